@@ -6,12 +6,64 @@ package ag
 
 var (
 	// debug is a global variable that indicates if the program is in debugging mode or not.
-	// In debugging mode the operators wait for the forward goroutine to finish.
+	// In debugging mode the operators are executed with the Sequential executor.
 	debug = false
+	// debugConfig holds the options of the debugging mode.
+	debugConfig debugOptions
 )
 
+// debugOptions are the options of the debugging mode, set with DebugOption.
+type debugOptions struct {
+	// detectAnomalies enables the detection of NaN and Inf values.
+	detectAnomalies bool
+	// timeStepHandler is used to resolve the time step of the operators.
+	timeStepHandler *TimeStepHandler
+}
+
+// DebugOption allows to configure the debugging mode (see SetDebugMode).
+type DebugOption func(*debugOptions)
+
+// WithAnomalyDetection enables the detection of NaN and Inf values in the
+// value of each operator, during the forward step, and in the gradients
+// propagated by each operator, during the backward step.
+//
+// On the first anomaly, the execution panics with an *Anomaly error,
+// describing the operator which produced it.
+func WithAnomalyDetection() DebugOption {
+	return func(o *debugOptions) {
+		o.detectAnomalies = true
+	}
+}
+
+// WithTimeStepHandler sets the TimeStepHandler used to resolve the time step
+// of the operators reported as anomalies (see WithAnomalyDetection).
+//
+// During a truncated backward step, the TimeStepHandler passed to BackwardT
+// takes precedence.
+func WithTimeStepHandler(tsh *TimeStepHandler) DebugOption {
+	return func(o *debugOptions) {
+		o.timeStepHandler = tsh
+	}
+}
+
 // SetDebugMode enables or disables the debugging mode.
-// In debugging mode the operators wait for the forward goroutine to finish.
-func SetDebugMode(d bool) {
+// In debugging mode the operators are executed with the Sequential executor,
+// regardless of the executor set globally or on the variables.
+//
+// Further checks can be enabled with the given options. They are reset when
+// the debugging mode is disabled.
+func SetDebugMode(d bool, opts ...DebugOption) {
 	debug = d
+	debugConfig = debugOptions{}
+	if !d {
+		return
+	}
+	for _, opt := range opts {
+		opt(&debugConfig)
+	}
+}
+
+// detectAnomalies reports whether the anomaly detection is enabled.
+func detectAnomalies() bool {
+	return debug && debugConfig.detectAnomalies
 }
@@ -0,0 +1,255 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package agtest provides utilities for testing the automatic
+// differentiation of spaGO functions and models.
+package agtest
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+)
+
+// T requires a subset of methods from testing.TB.
+type T interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Func is a function of nodes whose gradients are checked.
+type Func func(xs ...ag.Node) ag.Node
+
+// CheckGrad compares the gradients of the function f with respect to each
+// input, as computed by ag.Backward, against their numerical approximation
+// by central differences, using the default Checker.
+//
+// It returns an error describing the mismatching entries, or nil if all the
+// gradients are correct.
+func CheckGrad(f Func, xs ...mat.Matrix) error {
+	return Checker{}.Check(f, xs...).Err()
+}
+
+// CheckModelGrad is like CheckGrad, but the gradients are also checked with
+// respect to the parameters of the model m, which is expected to be used
+// by f (e.g. calling its Forward method).
+func CheckModelGrad(m nn.Model, f Func, xs ...mat.Matrix) error {
+	return Checker{}.CheckModel(m, f, xs...).Err()
+}
+
+// AssertGrad checks the gradients of f like CheckGrad; if they are not
+// correct, T.Errorf is called with the report of the mismatching entries.
+//
+// It returns whether the check succeeded.
+func AssertGrad(t T, f Func, xs ...mat.Matrix) bool {
+	t.Helper()
+	return assertReport(t, Checker{}.Check(f, xs...))
+}
+
+// AssertModelGrad checks the gradients of f like CheckModelGrad; if they
+// are not correct, T.Errorf is called with the report of the mismatching
+// entries.
+//
+// It returns whether the check succeeded.
+func AssertModelGrad(t T, m nn.Model, f Func, xs ...mat.Matrix) bool {
+	t.Helper()
+	return assertReport(t, Checker{}.CheckModel(m, f, xs...))
+}
+
+func assertReport(t T, r *Report) bool {
+	t.Helper()
+	if r.Failed() {
+		t.Errorf("%s", r)
+		return false
+	}
+	return true
+}
+
+// Checker compares the gradients computed by ag.Backward against their
+// numerical approximation by central differences.
+//
+// The output of the checked function is reduced to a scalar by a weighted
+// sum of its elements, with fixed weights, so that each element contributes
+// to the check.
+//
+// The zero value is ready to use, with a perturbation and a tolerance
+// suited to the precision (float32 or float64) of each matrix.
+type Checker struct {
+	// Epsilon is the perturbation applied to each entry. If zero, it is
+	// 1e-2 for float32 and 1e-6 for float64.
+	Epsilon float64
+	// Tolerance is the maximum difference allowed between the analytical
+	// and the numerical gradient, relative to their magnitude. If zero, it
+	// is 1e-2 for float32 and 1e-5 for float64.
+	Tolerance float64
+}
+
+// Check compares the gradients of the function f with respect to each
+// input.
+//
+// The inputs xs are not modified.
+func (c Checker) Check(f Func, xs ...mat.Matrix) *Report {
+	return c.check(nil, f, xs)
+}
+
+// CheckModel compares the gradients of the function f with respect to each
+// input and to each parameter of the model m which requires gradients.
+//
+// The parameters are temporarily perturbed, and restored afterwards. The
+// gradients of the model are zeroed.
+func (c Checker) CheckModel(m nn.Model, f Func, xs ...mat.Matrix) *Report {
+	return c.check(m, f, xs)
+}
+
+func (c Checker) check(m nn.Model, f Func, xs []mat.Matrix) *Report {
+	inputs := make([]mat.Matrix, len(xs))
+	targets := make([]*target, 0, len(xs))
+	for i, x := range xs {
+		inputs[i] = x.Clone()
+		targets = append(targets, &target{
+			name:  fmt.Sprintf("input %d", i),
+			value: inputs[i],
+		})
+	}
+
+	var params []nn.Param
+	if m != nil {
+		nn.ForEachParam(m, func(p nn.Param, name string, _ nn.ParamsType) {
+			if !p.RequiresGrad() {
+				return
+			}
+			if p.Name() != "" {
+				name = p.Name()
+			}
+			params = append(params, p)
+			targets = append(targets, &target{
+				name:  fmt.Sprintf("param %d (%s)", len(params)-1, name),
+				value: p.Value(),
+			})
+		})
+		nn.ZeroGrad(m)
+		defer nn.ZeroGrad(m)
+	}
+
+	// analytical gradients
+	nodes := make([]ag.Node, len(inputs))
+	for i, x := range inputs {
+		nodes[i] = ag.Var(x).WithGrad(true)
+	}
+	y := f(nodes...)
+	gy := outputWeights(y.Value())
+	if _, ok := y.(*ag.Operator); ok {
+		ag.Backward(y, gy)
+	} else {
+		y.AccGrad(gy) // e.g. f returns one of its inputs
+	}
+	for i, n := range nodes {
+		targets[i].grad = n.Grad()
+	}
+	for i, p := range params {
+		targets[len(nodes)+i].grad = p.Grad()
+	}
+
+	// numerical gradients
+	r := &Report{}
+	for _, t := range targets {
+		eps, tol := c.precision(t.value)
+		analytical := make([]float64, t.value.Size())
+		if t.grad != nil {
+			analytical = t.grad.Data().F64()
+		}
+		for k, a := range analytical {
+			n := t.numericalGrad(f, inputs, k, eps)
+			r.Checked++
+			if math.Abs(a-n) <= tol*(1+math.Max(math.Abs(a), math.Abs(n))) {
+				continue
+			}
+			r.Mismatches = append(r.Mismatches, Mismatch{
+				Target:     t.name,
+				Index:      k,
+				Row:        k / t.value.Columns(),
+				Col:        k % t.value.Columns(),
+				Analytical: a,
+				Numerical:  n,
+			})
+		}
+	}
+	return r
+}
+
+// precision returns the perturbation and the tolerance for the numerical
+// gradients of x.
+func (c Checker) precision(x mat.Matrix) (eps, tol float64) {
+	eps, tol = 1e-6, 1e-5
+	if x.Data().BitSize() == 32 {
+		eps, tol = 1e-2, 1e-2
+	}
+	if c.Epsilon != 0 {
+		eps = c.Epsilon
+	}
+	if c.Tolerance != 0 {
+		tol = c.Tolerance
+	}
+	return eps, tol
+}
+
+// target is a matrix, either an input or a parameter, whose gradients are
+// checked.
+type target struct {
+	name  string
+	value mat.Matrix
+	grad  mat.Matrix
+}
+
+// numericalGrad returns the central difference of the weighted sum of the
+// output of f, with respect to the entry k of the target.
+func (t *target) numericalGrad(f Func, inputs []mat.Matrix, k int, eps float64) float64 {
+	data := t.value.Data().F64()
+	original := data[k]
+	defer t.set(data, k, original)
+
+	h1 := t.set(data, k, original+eps)
+	y1 := weightedOutput(f, inputs)
+	h2 := t.set(data, k, original-eps)
+	y2 := weightedOutput(f, inputs)
+	return (y1 - y2) / (h1 - h2)
+}
+
+// set modifies the entry k of the target in place, and returns its actual
+// value, after rounding to the precision of the matrix.
+func (t *target) set(data []float64, k int, v float64) float64 {
+	data[k] = v
+	t.value.SetData(float.SliceInterface(data))
+	return t.value.Data().F64()[k]
+}
+
+// weightedOutput computes f in inference mode, and returns the weighted sum
+// of the output.
+func weightedOutput(f Func, inputs []mat.Matrix) float64 {
+	nodes := make([]ag.Node, len(inputs))
+	for i, x := range inputs {
+		nodes[i] = ag.Var(x).WithInference(true)
+	}
+	y := f(nodes...).Value()
+	w := outputWeights(y).Data().F64()
+	sum := 0.0
+	for i, v := range y.Data().F64() {
+		sum += w[i] * v
+	}
+	return sum
+}
+
+// outputWeights returns the fixed weights of the elements of the output y,
+// with the same shape of y.
+func outputWeights(y mat.Matrix) mat.Matrix {
+	w := make([]float64, y.Size())
+	for i := range w {
+		w[i] = math.Sin(float64(i) + 1)
+	}
+	return y.NewMatrix(y.Rows(), y.Columns(), float.SliceInterface(w))
+}
@@ -0,0 +1,239 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agtest
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/nn/linear"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckGrad(t *testing.T) {
+	t.Run("float32", testCheckGrad[float32])
+	t.Run("float64", testCheckGrad[float64])
+}
+
+func testCheckGrad[T float.DType](t *testing.T) {
+	x1 := mat.NewDense(2, 3, []T{0.1, -0.2, 0.3, 0.4, 0.5, -0.6})
+	x2 := mat.NewDense(2, 3, []T{0.7, 0.8, -0.9, 1.0, -1.1, 1.2})
+
+	// y = x1 * x2^2
+	forward := func(xs []mat.Matrix) mat.Matrix {
+		return xs[0].Prod(xs[1]).Prod(xs[1])
+	}
+
+	t.Run("correct gradients", func(t *testing.T) {
+		f := func(xs ...ag.Node) ag.Node {
+			return ag.Custom("Foo", forward, func(xs []mat.Matrix, y, gy mat.Matrix) []mat.Matrix {
+				return []mat.Matrix{
+					gy.Prod(xs[1]).Prod(xs[1]),
+					gy.Prod(xs[0]).Prod(xs[1]).ProdScalar(2),
+				}
+			}, xs...)
+		}
+		assert.NoError(t, CheckGrad(f, x1, x2))
+	})
+
+	t.Run("wrong gradients", func(t *testing.T) {
+		f := func(xs ...ag.Node) ag.Node {
+			return ag.Custom("Foo", forward, func(xs []mat.Matrix, y, gy mat.Matrix) []mat.Matrix {
+				return []mat.Matrix{
+					gy.Prod(xs[1]).Prod(xs[1]),
+					gy.Prod(xs[0]).Prod(xs[1]), // the factor 2 is missing
+				}
+			}, xs...)
+		}
+		err := CheckGrad(f, x1, x2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "6 mismatching gradients out of 12")
+		assert.Contains(t, err.Error(), "input 1, entry 0")
+		assert.NotContains(t, err.Error(), "input 0")
+	})
+
+	t.Run("built-in operators", func(t *testing.T) {
+		f := func(xs ...ag.Node) ag.Node {
+			return ag.Tanh(ag.Add(ag.Mul(xs[0], ag.T(xs[1])), ag.Exp(xs[2])))
+		}
+		assert.NoError(t, CheckGrad(f, x1, x2, mat.NewDense(2, 2, []T{0.1, 0.2, 0.3, 0.4})))
+	})
+
+	t.Run("broadcasting operators", func(t *testing.T) {
+		f := func(xs ...ag.Node) ag.Node {
+			return ag.Div(ag.Prod(ag.Sub(xs[0], xs[1]), xs[2]), ag.Add(xs[2], ag.BroadcastTo(xs[3], 2, 3)))
+		}
+		row := mat.NewDense(1, 3, []T{0.2, -0.1, 0.4})
+		col := mat.NewDense(2, 1, []T{0.5, -0.3})
+		assert.NoError(t, CheckGrad(f, x1, row, col, mat.NewScalar[T](2)))
+	})
+
+	t.Run("tensor operators", func(t *testing.T) {
+		f := func(xs ...ag.Node) ag.Node {
+			a := ag.NewTensor(xs[0], 3, 2, 1)
+			b := ag.NewTensor(xs[1], 2, 3)
+			y := ag.TensorProd(ag.TensorPermute(a, 1, 2, 0), ag.TensorMap(b, ag.Tanh))
+			return ag.TensorSumTo(ag.TensorDiv(y, ag.NewTensor(xs[2])), 3).Node()
+		}
+		assert.NoError(t, CheckGrad(f, x1, x2, mat.NewScalar[T](2)))
+	})
+
+	t.Run("indexing operators", func(t *testing.T) {
+		f := func(xs ...ag.Node) ag.Node {
+			rows := ag.IndexSelect(xs[0], 0, []int{1, 0, 1})
+			y := ag.ScatterAdd(xs[1], 1, []int{2, 0}, ag.IndexSelect(ag.Tanh(rows), 1, []int{1, 1}))
+			return ag.Gather(y, 1, []int{0, 2, 2})
+		}
+		assert.NoError(t, CheckGrad(f, x1, mat.NewDense(3, 3, []T{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9})))
+	})
+
+	t.Run("reduction operators", func(t *testing.T) {
+		f := func(xs ...ag.Node) ag.Node {
+			// a layer normalization along the rows
+			y := ag.Div(ag.Sub(xs[0], ag.MeanAxis(xs[0], 1)), ag.StdAxis(xs[0], 1))
+			z := ag.Add(ag.LogSumExpAxis(y, 0), ag.Add(ag.VarAxis(xs[0], 0), ag.MaxAxis(xs[0], 0)))
+			return ag.Add(z, ag.SumAxis(xs[0], 1))
+		}
+		assert.NoError(t, CheckGrad(f, x1))
+	})
+
+	t.Run("pooling operators", func(t *testing.T) {
+		f := func(xs ...ag.Node) ag.Node {
+			y := ag.Pooling(xs[0], fn.PoolingConfig{Mode: fn.AvgPool, Rows: 2, Columns: 2, ColumnStride: 1})
+			z := ag.Add(ag.AdaptivePooling(xs[0], fn.MaxPool, 1, 2), ag.AvgPooling(ag.T(xs[0]), 3, 1))
+			return ag.ReduceSum(ag.Square(ag.T(ag.Add(y, z))))
+		}
+		assert.NoError(t, CheckGrad(f, x1))
+	})
+
+	t.Run("linear algebra operators", func(t *testing.T) {
+		a := mat.NewDense(3, 3, []T{2.0, 0.4, -0.3, 0.4, 1.5, 0.2, -0.3, 0.2, 1.2})
+		b := mat.NewDense(3, 2, []T{0.1, 0.2, 0.3, -0.4, 0.5, -0.6})
+		f := func(xs ...ag.Node) ag.Node {
+			// the Cholesky decomposition reads the lower triangular part only
+			sym := ag.ProdScalar(ag.Add(xs[0], ag.T(xs[0])), ag.Var(xs[0].Value().NewScalar(0.5)))
+			l := ag.Cholesky(sym)
+			y := ag.Add(ag.Solve(l, xs[1]), ag.Mul(ag.Inverse(xs[0]), xs[1]))
+			return ag.Add(ag.ReduceSum(ag.Square(ag.Reshape(y, 6, 1))), ag.LogDet(xs[0]))
+		}
+		assert.NoError(t, CheckGrad(f, a, b))
+	})
+
+	t.Run("einsum", func(t *testing.T) {
+		a := mat.NewDense(2, 2, []T{0.5, -0.3, 0.8, 0.2})
+		f := func(xs ...ag.Node) ag.Node {
+			y := ag.Einsum("ij,kj,ik->", xs[0], xs[0], xs[1])
+			return ag.Add(y, ag.Einsum("ii,ij->", xs[1], ag.Tanh(xs[0])))
+		}
+		assert.NoError(t, CheckGrad(f, x1, a))
+	})
+
+	t.Run("identity", func(t *testing.T) {
+		assert.NoError(t, CheckGrad(func(xs ...ag.Node) ag.Node { return xs[0] }, x1))
+	})
+}
+
+func TestChecker_CheckModel(t *testing.T) {
+	t.Run("float32", testCheckerCheckModel[float32])
+	t.Run("float64", testCheckerCheckModel[float64])
+}
+
+func testCheckerCheckModel[T float.DType](t *testing.T) {
+	newModel := func() *linear.Model {
+		m := linear.New[T](3, 2)
+		mat.SetData[T](m.W.Value(), []T{0.1, -0.2, 0.3, 0.4, 0.5, -0.6})
+		mat.SetData[T](m.B.Value(), []T{0.7, -0.8})
+		return nn.Introspect(m)
+	}
+	x := mat.NewVecDense([]T{0.5, -0.4, 0.3})
+
+	t.Run("correct gradients", func(t *testing.T) {
+		m := newModel()
+		f := func(xs ...ag.Node) ag.Node {
+			return ag.Sigmoid(m.Forward(xs...)[0])
+		}
+		r := Checker{}.CheckModel(m, f, x)
+		assert.False(t, r.Failed())
+		assert.Equal(t, 3+6+2, r.Checked)
+		assert.NoError(t, r.Err())
+
+		// the parameters are restored, and their gradients zeroed
+		assert.InDeltaSlice(t, []T{0.1, -0.2, 0.3, 0.4, 0.5, -0.6}, m.W.Value().Data(), 1.0e-6)
+		assert.InDeltaSlice(t, []T{0.7, -0.8}, m.B.Value().Data(), 1.0e-6)
+		assert.Nil(t, m.W.Grad())
+		assert.Nil(t, m.B.Grad())
+	})
+
+	t.Run("frozen parameters are skipped", func(t *testing.T) {
+		m := newModel().WithBiasGrad(false)
+		f := func(xs ...ag.Node) ag.Node {
+			return m.Forward(xs...)[0]
+		}
+		r := Checker{}.CheckModel(m, f, x)
+		assert.False(t, r.Failed())
+		assert.Equal(t, 3+6, r.Checked)
+	})
+
+	t.Run("wrong gradients of a parameter", func(t *testing.T) {
+		m := newModel()
+		f := func(xs ...ag.Node) ag.Node {
+			// the gradients are propagated to the input only
+			return ag.Custom("WrongAdd", func(xs []mat.Matrix) mat.Matrix {
+				return xs[0].Add(xs[1])
+			}, func(_ []mat.Matrix, _, gy mat.Matrix) []mat.Matrix {
+				return []mat.Matrix{gy, nil}
+			}, ag.Mul(m.W, xs[0]), m.B)
+		}
+		r := Checker{}.CheckModel(m, f, x)
+		require.True(t, r.Failed())
+		require.Len(t, r.Mismatches, 2)
+		for i, mm := range r.Mismatches {
+			assert.Equal(t, "param 1 (B)", mm.Target)
+			assert.Equal(t, i, mm.Index)
+			assert.Equal(t, i, mm.Row)
+			assert.Equal(t, 0, mm.Col)
+			assert.Equal(t, 0.0, mm.Analytical)
+			assert.InDelta(t, math.Sin(float64(i)+1), mm.Numerical, 1.0e-2)
+		}
+		assert.Contains(t, r.String(), "2 mismatching gradients out of 11")
+		assert.Contains(t, r.String(), "param 1 (B), entry 1 [1, 0]")
+	})
+}
+
+func TestAssertGrad(t *testing.T) {
+	x := mat.NewVecDense([]float64{1, 2})
+
+	tt := &dummyT{}
+	assert.True(t, AssertGrad(tt, func(xs ...ag.Node) ag.Node { return ag.Square(xs[0]) }, x))
+	assert.Empty(t, tt.errors)
+
+	f := func(xs ...ag.Node) ag.Node {
+		return ag.Custom("WrongSquare", func(xs []mat.Matrix) mat.Matrix {
+			return xs[0].Prod(xs[0])
+		}, func(xs []mat.Matrix, _, gy mat.Matrix) []mat.Matrix {
+			return []mat.Matrix{gy.Prod(xs[0])}
+		}, xs...)
+	}
+	assert.False(t, AssertGrad(tt, f, x))
+	require.Len(t, tt.errors, 1)
+	assert.Contains(t, tt.errors[0], "2 mismatching gradients out of 2")
+}
+
+type dummyT struct {
+	errors []string
+}
+
+func (t *dummyT) Helper() {}
+
+func (t *dummyT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agtest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Report is the result of a gradients check.
+type Report struct {
+	// Checked is the number of entries whose gradients have been checked.
+	Checked int
+	// Mismatches are the entries whose analytical gradients differ from
+	// the numerical ones.
+	Mismatches []Mismatch
+}
+
+// Mismatch describes an entry whose analytical gradient differs from the
+// numerical one.
+type Mismatch struct {
+	// Target identifies the matrix, e.g. "input 0" or "param 1 (W)".
+	Target string
+	// Index is the position of the entry in the data of the matrix.
+	Index int
+	// Row and Col are the coordinates of the entry in the matrix.
+	Row, Col int
+	// Analytical is the gradient computed by ag.Backward.
+	Analytical float64
+	// Numerical is the gradient approximated by central differences.
+	Numerical float64
+}
+
+// Failed reports whether there are mismatching gradients.
+func (r *Report) Failed() bool {
+	return len(r.Mismatches) > 0
+}
+
+// Err returns an error describing the mismatching entries, or nil.
+func (r *Report) Err() error {
+	if !r.Failed() {
+		return nil
+	}
+	return errors.New(r.String())
+}
+
+// String returns a human-readable description of the report, listing the
+// mismatching entries.
+func (r *Report) String() string {
+	if !r.Failed() {
+		return fmt.Sprintf("agtest: %d gradients checked, no mismatches", r.Checked)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "agtest: %d mismatching gradients out of %d:", len(r.Mismatches), r.Checked)
+	for _, m := range r.Mismatches {
+		fmt.Fprintf(&b, "\n  %s, entry %d [%d, %d]: analytical %g, numerical %g, difference %g",
+			m.Target, m.Index, m.Row, m.Col, m.Analytical, m.Numerical, m.Analytical-m.Numerical)
+	}
+	return b.String()
+}
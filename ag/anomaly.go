@@ -0,0 +1,124 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Anomaly is the error reported by the anomaly detection, when a NaN or
+// Inf value is found (see WithAnomalyDetection).
+type Anomaly struct {
+	// Operator is the name of the operator which produced the anomaly.
+	Operator string
+	// Backward reports whether the anomaly was found in the gradients
+	// propagated by the operator, rather than in its value.
+	Backward bool
+	// Operand is the index of the operand whose gradients contain the
+	// anomaly. It is -1 if the anomaly was found in the forward step.
+	Operand int
+	// OperandShapes are the dimensions (rows, columns) of the values of the
+	// operands. The shape of a nil value is [0, 0].
+	OperandShapes [][2]int
+	// Index is the position of the first anomalous element in the data of
+	// the matrix.
+	Index int
+	// Value is the anomalous element, either NaN or ±Inf.
+	Value float64
+	// TimeStep is the time step of the operator, or -1 if it is not
+	// available (see WithTimeStepHandler).
+	TimeStep int
+}
+
+// Error returns a description of the anomaly.
+func (a *Anomaly) Error() string {
+	var b strings.Builder
+	if a.Backward {
+		fmt.Fprintf(&b, "ag: anomaly detected in the backward step of %s: gradients of operand %d", a.Operator, a.Operand)
+	} else {
+		fmt.Fprintf(&b, "ag: anomaly detected in the forward step of %s: value", a.Operator)
+	}
+	fmt.Fprintf(&b, " contains %g at index %d; operand shapes:", a.Value, a.Index)
+	for _, s := range a.OperandShapes {
+		fmt.Fprintf(&b, " [%d, %d]", s[0], s[1])
+	}
+	if a.TimeStep >= 0 {
+		fmt.Fprintf(&b, "; time step %d", a.TimeStep)
+	}
+	return b.String()
+}
+
+// checkForwardAnomaly panics with an *Anomaly if the value of the operator
+// contains NaN or Inf.
+func checkForwardAnomaly(o *Operator, value mat.Matrix) {
+	index, v, ok := findAnomaly(value)
+	if !ok {
+		return
+	}
+	panic(newAnomaly(o, debugConfig.timeStepHandler, -1, index, v))
+}
+
+// checkBackwardAnomaly panics with an *Anomaly if the gradients of any
+// operand contain NaN or Inf, after the backward step of the operator.
+//
+// Since the operators are executed sequentially in reverse topological
+// order, the first operator found is the one which produced the anomaly.
+func checkBackwardAnomaly(tsh *TimeStepHandler, o *Operator) {
+	if tsh == nil {
+		tsh = debugConfig.timeStepHandler
+	}
+	for i, operand := range o.Operands() {
+		var grad mat.Matrix
+		if oo, ok := operand.(*Operator); ok {
+			grad = oo.partialGrad()
+		} else {
+			grad = operand.Grad()
+		}
+		if index, v, ok := findAnomaly(grad); ok {
+			panic(newAnomaly(o, tsh, i, index, v))
+		}
+	}
+}
+
+func newAnomaly(o *Operator, tsh *TimeStepHandler, operand, index int, value float64) *Anomaly {
+	operands := o.Operands()
+	shapes := make([][2]int, len(operands))
+	for i, x := range operands {
+		if v := x.Value(); v != nil {
+			shapes[i] = [2]int{v.Rows(), v.Columns()}
+		}
+	}
+	timeStep := -1
+	if tsh != nil {
+		timeStep = NodeTimeStep(tsh, o)
+	}
+	return &Anomaly{
+		Operator:      o.Name(),
+		Backward:      operand >= 0,
+		Operand:       operand,
+		OperandShapes: shapes,
+		Index:         index,
+		Value:         value,
+		TimeStep:      timeStep,
+	}
+}
+
+// findAnomaly returns the index and the value of the first NaN or Inf
+// element of m, if any.
+func findAnomaly(m mat.Matrix) (int, float64, bool) {
+	if m == nil {
+		return 0, 0, false
+	}
+	for i, v := range m.Data().F64() {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return i, v, true
+		}
+	}
+	return 0, 0, false
+}
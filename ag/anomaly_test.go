@@ -0,0 +1,100 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnomalyDetection(t *testing.T) {
+	t.Run("float32", testAnomalyDetection[float32])
+	t.Run("float64", testAnomalyDetection[float64])
+}
+
+func testAnomalyDetection[T float.DType](t *testing.T) {
+	t.Run("forward", func(t *testing.T) {
+		SetDebugMode(true, WithAnomalyDetection())
+		defer SetDebugMode(false)
+
+		x := Var(mat.NewVecDense([]T{1, 0}))
+		a := recoverAnomaly(func() { Log(x) })
+		require.NotNil(t, a)
+		assert.Equal(t, "Log", a.Operator)
+		assert.False(t, a.Backward)
+		assert.Equal(t, -1, a.Operand)
+		assert.Equal(t, [][2]int{{2, 1}}, a.OperandShapes)
+		assert.Equal(t, 1, a.Index)
+		assert.True(t, math.IsInf(a.Value, -1))
+		assert.Equal(t, -1, a.TimeStep)
+		assert.Equal(t, "ag: anomaly detected in the forward step of Log: value contains -Inf at index 1; operand shapes: [2, 1]", a.Error())
+	})
+
+	t.Run("backward", func(t *testing.T) {
+		SetDebugMode(true, WithAnomalyDetection())
+		defer SetDebugMode(false)
+
+		x := Var(mat.NewVecDense([]T{1, 0})).WithGrad(true)
+		w := Var(mat.NewVecDense([]T{2, 3})).WithGrad(true)
+		y := ReduceSum(Prod(w, Sqrt(x)))
+
+		a := recoverAnomaly(func() { Backward(y) })
+		require.NotNil(t, a)
+		assert.Equal(t, "Sqrt", a.Operator)
+		assert.True(t, a.Backward)
+		assert.Equal(t, 0, a.Operand)
+		assert.Equal(t, [][2]int{{2, 1}}, a.OperandShapes)
+		assert.Equal(t, 1, a.Index)
+		assert.True(t, math.IsInf(a.Value, 1))
+		assert.Equal(t, "ag: anomaly detected in the backward step of Sqrt: gradients of operand 0 contains +Inf at index 1; operand shapes: [2, 1]", a.Error())
+	})
+
+	t.Run("time step", func(t *testing.T) {
+		tsh := NewTimeStepHandler()
+		SetDebugMode(true, WithAnomalyDetection(), WithTimeStepHandler(tsh))
+		defer SetDebugMode(false)
+
+		x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+		h := Tanh(x)
+		tsh.IncTimeStep()
+		h = Add(h, x)
+		tsh.IncTimeStep()
+		a := recoverAnomaly(func() { Log(Sub(h, h)) })
+		require.NotNil(t, a)
+		assert.Equal(t, "Log", a.Operator)
+		assert.Equal(t, 2, a.TimeStep)
+
+		y := Sqrt(Sub(h, h))
+		a = recoverAnomaly(func() { BackwardT(tsh, 2, y) })
+		require.NotNil(t, a)
+		assert.Equal(t, "Sqrt", a.Operator)
+		assert.Equal(t, 2, a.TimeStep)
+		assert.Contains(t, a.Error(), "; time step 2")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		SetDebugMode(true)
+		defer SetDebugMode(false)
+
+		x := Var(mat.NewVecDense([]T{1, 0})).WithGrad(true)
+		assert.NotPanics(t, func() { Backward(ReduceSum(Sqrt(Log(x)))) })
+	})
+}
+
+// recoverAnomaly calls f and returns the *Anomaly it panics with, if any.
+func recoverAnomaly(f func()) (a *Anomaly) {
+	defer func() {
+		if r := recover(); r != nil {
+			a = r.(*Anomaly)
+		}
+	}()
+	f()
+	return nil
+}
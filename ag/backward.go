@@ -109,9 +109,7 @@ func BackwardT(tsh *TimeStepHandler, backSteps int, x Node, grad ...mat.Matrix)
 	}
 	op.initOutputGrad(outputGrad)
 
-	wg := new(sync.WaitGroup)
-	backward(tsh, wg, op, stopAtTimeStep)
-	wg.Wait()
+	backward(tsh, []*Operator{op}, stopAtTimeStep)
 }
 
 // BackwardManyT starts a truncated backpropagation from a list of nodes.
@@ -140,11 +138,7 @@ func BackwardManyT(tsh *TimeStepHandler, backSteps int, xs ...Node) {
 		op.initOutputGrad(nil)
 	}
 
-	wg := new(sync.WaitGroup)
-	for _, op := range ops {
-		backward(tsh, wg, op, stopAtTimeStep)
-	}
-	wg.Wait()
+	backward(tsh, ops, stopAtTimeStep)
 }
 
 func setupOperatorForBackward(tsh *TimeStepHandler, op *Operator, stopAtTimeStep int) {
@@ -166,24 +160,65 @@ func setupOperatorForBackward(tsh *TimeStepHandler, op *Operator, stopAtTimeStep
 	}
 }
 
-func backward(tsh *TimeStepHandler, wg *sync.WaitGroup, op *Operator, stopAtTimeStep int) {
+// backward performs the backward step of the operators reachable from ops,
+// scheduling each of them with its own Executor, and waits for all of them
+// to complete.
+//
+// The operators are scheduled in reverse topological order, so that each
+// one comes after all the operators depending on it. This allows any
+// executor, even a sequential one, to propagate the gradients.
+func backward(tsh *TimeStepHandler, ops []*Operator, stopAtTimeStep int) {
+	var sorted []*Operator
+	for _, op := range ops {
+		sortForBackward(tsh, op, stopAtTimeStep, &sorted)
+	}
+
+	p := activeProfiler()
+	wg := new(sync.WaitGroup)
+	wg.Add(len(sorted))
+	for i := len(sorted) - 1; i >= 0; i-- {
+		op := sorted[i]
+		goProfiled(op.getExecutor(), op, p, func() {
+			op.backward(tsh, stopAtTimeStep, p)
+			if detectAnomalies() {
+				checkBackwardAnomaly(tsh, op)
+			}
+			op.backwardState = idle
+			wg.Done()
+		})
+	}
+	wg.Wait()
+}
+
+// backwardFrom performs the backward step from the operators ops, given
+// their output gradients, which are accumulated like any other gradients.
+func backwardFrom(tsh *TimeStepHandler, stopAtTimeStep int, ops []*Operator, grads []mat.Matrix) {
+	roots := make([]*Operator, 0, len(ops))
+	for i, op := range ops {
+		if !op.RequiresGrad() || timeStepTruncation(tsh, op, stopAtTimeStep) {
+			continue
+		}
+		setupOperatorForBackward(tsh, op, stopAtTimeStep)
+		op.AccGrad(grads[i])
+		roots = append(roots, op)
+	}
+	backward(tsh, roots, stopAtTimeStep)
+}
+
+// sortForBackward appends to sorted the operators pending for the backward
+// step, in topological order.
+func sortForBackward(tsh *TimeStepHandler, op *Operator, stopAtTimeStep int, sorted *[]*Operator) {
 	if !op.RequiresGrad() || op.backwardState != pending || timeStepTruncation(tsh, op, stopAtTimeStep) {
 		return
 	}
 	op.backwardState = ongoing
 
-	wg.Add(1)
-	go func() {
-		op.backward()
-		op.backwardState = idle
-		wg.Done()
-	}()
-
 	for _, operand := range op.Operands() {
 		if oo, ok := operand.(*Operator); ok {
-			backward(tsh, wg, oo, stopAtTimeStep)
+			sortForBackward(tsh, oo, stopAtTimeStep, sorted)
 		}
 	}
+	*sorted = append(*sorted, op)
 }
 
 func timeStepTruncation(tsh *TimeStepHandler, op *Operator, stopAtTimeStep int) bool {
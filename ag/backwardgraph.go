@@ -0,0 +1,160 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// GradNodeAccumulator is implemented by nodes which can accumulate their
+// gradients expressed as nodes of the graph, as computed by BackwardGraph.
+type GradNodeAccumulator interface {
+	// AccGradNode accumulates the gradients, expressed as a Node, into the
+	// node. The value of gx is also accumulated as with Node.AccGrad.
+	AccGradNode(gx Node)
+	// GradNode returns the gradients accumulated with AccGradNode, or nil.
+	GradNode() Node
+}
+
+// GradNode returns the gradients of x, expressed as a Node, accumulated
+// during BackwardGraph.
+//
+// It returns nil if x has no such gradients or does not implement
+// GradNodeAccumulator.
+func GradNode(x Node) Node {
+	if a, ok := x.(GradNodeAccumulator); ok {
+		return a.GradNode()
+	}
+	return nil
+}
+
+// BackwardGraph starts the back-propagation from the node, like Backward,
+// but the gradients are computed as new nodes of the graph, so that they can
+// be involved in further operations and differentiated in turn (e.g. for
+// gradient penalties or Hessian-vector products).
+//
+// If the output gradients are not passed, they are set to a matrix of ones
+// (dy/dy = 1).
+//
+// The gradients are accumulated into each node implementing
+// GradNodeAccumulator (e.g. Variable), and can be retrieved with GradNode.
+// The intermediate operators are not affected.
+//
+// It panics if a function of the graph does not implement fn.GraphFunction.
+//
+// It returns a ReleaseGraphFunc which releases both the graph of x and the
+// nodes created for the gradients.
+func BackwardGraph(x Node, grad ...Node) ReleaseGraphFunc {
+	if len(grad) > 1 {
+		panic("ag: only none or one gradients node must be passed to BackwardGraph")
+	}
+	var outputGrad Node
+	if len(grad) > 0 && grad[0] != nil {
+		outputGrad = grad[0]
+	} else {
+		outputGrad = Var(x.Value().OnesLike())
+	}
+
+	grads := backwardGraph([]Node{x}, []Node{outputGrad})
+
+	gradNodes := make([]Node, 0, len(grads))
+	for n, gn := range grads {
+		if a, ok := n.(GradNodeAccumulator); ok {
+			a.AccGradNode(gn)
+		}
+		gradNodes = append(gradNodes, gn)
+	}
+
+	return func() {
+		ReleaseGraph(x)
+		ReleaseGraph(gradNodes...)
+	}
+}
+
+// backwardGraph visits the operators reachable from xs in reverse
+// topological order, building the gradients of each node as new nodes of
+// the graph, given the output gradients of xs.
+func backwardGraph(xs []Node, outputGrads []Node) map[Node]Node {
+	grads := make(map[Node]Node)
+	for i, x := range xs {
+		if x.RequiresGrad() {
+			accGradNode(grads, x, outputGrads[i])
+		}
+	}
+
+	ops := topologicalOrder(xs)
+	for i := len(ops) - 1; i >= 0; i-- {
+		op := ops[i]
+		gy, ok := grads[op]
+		if !ok {
+			continue
+		}
+		f, ok := op.function.(fn.GraphFunction[Node])
+		if !ok {
+			panic(fmt.Sprintf("ag: function %s does not support graph-based backward", op.Name()))
+		}
+		gxs := f.BackwardGraph(graphBuilder{}, gy)
+		for j, operand := range op.Operands() {
+			if gxs[j] == nil || !operand.RequiresGrad() {
+				continue
+			}
+			accGradNode(grads, operand, gxs[j])
+		}
+	}
+	return grads
+}
+
+// topologicalOrder returns the operators reachable from xs which require
+// gradients, sorted so that each operator comes after its operands.
+func topologicalOrder(xs []Node) []*Operator {
+	var ops []*Operator
+	visited := make(map[*Operator]struct{})
+
+	var visit func(n Node)
+	visit = func(n Node) {
+		op, ok := n.(*Operator)
+		if !ok || !op.RequiresGrad() {
+			return
+		}
+		if _, ok := visited[op]; ok {
+			return
+		}
+		visited[op] = struct{}{}
+		for _, operand := range op.Operands() {
+			visit(operand)
+		}
+		ops = append(ops, op)
+	}
+
+	for _, x := range xs {
+		visit(x)
+	}
+	return ops
+}
+
+func accGradNode(grads map[Node]Node, x, gx Node) {
+	if prev, ok := grads[x]; ok {
+		grads[x] = Add(prev, gx)
+		return
+	}
+	grads[x] = gx
+}
+
+// graphBuilder satisfies fn.Graph, allowing the functions to create the new
+// nodes of their backward pass.
+type graphBuilder struct{}
+
+// NewOperator creates a new Operator.
+func (graphBuilder) NewOperator(f fn.Function[Node]) Node {
+	return NewOperator(f)
+}
+
+// NewConstant creates a new Variable which does not require gradients.
+func (graphBuilder) NewConstant(value mat.Matrix) Node {
+	return Var(value)
+}
@@ -0,0 +1,144 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackwardGraph(t *testing.T) {
+	t.Run("float32", testBackwardGraph[float32])
+	t.Run("float64", testBackwardGraph[float64])
+}
+
+func testBackwardGraph[T float.DType](t *testing.T) {
+	t.Run("second order derivative", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{0.5, -1, 2})).WithGrad(true)
+		y := ReduceSum(Pow(x, 3))
+
+		release := BackwardGraph(y)
+		defer release()
+
+		gx := GradNode(x)
+		require.NotNil(t, gx)
+		assert.True(t, gx.RequiresGrad())
+		assert.InDeltaSlice(t, []T{0.75, 3, 12}, gx.Value().Data(), 1.0e-6)
+		assert.InDeltaSlice(t, []T{0.75, 3, 12}, x.Grad().Data(), 1.0e-6)
+
+		x.ZeroGrad()
+		assert.Nil(t, GradNode(x))
+
+		Backward(ReduceSum(gx))
+		assert.InDeltaSlice(t, []T{3, -6, 12}, x.Grad().Data(), 1.0e-6)
+	})
+
+	t.Run("Hessian-vector product", func(t *testing.T) {
+		// f(x) = sum(x1 * x2 * tanh(x1))
+		x1 := Var(mat.NewVecDense([]T{0.1, -0.3})).WithGrad(true)
+		x2 := Var(mat.NewVecDense([]T{0.4, 0.2})).WithGrad(true)
+		y := ReduceSum(Prod(Prod(x1, x2), Tanh(x1)))
+
+		BackwardGraph(y)
+		g1, g2 := GradNode(x1), GradNode(x2)
+		require.NotNil(t, g1)
+		require.NotNil(t, g2)
+
+		// Hessian-vector product with v = (v1, v2)
+		v1 := Var(mat.NewVecDense([]T{1, 0.5}))
+		v2 := Var(mat.NewVecDense([]T{-1, 2}))
+		x1.ZeroGrad()
+		x2.ZeroGrad()
+		Backward(Add(Dot(g1, v1), Dot(g2, v2)))
+
+		// d²f/dx1² = x2 * (2 sech²(x1) - 2 x1 tanh(x1) sech²(x1))
+		// d²f/dx1dx2 = tanh(x1) + x1 sech²(x1)
+		// d²f/dx2² = 0
+		expected1 := make([]T, 2)
+		expected2 := make([]T, 2)
+		for i, x1v := range []float64{0.1, -0.3} {
+			x2v := []float64{0.4, 0.2}[i]
+			th := math.Tanh(x1v)
+			sech2 := 1 - th*th
+			h11 := x2v * (2*sech2 - 2*x1v*th*sech2)
+			h12 := th + x1v*sech2
+			expected1[i] = T(h11*[]float64{1, 0.5}[i] + h12*[]float64{-1, 2}[i])
+			expected2[i] = T(h12 * []float64{1, 0.5}[i])
+		}
+		assert.InDeltaSlice(t, expected1, x1.Grad().Data(), 1.0e-5)
+		assert.InDeltaSlice(t, expected2, x2.Grad().Data(), 1.0e-5)
+	})
+
+	t.Run("output gradients", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+		y := Square(x)
+
+		BackwardGraph(y, Var(mat.NewVecDense([]T{0.5, -1})))
+		assert.InDeltaSlice(t, []T{1, -4}, GradNode(x).Value().Data(), 1.0e-6)
+	})
+
+	t.Run("constants are not affected", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+		c := Var(mat.NewVecDense([]T{3, 4}))
+		BackwardGraph(ReduceSum(Prod(x, c)))
+		assert.InDeltaSlice(t, []T{3, 4}, GradNode(x).Value().Data(), 1.0e-6)
+		assert.Nil(t, GradNode(c))
+		assert.Nil(t, c.Grad())
+	})
+
+	t.Run("entmax Hessian-vector product", func(t *testing.T) {
+		c := Var(mat.NewVecDense([]T{0.3, -0.5, 0.9, 0.2}))
+		v := mat.NewVecDense([]T{1, -0.5, 0.5, 2})
+		grad := func(xv mat.Matrix) mat.Matrix {
+			x := Var(xv).WithGrad(true)
+			Backward(Dot(Entmax(x, 1.5), c))
+			return x.Grad().Clone()
+		}
+
+		x := Var(mat.NewVecDense([]T{0.8, 0.6, -0.3, 0.1})).WithGrad(true)
+		BackwardGraph(Dot(Entmax(x, 1.5), c))
+		gx := GradNode(x)
+		require.NotNil(t, gx)
+		x.ZeroGrad()
+		Backward(Dot(gx, Var(v)))
+
+		// central differences of the gradients along v
+		const h = 1.0e-3
+		xv := x.Value()
+		expected := grad(xv.Add(v.ProdScalar(h))).Sub(grad(xv.Sub(v.ProdScalar(h)))).ProdScalar(1 / (2 * h))
+		assert.InDeltaSlice(t, expected.Data().F64(), x.Grad().Data().F64(), 1.0e-2)
+	})
+
+	t.Run("custom functions", func(t *testing.T) {
+		cube := func(xs []mat.Matrix) mat.Matrix {
+			return xs[0].Pow(3)
+		}
+		cubeGrad := func(xs []mat.Matrix, _, gy mat.Matrix) []mat.Matrix {
+			return []mat.Matrix{xs[0].Pow(2).ProdScalar(3).Prod(gy)}
+		}
+		cubeGradGraph := func(_ fn.Graph[Node], xs []Node, gy Node) []Node {
+			return []Node{Prod(ProdScalar(Square(xs[0]), Scalar(T(3))), gy)}
+		}
+
+		x := Var(mat.NewVecDense([]T{0.5, -1, 2})).WithGrad(true)
+		assert.Panics(t, func() { BackwardGraph(ReduceSum(Custom("cube", cube, cubeGrad, x))) })
+
+		y := ReduceSum(NewOperator(fn.NewCustom[Node]("cube", cube, cubeGrad, x).WithBackwardGraph(cubeGradGraph)))
+		BackwardGraph(y)
+		gx := GradNode(x)
+		require.NotNil(t, gx)
+		assert.InDeltaSlice(t, []T{0.75, 3, 12}, gx.Value().Data(), 1.0e-6)
+
+		x.ZeroGrad()
+		Backward(ReduceSum(gx))
+		assert.InDeltaSlice(t, []T{3, -6, 12}, x.Grad().Data(), 1.0e-6)
+	})
+}
@@ -0,0 +1,194 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// Checkpoint performs gradient checkpointing on the sub-computation f,
+// trading compute for memory.
+//
+// The function f is executed on the input nodes xs, but its intermediate
+// operators are released as soon as the values of the outputs are
+// available. During the backward step, f is executed once again, and the
+// gradients of all the outputs are propagated through the recomputed
+// operators, both to xs and to any parameter used by f.
+//
+// Since f is executed more than once, it must be deterministic, and depend
+// only on xs and on nodes which are not Operators (e.g. model parameters).
+// Its signature matches the Forward method of nn.StandardModel.
+//
+// The outputs of Checkpoint are regular operators, created at the current
+// time step, so they can be used with truncated backpropagation (see
+// BackwardT): the recomputed operators belong to the same time step. The
+// gradients can not be computed with BackwardGraph through a checkpoint.
+//
+// In inference mode, either global or inherited from xs (see NoGrad), f is
+// simply executed on xs.
+func Checkpoint(f func(xs ...Node) []Node, xs ...Node) []Node {
+	if InferenceMode() || inheritedInferenceMode(xs) {
+		return f(xs...)
+	}
+	c := &checkpoint{f: f, xs: xs}
+
+	outputs := f(c.newInputs(false)...)
+	if len(outputs) == 0 {
+		return outputs
+	}
+	values := CopyValues(outputs)
+	// The outputs may require gradients because of the parameters used
+	// by f, even if the inputs do not.
+	requiresGrad := make([]bool, len(outputs))
+	for i, y := range outputs {
+		requiresGrad[i] = y.RequiresGrad()
+	}
+	ReleaseGraph(outputs...)
+
+	var data []float64
+	c.shapes = make([][2]int, len(values))
+	for i, v := range values {
+		c.shapes[i] = [2]int{v.Rows(), v.Columns()}
+		data = append(data, v.Data().F64()...)
+	}
+	c.value = values[0].NewMatrix(len(data), 1, float.SliceInterface(data))
+
+	joint := NewOperator(c).(*Operator)
+	joint.requiresGrad = 0
+	c.createdAt = joint.createdAt
+	ys := make([]Node, len(values))
+	offset := 0
+	for i, v := range values {
+		op := NewOperator(&checkpointOutput{joint: joint, offset: offset, value: v}).(*Operator)
+		if requiresGrad[i] {
+			joint.requiresGrad = 1
+			op.requiresGrad = 1
+		} else {
+			op.requiresGrad = 0
+		}
+		ys[i] = op
+		offset += v.Size()
+	}
+	return ys
+}
+
+var _ fn.Function[Node] = &checkpoint{}
+
+// checkpoint is the Function of the operator joining the outputs of the
+// sub-computation wrapped by Checkpoint.
+//
+// Its value is the concatenation of the flattened outputs, so that the
+// gradients of all the outputs are accumulated into a single operator, and
+// f is recomputed only once by its backward step.
+type checkpoint struct {
+	f         func(xs ...Node) []Node
+	xs        []Node
+	shapes    [][2]int   // the shapes of the outputs
+	value     mat.Matrix // the concatenated outputs
+	createdAt uint64     // the creation timestamp of the operator
+}
+
+// newInputs returns new Variables holding the values of the inputs, which
+// are used to isolate the operators of f from the rest of the graph.
+func (c *checkpoint) newInputs(requiresGrad bool) []Node {
+	inputs := make([]Node, len(c.xs))
+	for i, x := range c.xs {
+		inputs[i] = Var(x.Value()).WithGrad(requiresGrad && x.RequiresGrad())
+	}
+	return inputs
+}
+
+// Operands returns the list of operands.
+func (c *checkpoint) Operands() []Node {
+	return c.xs
+}
+
+// Forward returns the concatenated outputs of the checkpoint.
+func (c *checkpoint) Forward() mat.Matrix {
+	return c.value
+}
+
+// Backward recomputes the operators of the checkpoint, and propagates the
+// gradients through them.
+func (c *checkpoint) Backward(gy mat.Matrix) {
+	c.backwardT(nil, -1, gy, nil)
+}
+
+// backwardT recomputes the operators of the checkpoint, and propagates the
+// gradients through them, with the truncation of the outer backward step.
+// The gradients of the leaves are collected by gc, if not nil (see Grad).
+func (c *checkpoint) backwardT(tsh *TimeStepHandler, stopAtTimeStep int, gy mat.Matrix, gc *gradCapture) {
+	inputs := c.newInputs(true)
+	outputs := c.f(inputs...)
+	defer ReleaseGraph(outputs...)
+	if len(outputs) != len(c.shapes) {
+		panic("ag: the checkpoint function returned a different number of outputs")
+	}
+
+	// The recomputed operators belong to the time step of the checkpoint,
+	// and to the same call to Grad, if any.
+	for _, op := range topologicalOrder(outputs) {
+		op.createdAt = c.createdAt
+		op.gradCapture = gc
+	}
+
+	gyData := gy.Data().F64()
+	var roots []*Operator
+	var rootGrads []mat.Matrix
+	offset := 0
+	for i, y := range outputs {
+		rows, cols := c.shapes[i][0], c.shapes[i][1]
+		g := y.Value().NewMatrix(rows, cols, float.SliceInterface(gyData[offset:offset+rows*cols]))
+		defer mat.ReleaseMatrix(g)
+		offset += rows * cols
+		if op, ok := y.(*Operator); ok {
+			roots = append(roots, op)
+			rootGrads = append(rootGrads, g)
+			continue
+		}
+		gc.accGrad(y, g) // e.g. f returns one of its inputs
+	}
+	backwardFrom(tsh, stopAtTimeStep, roots, rootGrads)
+
+	// The gradients are accumulated even if they are nil (i.e. f does not
+	// use the input), since an operator expects them from all of its
+	// consumers to complete its own backward step.
+	for i, x := range c.xs {
+		gc.accGrad(x, gc.grad(inputs[i]))
+	}
+}
+
+var _ fn.Function[Node] = &checkpointOutput{}
+
+// checkpointOutput is the Function providing one of the outputs of a
+// checkpoint.
+type checkpointOutput struct {
+	joint  *Operator // the operator of the checkpoint
+	offset int       // the offset of the output in the value of joint
+	value  mat.Matrix
+}
+
+// Operands returns the list of operands.
+func (r *checkpointOutput) Operands() []Node {
+	return []Node{r.joint}
+}
+
+// Forward returns the value computed by the checkpoint.
+func (r *checkpointOutput) Forward() mat.Matrix {
+	return r.value
+}
+
+// Backward accumulates the gradients into the operator of the checkpoint,
+// at the offset of the output.
+func (r *checkpointOutput) Backward(gy mat.Matrix) {
+	gx := r.joint.Value().ZerosLike()
+	defer mat.ReleaseMatrix(gx)
+	for i, v := range gy.Data().F64() {
+		gx.SetVecScalar(r.offset+i, float.Interface(v))
+	}
+	r.joint.AccGrad(gx)
+}
@@ -0,0 +1,157 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint(t *testing.T) {
+	t.Run("float32", testCheckpoint[float32])
+	t.Run("float64", testCheckpoint[float64])
+}
+
+func testCheckpoint[T float.DType](t *testing.T) {
+	newParams := func() (w, b Node) {
+		w = Var(mat.NewDense(2, 3, []T{0.4, 0.5, -0.6, 0.7, -0.8, 0.9})).WithGrad(true)
+		b = Var(mat.NewVecDense([]T{0.1, -0.2})).WithGrad(true)
+		return
+	}
+
+	t.Run("gradients are equal to the ones without checkpoint", func(t *testing.T) {
+		run := func(checkpoint bool) (gw, gb, gx2 mat.Matrix, calls int64) {
+			w, b := newParams()
+			x1 := Var(mat.NewVecDense([]T{0.1, 0.2, -0.3}))
+			x2 := Var(mat.NewVecDense([]T{0.3, -0.1})).WithGrad(true)
+
+			f := func(xs ...Node) []Node {
+				atomic.AddInt64(&calls, 1)
+				h := Tanh(Affine(b, w, xs[0]))
+				return []Node{Prod(h, xs[1]), ReduceSum(Square(h))}
+			}
+
+			var ys []Node
+			if checkpoint {
+				ys = Checkpoint(f, x1, x2)
+			} else {
+				ys = f(x1, x2)
+			}
+			require.Len(t, ys, 2)
+			assert.True(t, ys[0].RequiresGrad())
+			assert.True(t, ys[1].RequiresGrad())
+
+			Backward(Add(ReduceSum(ys[0]), ys[1]))
+			return w.Grad(), b.Grad(), x2.Grad(), atomic.LoadInt64(&calls)
+		}
+
+		gw, gb, gx2, calls := run(false)
+		assert.Equal(t, int64(1), calls)
+
+		cgw, cgb, cgx2, cCalls := run(true)
+		assert.Equal(t, int64(2), cCalls, "forward and a single recomputation")
+
+		assert.InDeltaSlice(t, gw.Data(), cgw.Data(), 1.0e-6)
+		assert.InDeltaSlice(t, gb.Data(), cgb.Data(), 1.0e-6)
+		assert.InDeltaSlice(t, gx2.Data(), cgx2.Data(), 1.0e-6)
+	})
+
+	t.Run("the outputs do not depend on the operators of the function", func(t *testing.T) {
+		w, b := newParams()
+		x := Var(mat.NewVecDense([]T{0.1, 0.2, -0.3}))
+		ys := Checkpoint(func(xs ...Node) []Node {
+			return []Node{Sigmoid(Affine(b, w, xs[0]))}
+		}, x)
+		require.Len(t, ys, 1)
+		op := ys[0].(*Operator)
+		require.Len(t, op.Operands(), 1)
+		joint := op.Operands()[0].(*Operator)
+		assert.Equal(t, []Node{x}, joint.Operands())
+	})
+
+	t.Run("truncated backpropagation", func(t *testing.T) {
+		run := func(checkpoint bool) (gw, gb mat.Matrix) {
+			w, b := newParams()
+			step := func(xs ...Node) []Node {
+				return []Node{Tanh(Add(Affine(b, w, xs[0]), xs[1]))}
+			}
+
+			tsh := NewTimeStepHandler()
+			var h Node = Var(mat.NewVecDense([]T{0, 0}))
+			for _, v := range [][]T{{0.1, 0.2, -0.3}, {-0.4, 0.5, 0.6}, {0.7, -0.8, 0.9}} {
+				tsh.IncTimeStep()
+				x := Var(mat.NewVecDense(v))
+				if checkpoint {
+					h = Checkpoint(step, x, h)[0]
+				} else {
+					h = step(x, h)[0]
+				}
+			}
+			BackwardT(tsh, 1, ReduceSum(h))
+			return w.Grad(), b.Grad()
+		}
+
+		gw, gb := run(false)
+		cgw, cgb := run(true)
+		assert.InDeltaSlice(t, gw.Data(), cgw.Data(), 1.0e-6)
+		assert.InDeltaSlice(t, gb.Data(), cgb.Data(), 1.0e-6)
+	})
+
+	t.Run("Grad does not accumulate into the parameters", func(t *testing.T) {
+		w, b := newParams()
+		x := Var(mat.NewVecDense([]T{0.1, 0.2, -0.3})).WithGrad(true)
+		f := func(xs ...Node) []Node {
+			return []Node{Tanh(Affine(b, w, xs[0]))}
+		}
+		y := ReduceSum(Checkpoint(f, Sin(x))[0])
+
+		gs := Grad([]Node{y}, nil, []Node{w, b, x})
+		assert.Nil(t, w.Grad())
+		assert.Nil(t, b.Grad())
+		assert.Nil(t, x.Grad())
+
+		Backward(y)
+		assert.InDeltaSlice(t, w.Grad().Data(), gs[0].Data(), 1.0e-6)
+		assert.InDeltaSlice(t, b.Grad().Data(), gs[1].Data(), 1.0e-6)
+		assert.InDeltaSlice(t, x.Grad().Data(), gs[2].Data(), 1.0e-6)
+	})
+
+	t.Run("operator inputs not used by the function", func(t *testing.T) {
+		w := Var(mat.NewVecDense([]T{0.5, -0.2})).WithGrad(true)
+		x := Var(mat.NewVecDense([]T{0.1, 0.2})).WithGrad(true)
+		ys := Checkpoint(func(xs ...Node) []Node {
+			return []Node{Prod(w, w)}
+		}, Exp(x))
+		require.Len(t, ys, 1)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			Backward(ReduceSum(ys[0]))
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the backward step did not complete")
+		}
+		assert.InDeltaSlice(t, []T{1, -0.4}, w.Grad().Data(), 1.0e-6)
+		assert.Nil(t, x.Grad())
+	})
+
+	t.Run("inference mode", func(t *testing.T) {
+		w, b := newParams()
+		ys := Checkpoint(func(xs ...Node) []Node {
+			return []Node{Affine(b, w, xs[0])}
+		}, NoGrad(Var(mat.NewVecDense([]T{0.1, 0.2, -0.3})))...)
+		require.Len(t, ys, 1)
+		assert.IsType(t, &Result{}, ys[0])
+	})
+}
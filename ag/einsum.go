@@ -0,0 +1,298 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// Einsum returns a new node with the Einstein summation of the nodes xs,
+// as specified by spec, following the NumPy notation: each operand has a
+// term with a letter for each of its dimensions, and the letters after the
+// arrow are the dimensions of the result. For instance, "ij,jk->ik" is the
+// matrix multiplication, "i,ij,j->" is a bilinear form, "ij->ji" is the
+// transpose, and "ii->" is the trace. If the arrow is omitted, the result
+// has the letters appearing only once, in alphabetical order.
+//
+// The values of the nodes are matrices for terms of two letters, vectors for
+// terms of one letter, and scalars for empty terms. Likewise, the result is
+// a matrix, a column vector, or a scalar. Use TensorEinsum for tensors of
+// higher rank.
+func Einsum(spec string, xs ...Node) Node {
+	inputs, _ := parseEinsumSpec(spec, len(xs))
+	ts := make([]Tensor, len(xs))
+	for i, x := range xs {
+		rows, cols := x.Value().Dims()
+		switch len(inputs[i]) {
+		case 0:
+			ts[i] = NewTensor(x)
+		case 1:
+			if rows != 1 && cols != 1 {
+				panic(fmt.Sprintf("ag: einsum term %q requires a vector, found a %d×%d matrix", inputs[i], rows, cols))
+			}
+			ts[i] = NewTensor(x, x.Value().Size())
+		case 2:
+			ts[i] = NewTensor(x, rows, cols)
+		default:
+			panic(fmt.Sprintf("ag: einsum term %q has more than two dimensions (see TensorEinsum)", inputs[i]))
+		}
+	}
+	y := TensorEinsum(spec, ts...)
+	return y.matrixNode(y.shape)
+}
+
+// TensorEinsum returns a new tensor with the Einstein summation of the
+// tensors xs, as specified by spec (see Einsum). For instance,
+// "bij,bjk->bik" is the batched matrix multiplication.
+//
+// The dimensions appearing in a single tensor, and not in the result, are
+// summed first. Then, the tensors are contracted two at a time, choosing
+// each time the pair with the smallest result, and each contraction is
+// computed as a single batched matrix multiplication (see TensorMatMul).
+// The result is therefore differentiable with respect to all the tensors.
+func TensorEinsum(spec string, xs ...Tensor) Tensor {
+	inputs, output := parseEinsumSpec(spec, len(xs))
+	sizes := make(map[byte]int)
+	ops := make([]einsumOperand, len(xs))
+	for i, x := range xs {
+		if len(inputs[i]) != len(x.shape) {
+			panic(fmt.Sprintf("ag: einsum term %q does not match a tensor of rank %d", inputs[i], len(x.shape)))
+		}
+		for j, d := range x.shape {
+			c := inputs[i][j]
+			if size, ok := sizes[c]; ok && size != d {
+				panic(fmt.Sprintf("ag: einsum index %q has inconsistent sizes %d and %d", c, size, d))
+			}
+			sizes[c] = d
+		}
+		ops[i] = einsumOperand{t: x, indices: inputs[i]}.diagonal()
+	}
+	for i := 0; i < len(output); i++ {
+		if _, ok := sizes[output[i]]; !ok {
+			panic(fmt.Sprintf("ag: einsum output index %q is not in the inputs", output[i]))
+		}
+	}
+
+	for i := range ops {
+		ops[i] = ops[i].sum(einsumKept(ops, output, i, i))
+	}
+	for len(ops) > 1 {
+		i, j := einsumPair(ops, output, sizes)
+		ops[i] = ops[i].contract(ops[j], einsumKept(ops, output, i, j))
+		ops = append(ops[:j], ops[j+1:]...)
+	}
+	return ops[0].sum(output).permute(output).t
+}
+
+// parseEinsumSpec returns the terms of the n operands and the term of the
+// result of an einsum spec.
+func parseEinsumSpec(spec string, n int) (inputs []string, output string) {
+	s := strings.ReplaceAll(spec, " ", "")
+	lhs, rhs, explicit := strings.Cut(s, "->")
+	inputs = strings.Split(lhs, ",")
+	if len(inputs) != n {
+		panic(fmt.Sprintf("ag: einsum spec %q requires %d operands, found %d", spec, len(inputs), n))
+	}
+	counts := make(map[byte]int)
+	for _, term := range append(inputs, rhs) {
+		for i := 0; i < len(term); i++ {
+			if c := term[i]; !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+				panic(fmt.Sprintf("ag: invalid einsum spec %q", spec))
+			}
+		}
+	}
+	for _, term := range inputs {
+		for i := 0; i < len(term); i++ {
+			counts[term[i]]++
+		}
+	}
+	if explicit {
+		for i := 0; i < len(rhs); i++ {
+			if strings.IndexByte(rhs[:i], rhs[i]) != -1 {
+				panic(fmt.Sprintf("ag: einsum output index %q is repeated", rhs[i]))
+			}
+		}
+		return inputs, rhs
+	}
+	var implicit []byte
+	for c, count := range counts {
+		if count == 1 {
+			implicit = append(implicit, c)
+		}
+	}
+	sort.Slice(implicit, func(i, j int) bool { return implicit[i] < implicit[j] })
+	return inputs, string(implicit)
+}
+
+// einsumKept returns the indices which must be kept after a contraction of
+// the operands i and j (the same for a single operand): the indices of the
+// result, and the indices of the other operands.
+func einsumKept(ops []einsumOperand, output string, i, j int) string {
+	kept := output
+	for k, op := range ops {
+		if k != i && k != j {
+			kept += op.indices
+		}
+	}
+	return kept
+}
+
+// einsumPair returns the pair of operands whose contraction has the
+// smallest result, with i < j.
+func einsumPair(ops []einsumOperand, output string, sizes map[byte]int) (int, int) {
+	bestI, bestJ, bestSize := 0, 1, -1
+	for i := range ops {
+		for j := i + 1; j < len(ops); j++ {
+			kept := einsumKept(ops, output, i, j)
+			size := 1
+			for _, c := range []byte(einsumUnion(ops[i].indices, ops[j].indices)) {
+				if strings.IndexByte(kept, c) != -1 {
+					size *= sizes[c]
+				}
+			}
+			if bestSize == -1 || size < bestSize {
+				bestI, bestJ, bestSize = i, j, size
+			}
+		}
+	}
+	return bestI, bestJ
+}
+
+// einsumUnion returns the indices of a followed by the indices of b which
+// are not in a.
+func einsumUnion(a, b string) string {
+	return a + einsumFilter(b, a, false)
+}
+
+// einsumFilter returns the indices of s which are (or are not, according to
+// in) in the set of indices.
+func einsumFilter(s, set string, in bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if (strings.IndexByte(set, s[i]) != -1) == in {
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// einsumOperand is a tensor with an index for each dimension.
+type einsumOperand struct {
+	t       Tensor
+	indices string
+}
+
+// size returns the size of the dimension of the index c.
+func (o einsumOperand) size(c byte) int {
+	return o.t.shape[strings.IndexByte(o.indices, c)]
+}
+
+// diagonal returns the operand with a single dimension for each index,
+// taking the diagonal of the dimensions with the same index.
+func (o einsumOperand) diagonal() einsumOperand {
+	for q := 1; q < len(o.indices); q++ {
+		p := strings.IndexByte(o.indices[:q], o.indices[q])
+		if p == -1 {
+			continue
+		}
+		n := o.t.shape[p]
+		eye := make([]float64, n*n)
+		for i := 0; i < n; i++ {
+			eye[i*n+i] = 1
+		}
+		maskShape := make([]int, len(o.t.shape))
+		for i := range maskShape {
+			maskShape[i] = 1
+		}
+		maskShape[p], maskShape[q] = n, n
+		mask := NewTensor(Var(o.t.node.Value().NewMatrix(n, n, float.SliceInterface(eye))), maskShape...)
+		// the repeated dimension is summed, leaving the diagonal only
+		masked := TensorProd(o.t, mask)
+		reduced := masked.Shape()
+		reduced[q] = 1
+		shape := append(append([]int{}, o.t.shape[:q]...), o.t.shape[q+1:]...)
+		o = einsumOperand{
+			t:       TensorReshape(TensorSumTo(masked, reduced...), shape...),
+			indices: o.indices[:q] + o.indices[q+1:],
+		}
+		return o.diagonal()
+	}
+	return o
+}
+
+// sum returns the operand with the dimensions of the indices which are not
+// in kept summed away.
+func (o einsumOperand) sum(kept string) einsumOperand {
+	indices := einsumFilter(o.indices, kept, true)
+	if len(indices) == len(o.indices) {
+		return o
+	}
+	reduced := make([]int, len(o.t.shape))
+	shape := make([]int, 0, len(indices))
+	for i, d := range o.t.shape {
+		reduced[i] = 1
+		if strings.IndexByte(kept, o.indices[i]) != -1 {
+			reduced[i] = d
+			shape = append(shape, d)
+		}
+	}
+	return einsumOperand{
+		t:       TensorReshape(TensorSumTo(o.t, reduced...), shape...),
+		indices: indices,
+	}
+}
+
+// permute returns the operand with the dimensions in the order of indices,
+// which must be a permutation of the indices of the operand.
+func (o einsumOperand) permute(indices string) einsumOperand {
+	if o.indices == indices {
+		return o
+	}
+	axes := make([]int, len(indices))
+	for i := range axes {
+		axes[i] = strings.IndexByte(o.indices, indices[i])
+	}
+	return einsumOperand{t: TensorPermute(o.t, axes...), indices: indices}
+}
+
+// contract returns the contraction of the operands o and b, keeping the
+// indices in kept, as a batched matrix multiplication: the indices kept
+// and shared by the operands are the batch, the ones not kept are summed,
+// and the other ones are the rows of o and the columns of b.
+func (o einsumOperand) contract(b einsumOperand, kept string) einsumOperand {
+	o = o.sum(kept + b.indices)
+	b = b.sum(kept + o.indices)
+	shared := einsumFilter(o.indices, b.indices, true)
+	batch := einsumFilter(shared, kept, true)
+	summed := einsumFilter(shared, kept, false)
+	rows := einsumFilter(o.indices, b.indices, false)
+	cols := einsumFilter(b.indices, o.indices, false)
+
+	dims := func(op einsumOperand, indices string) (size int, shape []int) {
+		size = 1
+		for i := 0; i < len(indices); i++ {
+			d := op.size(indices[i])
+			size *= d
+			shape = append(shape, d)
+		}
+		return size, shape
+	}
+	n, batchShape := dims(o, batch)
+	r, rowsShape := dims(o, rows)
+	k, _ := dims(o, summed)
+	c, colsShape := dims(b, cols)
+
+	x1 := TensorReshape(o.permute(batch+rows+summed).t, n, r, k)
+	x2 := TensorReshape(b.permute(batch+summed+cols).t, n, k, c)
+	shape := append(append(batchShape, rowsShape...), colsShape...)
+	return einsumOperand{
+		t:       TensorReshape(TensorMatMul(x1, x2), shape...),
+		indices: batch + rows + cols,
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEinsum(t *testing.T) {
+	t.Run("float32", testEinsum[float32])
+	t.Run("float64", testEinsum[float64])
+}
+
+func testEinsum[T float.DType](t *testing.T) {
+	a := mat.NewDense(2, 3, []T{
+		1, 2, 3,
+		4, 5, 6,
+	})
+	b := mat.NewDense(3, 2, []T{
+		1, -1,
+		0, 2,
+		-2, 1,
+	})
+	sq := mat.NewDense(2, 2, []T{
+		1, 2,
+		3, 4,
+	})
+	u := mat.NewVecDense([]T{1, -1})
+	v := mat.NewVecDense([]T{2, 0, 1})
+
+	testCases := []struct {
+		name  string
+		spec  string
+		xs    []mat.Matrix
+		rows  int
+		cols  int
+		value []T
+	}{
+		{"matrix multiplication", "ij,jk->ik", []mat.Matrix{a, b}, 2, 2, []T{-5, 6, -8, 12}},
+		{"implicit output", "ij,jk", []mat.Matrix{a, b}, 2, 2, []T{-5, 6, -8, 12}},
+		{"transposed product", "ij,kj->ik", []mat.Matrix{a, a}, 2, 2, []T{14, 32, 32, 77}},
+		{"transpose", "ij->ji", []mat.Matrix{a}, 3, 2, []T{1, 4, 2, 5, 3, 6}},
+		{"sum", "ij->", []mat.Matrix{a}, 1, 1, []T{21}},
+		{"column sums", "ij->j", []mat.Matrix{a}, 3, 1, []T{5, 7, 9}},
+		{"trace", "ii->", []mat.Matrix{sq}, 1, 1, []T{5}},
+		{"diagonal", "ii->i", []mat.Matrix{sq}, 2, 1, []T{1, 4}},
+		{"dot product", "i,i->", []mat.Matrix{u, u}, 1, 1, []T{2}},
+		{"outer product", "i,j->ij", []mat.Matrix{u, v}, 2, 3, []T{2, 0, 1, -2, 0, -1}},
+		{"matrix-vector product", "ij,j->i", []mat.Matrix{a, v}, 2, 1, []T{5, 14}},
+		{"bilinear form", "i,ij,j->", []mat.Matrix{u, a, v}, 1, 1, []T{-9}},
+		{"chain of products", "ij,jk,kl->il", []mat.Matrix{a, b, sq}, 2, 2, []T{13, 14, 28, 32}},
+		{"element-wise product", "ij,ij->ij", []mat.Matrix{sq, sq}, 2, 2, []T{1, 4, 9, 16}},
+		{"scalar", ",ij->ij", []mat.Matrix{mat.NewScalar[T](2), sq}, 2, 2, []T{2, 4, 6, 8}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			xs := make([]Node, len(tc.xs))
+			for i, x := range tc.xs {
+				xs[i] = Var(x)
+			}
+			y := Einsum(tc.spec, xs...)
+			assert.Equal(t, tc.rows, y.Value().Rows())
+			assert.Equal(t, tc.cols, y.Value().Columns())
+			assert.InDeltaSlice(t, tc.value, mat.Data[T](y.Value()), 1.0e-6)
+		})
+	}
+
+	t.Run("gradients", func(t *testing.T) {
+		x1 := Var(a.Clone()).WithGrad(true)
+		x2 := Var(b.Clone()).WithGrad(true)
+		x3 := Var(sq.Clone()).WithGrad(true)
+		// the sum of the elements of x1·x2·x3
+		Backward(Einsum("ij,jk,kl->", x1, x2, x3))
+		assert.InDeltaSlice(t, []T{-4, 14, 1, -4, 14, 1}, mat.Data[T](x1.Grad()), 1.0e-6)
+		assert.InDeltaSlice(t, []T{15, 35, 21, 49, 27, 63}, mat.Data[T](x2.Grad()), 1.0e-6)
+		assert.InDeltaSlice(t, []T{-13, -13, 18, 18}, mat.Data[T](x3.Grad()), 1.0e-6)
+	})
+
+	t.Run("invalid specs", func(t *testing.T) {
+		x := Var(a)
+		assert.Panics(t, func() { Einsum("ij,jk->ik", x) }, "missing operand")
+		assert.Panics(t, func() { Einsum("ij->ik", x) }, "unknown output index")
+		assert.Panics(t, func() { Einsum("ij->ii", x) }, "repeated output index")
+		assert.Panics(t, func() { Einsum("i1->i", x) }, "invalid index")
+		assert.Panics(t, func() { Einsum("ijk->i", x) }, "rank 3")
+		assert.Panics(t, func() { Einsum("i->i", x) }, "not a vector")
+		assert.Panics(t, func() { Einsum("ij,ij->", x, Var(b)) }, "inconsistent sizes")
+		assert.Panics(t, func() { Einsum("ii->i", x) }, "non-square diagonal")
+	})
+}
+
+func TestTensorEinsum(t *testing.T) {
+	t.Run("float32", testTensorEinsum[float32])
+	t.Run("float64", testTensorEinsum[float64])
+}
+
+func testTensorEinsum[T float.DType](t *testing.T) {
+	x1 := Var(mat.NewVecDense([]T{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12})).WithGrad(true)
+	x2 := Var(mat.NewVecDense([]T{1, 0, -1, 2, 0.5, 1, 1, -2, 0, 3, 1, 1})).WithGrad(true)
+
+	a := NewTensor(x1, 2, 2, 3)
+	b := NewTensor(x2, 2, 3, 2)
+
+	y := TensorEinsum("bij,bjk->bik", a, b)
+	expected := TensorMatMul(a, b)
+	assert.Equal(t, []int{2, 2, 2}, y.Shape())
+	assert.InDeltaSlice(t, mat.Data[T](expected.Node().Value()), mat.Data[T](y.Node().Value()), 1.0e-6)
+
+	// the same product, with the batch as the last dimension of the result
+	y = TensorEinsum("bij,bjk->ikb", a, b)
+	assert.Equal(t, []int{2, 2, 2}, y.Shape())
+	assert.InDeltaSlice(t, mat.Data[T](TensorPermute(expected, 1, 2, 0).Node().Value()), mat.Data[T](y.Node().Value()), 1.0e-6)
+
+	// the sum over the batch of the traces of a·b
+	y = TensorEinsum("bij,bji->", a, b)
+	assert.Equal(t, []int{}, y.Shape())
+	assert.InDeltaSlice(t, []T{57.5}, mat.Data[T](y.Node().Value()), 1.0e-6)
+
+	Backward(y.Node())
+	assert.InDeltaSlice(t, mat.Data[T](TensorPermute(b, 0, 2, 1).Value().Matrix()), mat.Data[T](x1.Grad()), 1.0e-6)
+	assert.InDeltaSlice(t, mat.Data[T](TensorPermute(a, 0, 2, 1).Value().Matrix()), mat.Data[T](x2.Grad()), 1.0e-6)
+
+	assert.Panics(t, func() { TensorEinsum("ij,jk->ik", a, b) })
+}
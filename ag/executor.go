@@ -0,0 +1,119 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+// Executor schedules the execution of the forward and backward steps of
+// the operators.
+//
+// The executor of an Operator is set on creation. It is the executor of the
+// first operand having one (see Variable.WithExecutor), or the global
+// executor set with SetExecutor otherwise. This way, a whole graph can be
+// run with a specific executor, simply setting it on its input variables.
+//
+// In debugging mode (see SetDebugMode), the Sequential executor is always
+// used.
+type Executor interface {
+	// Go executes the function f, either synchronously or asynchronously.
+	//
+	// The function f may wait for the completion of other functions
+	// previously passed to Go, so an implementation must never postpone
+	// the execution of f until the completion of a function passed later.
+	Go(f func())
+}
+
+var (
+	// Sequential is an Executor which runs the operators eagerly and
+	// sequentially, on the calling goroutine.
+	Sequential Executor = sequential{}
+	// Concurrent is an Executor which runs each operation on a new
+	// goroutine. It is the default global executor.
+	Concurrent Executor = concurrent{}
+)
+
+// executor is the global executor, used by the operators whose operands
+// have no executor.
+var executor = Concurrent
+
+// SetExecutor sets the global executor, used for the new operators whose
+// operands have no executor.
+func SetExecutor(e Executor) {
+	if e == nil {
+		panic("ag: executor cannot be nil")
+	}
+	executor = e
+}
+
+// DefaultExecutor returns the global executor (see SetExecutor).
+func DefaultExecutor() Executor {
+	return executor
+}
+
+type sequential struct{}
+
+// Go executes f on the calling goroutine.
+func (sequential) Go(f func()) {
+	f()
+}
+
+type concurrent struct{}
+
+// Go executes f on a new goroutine.
+func (concurrent) Go(f func()) {
+	go f()
+}
+
+// WorkerPool is an Executor which runs the operations concurrently, on a
+// bounded number of goroutines.
+type WorkerPool struct {
+	sem chan struct{}
+}
+
+// NewWorkerPool returns a new WorkerPool, running at most size operations
+// on their own goroutines at the same time.
+func NewWorkerPool(size int) *WorkerPool {
+	if size < 1 {
+		panic("ag: the size of the worker pool must be greater than zero")
+	}
+	return &WorkerPool{
+		sem: make(chan struct{}, size),
+	}
+}
+
+// Go executes f on a new goroutine, if the number of running operations is
+// lower than the size of the pool. Otherwise, f is executed on the calling
+// goroutine.
+//
+// Executing f synchronously, instead of waiting for a free goroutine,
+// prevents any deadlock in case the calling goroutine is itself executing
+// an operation of the pool.
+func (p *WorkerPool) Go(f func()) {
+	select {
+	case p.sem <- struct{}{}:
+		go func() {
+			defer func() { <-p.sem }()
+			f()
+		}()
+	default:
+		f()
+	}
+}
+
+// inheritedExecutor returns the executor explicitly set on the first
+// operand having one, or nil.
+func inheritedExecutor(operands []Node) Executor {
+	for _, operand := range operands {
+		switch o := operand.(type) {
+		case *Operator:
+			if o.executor != nil {
+				return o.executor
+			}
+		case *Variable:
+			if o.executor != nil {
+				return o.executor
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutors(t *testing.T) {
+	t.Run("float32", testExecutors[float32])
+	t.Run("float64", testExecutors[float64])
+}
+
+func testExecutors[T float.DType](t *testing.T) {
+	executors := []struct {
+		name     string
+		executor Executor
+	}{
+		{"Sequential", Sequential},
+		{"Concurrent", Concurrent},
+		{"WorkerPool(1)", NewWorkerPool(1)},
+		{"WorkerPool(3)", NewWorkerPool(3)},
+	}
+
+	for _, e := range executors {
+		t.Run(e.name, func(t *testing.T) {
+			x := Var(mat.NewVecDense([]T{0.1, 0.2, 0.3})).WithGrad(true).WithExecutor(e.executor)
+			w := Var(mat.NewDense(2, 3, []T{0.4, 0.5, -0.6, 0.7, -0.8, 0.9})).WithGrad(true)
+
+			// A long chain of operators sharing the same parameter.
+			h := Mul(w, x)
+			for i := 0; i < 1000; i++ {
+				h = Add(Tanh(h), Mul(w, x))
+			}
+			y := ReduceSum(h)
+
+			op := y.(*Operator)
+			assert.Equal(t, e.executor, op.executor)
+
+			Backward(y)
+			require.NotNil(t, x.Grad())
+			require.NotNil(t, w.Grad())
+			assert.Equal(t, 3, x.Grad().Size())
+			assert.Equal(t, 6, w.Grad().Size())
+		})
+	}
+}
+
+func TestSequential(t *testing.T) {
+	x := Var(mat.NewScalar[float64](1)).WithExecutor(Sequential)
+	y := Exp(x).(*Operator)
+	assert.NotNil(t, y.value.Load(), "the value is expected to be already computed")
+
+	SetDebugMode(true)
+	defer SetDebugMode(false)
+	z := Exp(Var(mat.NewScalar[float64](1)).WithExecutor(NewWorkerPool(1))).(*Operator)
+	assert.NotNil(t, z.value.Load(), "the value is expected to be already computed")
+}
+
+func TestExecutorConsistency(t *testing.T) {
+	run := func(e Executor) (mat.Matrix, mat.Matrix) {
+		x := Var(mat.NewVecDense([]float64{0.1, 0.2, 0.3})).WithGrad(true).WithExecutor(e)
+		w := Var(mat.NewDense(2, 3, []float64{0.4, 0.5, -0.6, 0.7, -0.8, 0.9})).WithGrad(true)
+		h := Mul(w, x)
+		for i := 0; i < 50; i++ {
+			h = Add(Sigmoid(h), Mul(w, x))
+		}
+		Backward(ReduceSum(h))
+		return x.Grad(), w.Grad()
+	}
+
+	expectedGx, expectedGw := run(Concurrent)
+	for _, e := range []Executor{Sequential, NewWorkerPool(1), NewWorkerPool(4)} {
+		gx, gw := run(e)
+		assert.InDeltaSlice(t, expectedGx.Data(), gx.Data(), 1.0e-12)
+		assert.InDeltaSlice(t, expectedGw.Data(), gw.Data(), 1.0e-12)
+	}
+}
+
+func TestSetExecutor(t *testing.T) {
+	defer SetExecutor(Concurrent)
+
+	e := &countingExecutor{}
+	SetExecutor(e)
+	assert.Same(t, e, DefaultExecutor())
+
+	x := Var(mat.NewVecDense([]float64{1, 2})).WithGrad(true)
+	y := ReduceSum(Square(x))
+	assert.Nil(t, y.(*Operator).executor)
+	Backward(y)
+	assert.Equal(t, int64(4), atomic.LoadInt64(&e.count))
+	assert.Equal(t, []float64{2, 4}, x.Grad().Data().F64())
+
+	assert.Panics(t, func() { SetExecutor(nil) })
+}
+
+func TestNewWorkerPool(t *testing.T) {
+	assert.Panics(t, func() { NewWorkerPool(0) })
+
+	p := NewWorkerPool(2)
+	var running, maxRunning int64
+	done := make(chan struct{}, 10)
+	for i := 0; i < 10; i++ {
+		p.Go(func() {
+			n := atomic.AddInt64(&running, 1)
+			for {
+				m := atomic.LoadInt64(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt64(&maxRunning, m, n) {
+					break
+				}
+			}
+			atomic.AddInt64(&running, -1)
+			done <- struct{}{}
+		})
+	}
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+	// The calling goroutine can run one more operation.
+	assert.LessOrEqual(t, atomic.LoadInt64(&maxRunning), int64(3))
+}
+
+// countingExecutor is a sequential Executor counting the executions.
+type countingExecutor struct {
+	count int64
+}
+
+func (e *countingExecutor) Go(f func()) {
+	atomic.AddInt64(&e.count, 1)
+	f()
+}
@@ -8,6 +8,9 @@ import "github.com/nlpodyssey/spago/mat"
 
 // Add is an operator to perform element-wise sum over two values.
 // y = x1 + x2
+//
+// The values are broadcast against each other if their dimensions differ
+// (see mat.BroadcastDims), and the gradients are reduced accordingly.
 type Add[O Operand] struct {
 	x1 O
 	x2 O
@@ -40,17 +43,38 @@ func (r *Add[O]) Forward() mat.Matrix {
 // Backward computes the backward pass.
 func (r *Add[O]) Backward(gy mat.Matrix) {
 	if r.x1.RequiresGrad() {
-		x1v := r.x1.Value()
-		if !mat.SameDims(x1v, gy) {
-			panic("fn: matrices have incompatible dimensions")
-		}
-		r.x1.AccGrad(gy)
+		broadcastGradDims(r.x1.Value(), r.x2.Value(), gy)
+		accUnbroadcastGrad(r.x1, gy)
 	}
 	if r.x2.RequiresGrad() {
 		x2v := r.x2.Value()
-		if !mat.SameDims(x2v, gy) {
+		if x1v := r.x1.Value(); x1v != nil {
+			broadcastGradDims(x1v, x2v, gy)
+		} else if !mat.SameDims(x2v, gy) {
 			panic("fn: matrices have incompatible dimensions")
 		}
-		r.x2.AccGrad(gy)
+		accUnbroadcastGrad(r.x2, gy)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Add[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x1.RequiresGrad() {
+		gxs[0] = unbroadcastGraph(g, gy, r.x1)
+	}
+	if r.x2.RequiresGrad() {
+		gxs[1] = unbroadcastGraph(g, gy, r.x2)
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Add[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	x2v := r.x2.Value()
+	rows, cols := x2v.Dims()
+	if x1v := r.x1.Value(); x1v != nil {
+		rows, cols, _ = mat.BroadcastDims(x1v, x2v)
 	}
+	return broadcastOutput(sumTangents(tangents[0], tangents[1]), rows, cols)
 }
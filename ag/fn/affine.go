@@ -136,3 +136,49 @@ func (a *Affine[O]) Backward(gy mat.Matrix) {
 
 	wg.Wait()
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (a *Affine[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 0, len(a.wxPairs)+3)
+
+	var gb O
+	if a.b.RequiresGrad() {
+		gb = gy
+	}
+	gxs = append(gxs, gb)
+
+	backwardWX := func(w, x O) {
+		var gw, gx O
+		if w.RequiresGrad() {
+			gw = g.NewOperator(NewMul(gy, g.NewOperator(NewTranspose(x))))
+		}
+		if x.RequiresGrad() {
+			gx = g.NewOperator(NewMulT(w, gy))
+		}
+		gxs = append(gxs, gw, gx)
+	}
+
+	backwardWX(a.w1, a.x1)
+	for i := 0; i < len(a.wxPairs); i += 2 {
+		backwardWX(a.wxPairs[i], a.wxPairs[i+1])
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (a *Affine[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	operands := a.Operands()
+	ts := []mat.Matrix{tangents[0]}
+	for i := 1; i < len(operands); i += 2 {
+		w, x := operands[i].Value(), operands[i+1].Value()
+		ts = append(ts,
+			mapTangent(tangents[i], func(t mat.Matrix) mat.Matrix {
+				return t.Mul(x)
+			}),
+			mapTangent(tangents[i+1], func(t mat.Matrix) mat.Matrix {
+				return w.Mul(t)
+			}),
+		)
+	}
+	return sumTangents(ts...)
+}
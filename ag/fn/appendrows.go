@@ -61,3 +61,36 @@ func (a *AppendRows[O]) Backward(gy mat.Matrix) {
 		mat.ReleaseMatrix(vGrads)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (a *AppendRows[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 0, len(a.vs)+1)
+	xRows, cols := a.x.Value().Dims()
+
+	var gx O
+	if a.x.RequiresGrad() {
+		gx = g.NewOperator(NewSlice(gy, 0, 0, xRows, cols))
+	}
+	gxs = append(gxs, gx)
+
+	for i, v := range a.vs {
+		var gv O
+		if v.RequiresGrad() {
+			rows, vCols := v.Value().Dims()
+			offset := (xRows + i) * cols
+			indices := rangeIndices(1, offset, 0, offset+cols, 1)
+			gv = g.NewOperator(&gather[O]{x: gy, rows: rows, cols: vCols, indices: indices})
+		}
+		gxs = append(gxs, gv)
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (a *AppendRows[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	ts := tangentsOrZeros(tangents, a.Operands())
+	if ts == nil {
+		return nil
+	}
+	return ts[0].AppendRows(ts[1:]...)
+}
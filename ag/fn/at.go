@@ -43,3 +43,19 @@ func (r *At[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(dx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *At[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	rows, cols := r.x.Value().Dims()
+	return []O{g.NewOperator(&scatter[O]{x: gy, rows: rows, cols: cols, indices: []int{r.i*cols + r.j}})}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *At[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return t.At(r.i, r.j)
+	})
+}
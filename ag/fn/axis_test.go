@@ -0,0 +1,250 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/mattest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ Function[*variable] = &SumAxis[*variable]{}
+	_ Function[*variable] = &MeanAxis[*variable]{}
+	_ Function[*variable] = &MaxAxis[*variable]{}
+	_ Function[*variable] = &VarAxis[*variable]{}
+	_ Function[*variable] = &StdAxis[*variable]{}
+	_ Function[*variable] = &LogSumExpAxis[*variable]{}
+)
+
+// axisFunction creates an axis-reduction Function.
+type axisFunction func(x *variable, axis int) Function[*variable]
+
+var (
+	newSumAxis       axisFunction = func(x *variable, axis int) Function[*variable] { return NewSumAxis(x, axis) }
+	newMeanAxis      axisFunction = func(x *variable, axis int) Function[*variable] { return NewMeanAxis(x, axis) }
+	newMaxAxis       axisFunction = func(x *variable, axis int) Function[*variable] { return NewMaxAxis(x, axis) }
+	newVarAxis       axisFunction = func(x *variable, axis int) Function[*variable] { return NewVarAxis(x, axis) }
+	newStdAxis       axisFunction = func(x *variable, axis int) Function[*variable] { return NewStdAxis(x, axis) }
+	newLogSumExpAxis axisFunction = func(x *variable, axis int) Function[*variable] { return NewLogSumExpAxis(x, axis) }
+)
+
+func TestAxisFunctions(t *testing.T) {
+	t.Run("float32", testAxisFunctions[float32])
+	t.Run("float64", testAxisFunctions[float64])
+}
+
+func testAxisFunctions[T float.DType](t *testing.T) {
+	x := mat.NewDense(2, 3, []T{
+		1, 2, 3,
+		4, 0, -2,
+	})
+	row := mat.NewDense(1, 4, []T{1, 3, 3, -1})
+	col := mat.NewDense(3, 1, []T{2, 5, 5})
+
+	tests := []struct {
+		name string
+		f    axisFunction
+		x    mat.Matrix
+		axis int
+		want mat.Matrix
+		gy   mat.Matrix
+		gx   mat.Matrix
+	}{
+		{
+			name: "SumAxis along axis 0",
+			f:    newSumAxis,
+			x:    x,
+			axis: 0,
+			want: mat.NewDense(1, 3, []T{5, 2, 1}),
+			gy:   mat.NewDense(1, 3, []T{1, 2, 3}),
+			gx:   mat.NewDense(2, 3, []T{1, 2, 3, 1, 2, 3}),
+		},
+		{
+			name: "SumAxis of a row vector along axis 0",
+			f:    newSumAxis,
+			x:    row,
+			axis: 0,
+			want: mat.NewDense(1, 4, []T{1, 3, 3, -1}),
+			gy:   mat.NewDense(1, 4, []T{1, 2, 3, 4}),
+			gx:   mat.NewDense(1, 4, []T{1, 2, 3, 4}),
+		},
+		{
+			name: "SumAxis of a column vector along axis 0",
+			f:    newSumAxis,
+			x:    col,
+			axis: 0,
+			want: mat.NewDense(1, 1, []T{12}),
+			gy:   mat.NewDense(1, 1, []T{2}),
+			gx:   mat.NewDense(3, 1, []T{2, 2, 2}),
+		},
+		{
+			name: "MeanAxis along axis 1",
+			f:    newMeanAxis,
+			x:    x,
+			axis: 1,
+			want: mat.NewDense(2, 1, []T{2, 0.666667}),
+			gy:   mat.NewDense(2, 1, []T{3, -3}),
+			gx:   mat.NewDense(2, 3, []T{1, 1, 1, -1, -1, -1}),
+		},
+		{
+			name: "MeanAxis of a row vector along axis 1",
+			f:    newMeanAxis,
+			x:    row,
+			axis: 1,
+			want: mat.NewDense(1, 1, []T{1.5}),
+			gy:   mat.NewDense(1, 1, []T{4}),
+			gx:   mat.NewDense(1, 4, []T{1, 1, 1, 1}),
+		},
+		{
+			name: "MeanAxis of a column vector along axis 1",
+			f:    newMeanAxis,
+			x:    col,
+			axis: 1,
+			want: mat.NewDense(3, 1, []T{2, 5, 5}),
+			gy:   mat.NewDense(3, 1, []T{1, 2, 3}),
+			gx:   mat.NewDense(3, 1, []T{1, 2, 3}),
+		},
+		{
+			name: "MaxAxis along axis 0",
+			f:    newMaxAxis,
+			x:    x,
+			axis: 0,
+			want: mat.NewDense(1, 3, []T{4, 2, 3}),
+			gy:   mat.NewDense(1, 3, []T{1, 2, 3}),
+			gx:   mat.NewDense(2, 3, []T{0, 2, 3, 1, 0, 0}),
+		},
+		{
+			name: "MaxAxis with ties along axis 1",
+			f:    newMaxAxis,
+			x: mat.NewDense(2, 3, []T{
+				3, 1, 3,
+				0, 2, 2,
+			}),
+			axis: 1,
+			want: mat.NewDense(2, 1, []T{3, 2}),
+			gy:   mat.NewDense(2, 1, []T{1, 2}),
+			gx:   mat.NewDense(2, 3, []T{1, 0, 0, 0, 2, 0}),
+		},
+		{
+			name: "MaxAxis of a row vector with ties along axis 1",
+			f:    newMaxAxis,
+			x:    row,
+			axis: 1,
+			want: mat.NewDense(1, 1, []T{3}),
+			gy:   mat.NewDense(1, 1, []T{2}),
+			gx:   mat.NewDense(1, 4, []T{0, 2, 0, 0}),
+		},
+		{
+			name: "MaxAxis of a column vector with ties along axis 0",
+			f:    newMaxAxis,
+			x:    col,
+			axis: 0,
+			want: mat.NewDense(1, 1, []T{5}),
+			gy:   mat.NewDense(1, 1, []T{1}),
+			gx:   mat.NewDense(3, 1, []T{0, 1, 0}),
+		},
+		{
+			name: "MaxAxis of a column vector along axis 1",
+			f:    newMaxAxis,
+			x:    col,
+			axis: 1,
+			want: mat.NewDense(3, 1, []T{2, 5, 5}),
+			gy:   mat.NewDense(3, 1, []T{1, 2, 3}),
+			gx:   mat.NewDense(3, 1, []T{1, 2, 3}),
+		},
+		{
+			name: "VarAxis along axis 1",
+			f:    newVarAxis,
+			x:    x,
+			axis: 1,
+			want: mat.NewDense(2, 1, []T{0.666667, 6.222222}),
+			gy:   mat.NewDense(2, 1, []T{1, 1}),
+			gx:   mat.NewDense(2, 3, []T{-0.666667, 0, 0.666667, 2.222222, -0.444444, -1.777778}),
+		},
+		{
+			name: "VarAxis of a row vector along axis 1",
+			f:    newVarAxis,
+			x:    row,
+			axis: 1,
+			want: mat.NewDense(1, 1, []T{2.75}),
+			gy:   mat.NewDense(1, 1, []T{1}),
+			gx:   mat.NewDense(1, 4, []T{-0.25, 0.75, 0.75, -1.25}),
+		},
+		{
+			name: "StdAxis along axis 0",
+			f:    newStdAxis,
+			x:    x,
+			axis: 0,
+			want: mat.NewDense(1, 3, []T{1.5, 1, 2.5}),
+			gy:   mat.NewDense(1, 3, []T{1, 1, 1}),
+			gx:   mat.NewDense(2, 3, []T{-0.5, 0.5, 0.5, 0.5, -0.5, -0.5}),
+		},
+		{
+			name: "StdAxis of a column vector along axis 0",
+			f:    newStdAxis,
+			x:    col,
+			axis: 0,
+			want: mat.NewDense(1, 1, []T{1.414214}),
+			gy:   mat.NewDense(1, 1, []T{1}),
+			gx:   mat.NewDense(3, 1, []T{-0.471405, 0.235702, 0.235702}),
+		},
+		{
+			name: "LogSumExpAxis along axis 1",
+			f:    newLogSumExpAxis,
+			x:    x,
+			axis: 1,
+			want: mat.NewDense(2, 1, []T{3.407606, 4.020581}),
+			gy:   mat.NewDense(2, 1, []T{1, 2}),
+			gx:   mat.NewDense(2, 3, []T{0.090031, 0.244728, 0.665241, 1.959258, 0.035885, 0.004857}),
+		},
+		{
+			name: "LogSumExpAxis of a row vector along axis 1",
+			f:    newLogSumExpAxis,
+			x:    row,
+			axis: 1,
+			want: mat.NewDense(1, 1, []T{3.767165}),
+			gy:   mat.NewDense(1, 1, []T{1}),
+			gx:   mat.NewDense(1, 4, []T{0.062840, 0.464328, 0.464328, 0.008504}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := newVarWithGrad(tt.x)
+			f := tt.f(x, tt.axis)
+			assert.Equal(t, []*variable{x}, f.Operands())
+
+			y := f.Forward()
+			mattest.RequireMatrixInDelta(t, tt.want, y, 1.0e-6)
+
+			f.Backward(tt.gy)
+			mattest.AssertMatrixInDelta(t, tt.gx, x.grad, 1.0e-6)
+
+			assert.Panics(t, func() { f.Backward(mat.NewEmptyDense[T](2, 2)) })
+		})
+	}
+
+	functions := map[string]axisFunction{
+		"SumAxis":       newSumAxis,
+		"MeanAxis":      newMeanAxis,
+		"MaxAxis":       newMaxAxis,
+		"VarAxis":       newVarAxis,
+		"StdAxis":       newStdAxis,
+		"LogSumExpAxis": newLogSumExpAxis,
+	}
+	for name, f := range functions {
+		for _, axis := range []int{-1, 2} {
+			t.Run(fmt.Sprintf("%s panics with axis %d", name, axis), func(t *testing.T) {
+				require.Panics(t, func() { f(newVarWithGrad(x), axis) })
+			})
+		}
+	}
+}
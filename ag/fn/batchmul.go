@@ -0,0 +1,122 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// BatchMul is an operator to perform the matrix multiplication of each pair
+// of matrices from two stacks, in a single step (see mat.BatchMul).
+type BatchMul[O Operand] struct {
+	x1     O // stack of matrices
+	x2     O // stack of matrices
+	batch  int
+	transA bool
+	transB bool
+}
+
+// NewBatchMul returns a new BatchMul Function. If transA or transB are true,
+// each matrix of the corresponding stack is transposed before the
+// multiplication.
+func NewBatchMul[O Operand](x1, x2 O, batch int, transA, transB bool) *BatchMul[O] {
+	return &BatchMul[O]{
+		x1:     x1,
+		x2:     x2,
+		batch:  batch,
+		transA: transA,
+		transB: transB,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *BatchMul[O]) Operands() []O {
+	return []O{r.x1, r.x2}
+}
+
+// Forward computes the output of the function.
+func (r *BatchMul[O]) Forward() mat.Matrix {
+	return mat.BatchMul(r.x1.Value(), r.x2.Value(), r.batch, r.transA, r.transB)
+}
+
+// Backward computes the backward pass.
+//
+// Given y = op(a)·op(b) for each pair of matrices, where op is either the
+// identity or the transposition, the gradients are op(a)ᵀ = gy·op(b)ᵀ and
+// op(b)ᵀ = op(a)ᵀ·gy, expressed again as batched multiplications.
+func (r *BatchMul[O]) Backward(gy mat.Matrix) {
+	x1, x2 := r.x1.Value(), r.x2.Value()
+	if gy.Rows() != r.batch*r.outRows(x1) || gy.Columns() != r.outCols(x2) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if r.x1.RequiresGrad() {
+		var gx mat.Matrix
+		if r.transA {
+			gx = mat.BatchMul(x2, gy, r.batch, r.transB, true)
+		} else {
+			gx = mat.BatchMul(gy, x2, r.batch, false, !r.transB)
+		}
+		defer mat.ReleaseMatrix(gx)
+		r.x1.AccGrad(gx)
+	}
+	if r.x2.RequiresGrad() {
+		var gx mat.Matrix
+		if r.transB {
+			gx = mat.BatchMul(gy, x1, r.batch, true, r.transA)
+		} else {
+			gx = mat.BatchMul(x1, gy, r.batch, !r.transA, false)
+		}
+		defer mat.ReleaseMatrix(gx)
+		r.x2.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *BatchMul[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x1.RequiresGrad() {
+		if r.transA {
+			gxs[0] = g.NewOperator(NewBatchMul(r.x2, gy, r.batch, r.transB, true))
+		} else {
+			gxs[0] = g.NewOperator(NewBatchMul(gy, r.x2, r.batch, false, !r.transB))
+		}
+	}
+	if r.x2.RequiresGrad() {
+		if r.transB {
+			gxs[1] = g.NewOperator(NewBatchMul(gy, r.x1, r.batch, true, r.transA))
+		} else {
+			gxs[1] = g.NewOperator(NewBatchMul(r.x1, gy, r.batch, !r.transA, false))
+		}
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *BatchMul[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return sumTangents(
+		mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+			return mat.BatchMul(t, r.x2.Value(), r.batch, r.transA, r.transB)
+		}),
+		mapTangent(tangents[1], func(t mat.Matrix) mat.Matrix {
+			return mat.BatchMul(r.x1.Value(), t, r.batch, r.transA, r.transB)
+		}),
+	)
+}
+
+// outRows returns the number of rows of each matrix of the output.
+func (r *BatchMul[O]) outRows(x1 mat.Matrix) int {
+	if r.transA {
+		return x1.Columns()
+	}
+	return x1.Rows() / r.batch
+}
+
+// outCols returns the number of columns of each matrix of the output.
+func (r *BatchMul[O]) outCols(x2 mat.Matrix) int {
+	if r.transB {
+		return x2.Rows() / r.batch
+	}
+	return x2.Columns()
+}
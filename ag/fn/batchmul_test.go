@@ -0,0 +1,80 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchMul_Forward(t *testing.T) {
+	t.Run("float32", testBatchMulForward[float32])
+	t.Run("float64", testBatchMulForward[float64])
+}
+
+func testBatchMulForward[T float.DType](t *testing.T) {
+	// two 2×3 matrices
+	x1 := &variable{
+		value: mat.NewDense(4, 3, []T{
+			1, 2, 3,
+			4, 5, 6,
+			-1, 0, 2,
+			0.5, 1, -2,
+		}),
+		requiresGrad: true,
+	}
+	// two 2×3 matrices, transposed
+	x2 := &variable{
+		value: mat.NewDense(4, 3, []T{
+			1, 0, 1,
+			0, 1, 1,
+			2, 1, 0,
+			-1, 3, 0.5,
+		}),
+		requiresGrad: true,
+	}
+
+	f := NewBatchMul(x1, x2, 2, false, true)
+	assert.Equal(t, []*variable{x1, x2}, f.Operands())
+
+	y := f.Forward()
+	assert.Equal(t, 4, y.Rows())
+	assert.Equal(t, 2, y.Columns())
+	assert.InDeltaSlice(t, []T{
+		4, 5,
+		10, 11,
+		-2, 2,
+		2, 1.5,
+	}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(4, 2, []T{
+		1, 0,
+		0, 1,
+		1, -1,
+		0.5, 0,
+	}))
+
+	// gx1 = gy·x2
+	assert.InDeltaSlice(t, []T{
+		1, 0, 1,
+		0, 1, 1,
+		3, -2, -0.5,
+		1, 0.5, 0,
+	}, x1.grad.Data(), 1.0e-6)
+	// gx2 = gyᵀ·x1
+	assert.InDeltaSlice(t, []T{
+		1, 2, 3,
+		4, 5, 6,
+		-0.75, 0.5, 1,
+		1, 0, -2,
+	}, x2.grad.Data(), 1.0e-6)
+
+	assert.Panics(t, func() {
+		f.Backward(mat.NewEmptyDense[T](2, 2))
+	})
+}
@@ -0,0 +1,171 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// BroadcastTo is an operator to broadcast a matrix to the given dimensions,
+// repeating its values along the dimensions of size 1 (see mat.BroadcastTo).
+type BroadcastTo[O Operand] struct {
+	x    O
+	rows int
+	cols int
+}
+
+// NewBroadcastTo returns a new BroadcastTo Function.
+func NewBroadcastTo[O Operand](x O, rows, cols int) *BroadcastTo[O] {
+	return &BroadcastTo[O]{
+		x:    x,
+		rows: rows,
+		cols: cols,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *BroadcastTo[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *BroadcastTo[O]) Forward() mat.Matrix {
+	return mat.BroadcastTo(r.x.Value(), r.rows, r.cols)
+}
+
+// Backward computes the backward pass.
+func (r *BroadcastTo[O]) Backward(gy mat.Matrix) {
+	if gy.Rows() != r.rows || gy.Columns() != r.cols {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		x := r.x.Value()
+		gx := mat.SumTo(gy, x.Rows(), x.Columns())
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *BroadcastTo[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	x := r.x.Value()
+	return []O{g.NewOperator(NewSumTo(gy, x.Rows(), x.Columns()))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *BroadcastTo[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return mat.BroadcastTo(t, r.rows, r.cols)
+	})
+}
+
+// SumTo is an operator to reduce a matrix to the given dimensions, summing
+// its values along the dimensions reduced to size 1 (see mat.SumTo).
+type SumTo[O Operand] struct {
+	x    O
+	rows int
+	cols int
+}
+
+// NewSumTo returns a new SumTo Function.
+func NewSumTo[O Operand](x O, rows, cols int) *SumTo[O] {
+	return &SumTo[O]{
+		x:    x,
+		rows: rows,
+		cols: cols,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *SumTo[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *SumTo[O]) Forward() mat.Matrix {
+	return mat.SumTo(r.x.Value(), r.rows, r.cols)
+}
+
+// Backward computes the backward pass.
+func (r *SumTo[O]) Backward(gy mat.Matrix) {
+	if gy.Rows() != r.rows || gy.Columns() != r.cols {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		x := r.x.Value()
+		gx := mat.BroadcastTo(gy, x.Rows(), x.Columns())
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *SumTo[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	x := r.x.Value()
+	return []O{g.NewOperator(NewBroadcastTo(gy, x.Rows(), x.Columns()))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *SumTo[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return mat.SumTo(t, r.rows, r.cols)
+	})
+}
+
+// unbroadcast returns the gradients gy reduced to the dimensions of x, which
+// may have been broadcast in the forward step. If the dimensions are the
+// same, gy itself is returned.
+func unbroadcast(gy, x mat.Matrix) mat.Matrix {
+	if mat.SameDims(gy, x) {
+		return gy
+	}
+	return mat.SumTo(gy, x.Rows(), x.Columns())
+}
+
+// accUnbroadcastGrad accumulates the gradients gx into the operand x,
+// reducing them to the dimensions of its value if necessary. The matrix gx
+// is not released.
+func accUnbroadcastGrad[O Operand](x O, gx mat.Matrix) {
+	g := unbroadcast(gx, x.Value())
+	if g != gx {
+		defer mat.ReleaseMatrix(g)
+	}
+	x.AccGrad(g)
+}
+
+// unbroadcastGraph returns the node gy reduced to the dimensions of x,
+// which may have been broadcast in the forward step. If the dimensions are
+// the same, gy itself is returned.
+func unbroadcastGraph[O Operand](g Graph[O], gy, x O) O {
+	xv := x.Value()
+	if mat.SameDims(gy.Value(), xv) {
+		return gy
+	}
+	return g.NewOperator(NewSumTo(gy, xv.Rows(), xv.Columns()))
+}
+
+// broadcastOutput returns the tangent t broadcast to the given dimensions
+// of the output, or nil if t is nil. If the dimensions are the same, t itself
+// is returned, otherwise t is released.
+func broadcastOutput(t mat.Matrix, rows, cols int) mat.Matrix {
+	if t == nil || (t.Rows() == rows && t.Columns() == cols) {
+		return t
+	}
+	defer mat.ReleaseMatrix(t)
+	return mat.BroadcastTo(t, rows, cols)
+}
+
+// broadcastGradDims panics if the dimensions of the gradients gy differ from
+// the dimensions resulting from broadcasting x1 and x2 against each other.
+func broadcastGradDims(x1, x2, gy mat.Matrix) {
+	rows, cols, ok := mat.BroadcastDims(x1, x2)
+	if !ok || gy.Rows() != rows || gy.Columns() != cols {
+		panic("fn: matrices have incompatible dimensions")
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroadcastTo_Forward(t *testing.T) {
+	t.Run("float32", testBroadcastToForward[float32])
+	t.Run("float64", testBroadcastToForward[float64])
+}
+
+func testBroadcastToForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value:        mat.NewDense(2, 1, []T{1, 2}),
+		requiresGrad: true,
+	}
+
+	f := NewBroadcastTo(x, 2, 3)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.Equal(t, 2, y.Rows())
+	assert.Equal(t, 3, y.Columns())
+	assert.InDeltaSlice(t, []T{1, 1, 1, 2, 2, 2}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(2, 3, []T{1, 2, 3, 4, 5, 6}))
+	assert.Equal(t, 2, x.grad.Rows())
+	assert.Equal(t, 1, x.grad.Columns())
+	assert.InDeltaSlice(t, []T{6, 15}, x.grad.Data(), 1.0e-6)
+
+	assert.Panics(t, func() {
+		f.Backward(mat.NewDense(2, 1, []T{1, 2}))
+	})
+}
+
+func TestSumTo_Forward(t *testing.T) {
+	t.Run("float32", testSumToForward[float32])
+	t.Run("float64", testSumToForward[float64])
+}
+
+func testSumToForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value:        mat.NewDense(2, 3, []T{1, 2, 3, 4, 5, 6}),
+		requiresGrad: true,
+	}
+
+	f := NewSumTo(x, 1, 3)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.Equal(t, 1, y.Rows())
+	assert.Equal(t, 3, y.Columns())
+	assert.InDeltaSlice(t, []T{5, 7, 9}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(1, 3, []T{1, 2, 3}))
+	assert.Equal(t, 2, x.grad.Rows())
+	assert.Equal(t, 3, x.grad.Columns())
+	assert.InDeltaSlice(t, []T{1, 2, 3, 1, 2, 3}, x.grad.Data(), 1.0e-6)
+}
+
+func TestAdd_Broadcast(t *testing.T) {
+	t.Run("float32", testAddBroadcast[float32])
+	t.Run("float64", testAddBroadcast[float64])
+}
+
+func testAddBroadcast[T float.DType](t *testing.T) {
+	x1 := &variable{
+		value:        mat.NewDense(2, 3, []T{1, 2, 3, 4, 5, 6}),
+		requiresGrad: true,
+	}
+	x2 := &variable{
+		value:        mat.NewDense(1, 3, []T{10, 20, 30}),
+		requiresGrad: true,
+	}
+
+	f := NewAdd(x1, x2)
+	y := f.Forward()
+	assert.InDeltaSlice(t, []T{11, 22, 33, 14, 25, 36}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(2, 3, []T{1, 2, 3, 4, 5, 6}))
+	assert.InDeltaSlice(t, []T{1, 2, 3, 4, 5, 6}, x1.grad.Data(), 1.0e-6)
+	assert.Equal(t, 1, x2.grad.Rows())
+	assert.Equal(t, 3, x2.grad.Columns())
+	assert.InDeltaSlice(t, []T{5, 7, 9}, x2.grad.Data(), 1.0e-6)
+
+	assert.Panics(t, func() {
+		f.Backward(mat.NewDense(1, 3, []T{1, 2, 3}))
+	})
+}
+
+func TestProd_Broadcast(t *testing.T) {
+	t.Run("float32", testProdBroadcast[float32])
+	t.Run("float64", testProdBroadcast[float64])
+}
+
+func testProdBroadcast[T float.DType](t *testing.T) {
+	x1 := &variable{
+		value:        mat.NewDense(2, 1, []T{2, 3}),
+		requiresGrad: true,
+	}
+	x2 := &variable{
+		value:        mat.NewDense(1, 3, []T{1, 2, 3}),
+		requiresGrad: true,
+	}
+
+	f := NewProd(x1, x2)
+	y := f.Forward()
+	assert.Equal(t, 2, y.Rows())
+	assert.Equal(t, 3, y.Columns())
+	assert.InDeltaSlice(t, []T{2, 4, 6, 3, 6, 9}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(2, 3, []T{1, 1, 1, 1, 0, -1}))
+	assert.InDeltaSlice(t, []T{6, -2}, x1.grad.Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{5, 2, -1}, x2.grad.Data(), 1.0e-6)
+}
@@ -45,3 +45,22 @@ func (r *CELU[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *CELU[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x.RequiresGrad() {
+		alpha := newScalarConstant(g, r.x, r.alpha.Value().Scalar().F64())
+		lower := g.NewOperator(NewExp(g.NewOperator(NewDivScalar(r.x, alpha))))
+		df := piecewiseDerivGraph(g, r.x, 0, 1, lower)
+		gxs[0] = g.NewOperator(NewProd(df, gy))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *CELU[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return r.x.Value().ApplyWithAlpha(celuDeriv, r.alpha.Value().Scalar().F64()).ProdInPlace(t)
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// Cholesky is a Function computing the lower triangular matrix y of the
+// Cholesky decomposition of a symmetric positive-definite matrix x, such
+// that x = y·yᵀ (see mat.Cholesky).
+//
+// Since only the lower triangular part of x is read, the gradients are
+// symmetrized, as if the function were applied to (x + xᵀ) / 2.
+type Cholesky[O Operand] struct {
+	x O
+	y mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewCholesky returns a new Cholesky Function.
+func NewCholesky[O Operand](x O) *Cholesky[O] {
+	return &Cholesky[O]{
+		x: x,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *Cholesky[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *Cholesky[O]) Forward() mat.Matrix {
+	r.y = mat.Cholesky(r.x.Value())
+	return r.y
+}
+
+// Backward computes the backward pass.
+func (r *Cholesky[O]) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x.Value(), gy) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		// s = y⁻ᵀ·Φ(yᵀ·tril(gy))·y⁻¹
+		// gx = (s + sᵀ) / 2
+		gyL := triangularPart(gy, 1)
+		defer mat.ReleaseMatrix(gyL)
+		yT := r.y.T()
+		defer mat.ReleaseMatrix(yT)
+		tmp := yT.Mul(gyL)
+		defer mat.ReleaseMatrix(tmp)
+		p := triangularPart(tmp, 0.5)
+		defer mat.ReleaseMatrix(p)
+		s := r.sandwich(p)
+		defer mat.ReleaseMatrix(s)
+		gx := symmetrize(s)
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Cholesky[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	y := g.NewOperator(NewCholesky(r.x))
+	yInv := g.NewOperator(NewInverse(y))
+	ones := r.y.OnesLike()
+	defer mat.ReleaseMatrix(ones)
+
+	gyL := g.NewOperator(NewProd(gy, g.NewConstant(triangularPart(ones, 1))))
+	yTgyL := g.NewOperator(NewMul(g.NewOperator(NewTranspose(y)), gyL))
+	p := g.NewOperator(NewProd(yTgyL, g.NewConstant(triangularPart(ones, 0.5))))
+	s := g.NewOperator(NewMul(g.NewOperator(NewMul(g.NewOperator(NewTranspose(yInv)), p)), yInv))
+	sum := g.NewOperator(NewAdd(s, g.NewOperator(NewTranspose(s))))
+	return []O{g.NewOperator(NewProdScalar(sum, newScalarConstant(g, gy, 0.5)))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Cholesky[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		// y·Φ(y⁻¹·sym(t)·y⁻ᵀ)
+		st := symmetrize(t)
+		defer mat.ReleaseMatrix(st)
+		m := solveBothSides(r.y, st)
+		defer mat.ReleaseMatrix(m)
+		phi := triangularPart(m, 0.5)
+		defer mat.ReleaseMatrix(phi)
+		return r.y.Mul(phi)
+	})
+}
+
+// sandwich returns y⁻ᵀ·m·y⁻¹.
+func (r *Cholesky[O]) sandwich(m mat.Matrix) mat.Matrix {
+	yT := r.y.T()
+	defer mat.ReleaseMatrix(yT)
+	return solveBothSides(yT, m)
+}
+
+// solveBothSides returns a⁻¹·m·a⁻ᵀ, without computing the inverse of the
+// square matrix a explicitly, by solving the linear systems (see mat.Solve)
+//
+//	a·b = m
+//	a·cᵀ = bᵀ
+//
+// where c = b·a⁻ᵀ is the result.
+func solveBothSides(a, m mat.Matrix) mat.Matrix {
+	b := mat.Solve(a, m)
+	defer mat.ReleaseMatrix(b)
+	bT := b.T()
+	defer mat.ReleaseMatrix(bT)
+	cT := mat.Solve(a, bT)
+	defer mat.ReleaseMatrix(cT)
+	return cT.T()
+}
+
+// triangularPart returns a new matrix with the lower triangular part of m,
+// whose diagonal is multiplied by diag, and zeros above the diagonal.
+func triangularPart(m mat.Matrix, diag float64) mat.Matrix {
+	return m.Apply(func(i, j int, v float64) float64 {
+		switch {
+		case i > j:
+			return v
+		case i == j:
+			return v * diag
+		default:
+			return 0
+		}
+	})
+}
+
+// symmetrize returns a new matrix (m + mᵀ) / 2.
+func symmetrize(m mat.Matrix) mat.Matrix {
+	mT := m.T()
+	defer mat.ReleaseMatrix(mT)
+	return m.Add(mT).ProdScalarInPlace(0.5)
+}
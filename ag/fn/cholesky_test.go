@@ -0,0 +1,50 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCholesky_Forward(t *testing.T) {
+	t.Run("float32", testCholeskyForward[float32])
+	t.Run("float64", testCholeskyForward[float64])
+}
+
+func testCholeskyForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value:        mat.NewDense(2, 2, []T{4, 2, 2, 5}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewCholesky(x)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.InDeltaSlice(t, []T{2, 0, 1, 2}, y.Data(), 1.0e-6)
+
+	// the gradients above the diagonal are ignored
+	f.Backward(mat.NewDense(2, 2, []T{1, 5, 1, 0}))
+	assert.InDeltaSlice(t, []T{0.125, 0.25, 0.25, 0}, x.grad.Data(), 1.0e-6)
+}
+
+func TestSolveBothSides(t *testing.T) {
+	t.Run("float32", testSolveBothSides[float32])
+	t.Run("float64", testSolveBothSides[float64])
+}
+
+func testSolveBothSides[T float.DType](t *testing.T) {
+	a := mat.NewDense(2, 2, []T{2, 0, 1, 3})
+	m := mat.NewDense(2, 2, []T{1, 2, 3, 4})
+
+	// a⁻¹·m·a⁻ᵀ
+	y := solveBothSides(a, m)
+	assert.InDeltaSlice(t, []T{0.25, 0.25, 0.416667, 0.194444}, y.Data(), 1.0e-6)
+}
@@ -47,3 +47,20 @@ func (r *ColView[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *ColView[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	rows, cols := r.x.Value().Dims()
+	indices := rangeIndices(cols, 0, r.i, rows, r.i+1)
+	return []O{g.NewOperator(&scatter[O]{x: gy, rows: rows, cols: cols, indices: indices})}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *ColView[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return t.ExtractColumn(r.i)
+	})
+}
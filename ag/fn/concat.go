@@ -59,3 +59,28 @@ func (r *Concat[O]) Backward(gy mat.Matrix) {
 		mat.ReleaseMatrix(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Concat[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, len(r.xs))
+	offset := 0
+	for i, x := range r.xs {
+		rows, cols := x.Value().Dims()
+		size := rows * cols
+		if x.RequiresGrad() {
+			indices := rangeIndices(1, offset, 0, offset+size, 1)
+			gxs[i] = g.NewOperator(&gather[O]{x: gy, rows: rows, cols: cols, indices: indices})
+		}
+		offset += size
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Concat[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	ts := tangentsOrZeros(tangents, r.xs)
+	if ts == nil {
+		return nil
+	}
+	return ts[0].NewConcatV(ts...)
+}
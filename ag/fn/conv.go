@@ -0,0 +1,342 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// PaddingMode specifies the values of the padding of a convolution.
+type PaddingMode int
+
+const (
+	// ZerosPadding pads the input with zeros.
+	ZerosPadding PaddingMode = iota
+	// ReflectPadding pads the input with the reflection of its values,
+	// without repeating the values at the edges (e.g. 2, 1 | 0, 1, 2).
+	ReflectPadding
+	// ReplicatePadding pads the input with the repetition of the values at
+	// the edges (e.g. 0, 0 | 0, 1, 2).
+	ReplicatePadding
+	// CircularPadding pads the input with the values wrapped around from the
+	// opposite edge (e.g. 1, 2 | 0, 1, 2).
+	CircularPadding
+)
+
+// ConvConfig provides the hyper-parameters of a convolution.
+//
+// Stride, Dilation and Padding have one value for each spatial dimension of
+// the convolution, that is the length for the 1D convolution, and the height
+// and the width for the 2D convolution. A nil slice sets the default value
+// for all the dimensions.
+type ConvConfig struct {
+	// Stride is the step between the positions of the kernel (default 1).
+	Stride []int
+	// Dilation is the step between the elements of the input multiplied by
+	// consecutive elements of the kernel (default 1).
+	Dilation []int
+	// Padding is the number of values added at both sides of the input
+	// (default 0). The padding which preserves the size of the input, for
+	// odd kernel sizes and stride 1, is dilation·(kernelSize-1)/2.
+	Padding []int
+	// PaddingMode specifies the values added by the padding.
+	PaddingMode PaddingMode
+	// Groups is the number of groups in which the input and the output
+	// channels are divided, so that each group of output channels is
+	// connected to the corresponding group of input channels only
+	// (default 1). It must divide the number of both input and output
+	// channels. Setting it to the number of input channels results in a
+	// depth-wise convolution.
+	Groups int
+}
+
+// Conv is a Function performing a 1D or 2D convolution (more precisely, a
+// cross-correlation, as usual in neural networks), computed as a single
+// matrix multiplication of the kernels by the patches of the input (im2col).
+//
+// The input and the kernels are tensors (see mat.TensorView): the elements
+// of their values, in row-major order, are taken with the shapes given to
+// NewConv1D and NewConv2D, regardless of the dimensions of the matrices.
+type Conv[O Operand] struct {
+	x           O
+	w           O
+	outChannels int
+	groups      int
+	xSize       int // the size of the input
+	wSize       int // the size of the kernels
+	outShape    []int
+	positions   int // the number of positions of the kernel over the input
+	// indices are the flat positions, in the input, of the elements of the
+	// im2col matrix, or -1 for the padding zeros
+	indices []int
+	cols    mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewConv1D returns a new Conv Function performing the 1D convolution of
+// the input x, with shape [inChannels, length], by the kernels w, with
+// shape [outChannels, inChannels/groups, kernelSize].
+//
+// The output has shape [outChannels, outLength] (see Conv.OutputShape),
+// being outLength = (length + 2·padding - dilation·(kernelSize-1) - 1) / stride + 1.
+func NewConv1D[O Operand](x, w O, xShape, wShape []int, config ConvConfig) *Conv[O] {
+	if len(xShape) != 2 || len(wShape) != 3 {
+		panic(fmt.Sprintf("fn: invalid shapes %v and %v for a 1D convolution", xShape, wShape))
+	}
+	r := newConv(x, w, []int{xShape[0], 1, xShape[1]}, []int{wShape[0], wShape[1], 1, wShape[2]}, config, 1)
+	r.outShape = []int{r.outShape[0], r.outShape[2]}
+	return r
+}
+
+// NewConv2D returns a new Conv Function performing the 2D convolution of
+// the input x, with shape [inChannels, height, width], by the kernels w,
+// with shape [outChannels, inChannels/groups, kernelHeight, kernelWidth].
+//
+// The output has shape [outChannels, outHeight, outWidth] (see
+// Conv.OutputShape), where each output dimension is computed as for
+// NewConv1D.
+func NewConv2D[O Operand](x, w O, xShape, wShape []int, config ConvConfig) *Conv[O] {
+	if len(xShape) != 3 || len(wShape) != 4 {
+		panic(fmt.Sprintf("fn: invalid shapes %v and %v for a 2D convolution", xShape, wShape))
+	}
+	return newConv(x, w, xShape, wShape, config, 2)
+}
+
+// newConv returns a new Conv Function performing the 2D convolution of x,
+// with shape [inChannels, height, width], by w, with shape [outChannels,
+// inChannels/groups, kernelHeight, kernelWidth]. The config has the given
+// number of spatial dimensions, which are the last ones.
+func newConv[O Operand](x, w O, xShape, wShape []int, config ConvConfig, dims int) *Conv[O] {
+	stride := convParams(config.Stride, dims, 1, "stride")
+	dilation := convParams(config.Dilation, dims, 1, "dilation")
+	padding := convParams(config.Padding, dims, 0, "padding")
+	groups := config.Groups
+	if groups == 0 {
+		groups = 1
+	}
+
+	inChannels, outChannels := xShape[0], wShape[0]
+	if groups < 0 || inChannels%groups != 0 || outChannels%groups != 0 || wShape[1] != inChannels/groups {
+		panic(fmt.Sprintf("fn: invalid shapes %v and %v for a convolution with %d groups", xShape, wShape, groups))
+	}
+	for _, v := range [][]int{stride, dilation} {
+		if v[0] <= 0 || v[1] <= 0 {
+			panic("fn: the stride and the dilation of a convolution must be positive")
+		}
+	}
+
+	outShape := []int{outChannels, 0, 0}
+	for d := 0; d < 2; d++ {
+		size, kernel := xShape[d+1], wShape[d+2]
+		if padding[d] < 0 || padding[d] > 0 && size == 0 {
+			panic(fmt.Sprintf("fn: invalid padding %d", padding[d]))
+		}
+		extent := size + 2*padding[d] - dilation[d]*(kernel-1)
+		if kernel <= 0 || extent <= 0 {
+			panic("fn: the kernel is larger than the padded input")
+		}
+		outShape[d+1] = (extent-1)/stride[d] + 1
+	}
+
+	r := &Conv[O]{
+		x:           x,
+		w:           w,
+		outChannels: outChannels,
+		groups:      groups,
+		xSize:       mat.ShapeSize(xShape),
+		wSize:       mat.ShapeSize(wShape),
+		outShape:    outShape,
+		positions:   outShape[1] * outShape[2],
+	}
+	r.indices = im2colIndices(xShape, wShape[2:], outShape[1:], stride, dilation, padding, config.PaddingMode)
+	return r
+}
+
+// convParams returns the values of a hyper-parameter for the two spatial
+// dimensions of a 2D convolution, given the values for the last dims
+// dimensions, or nil for the default value.
+func convParams(values []int, dims, defaultValue int, name string) []int {
+	params := []int{defaultValue, defaultValue}
+	if values == nil {
+		return params
+	}
+	if len(values) != dims {
+		panic(fmt.Sprintf("fn: invalid convolution %s %v for %d dimensions", name, values, dims))
+	}
+	copy(params[2-dims:], values)
+	return params
+}
+
+// im2colIndices returns, for each element of the im2col matrix of an input
+// with shape [channels, height, width], the flat position of the
+// corresponding element of the input, or -1 for the padding zeros.
+//
+// The im2col matrix has a row for each element of a kernel, ordered by
+// channel, row and column, and a column for each position of the kernel.
+func im2colIndices(xShape, kernel, out, stride, dilation, padding []int, mode PaddingMode) []int {
+	channels, height, width := xShape[0], xShape[1], xShape[2]
+	n := out[0] * out[1]
+	indices := make([]int, 0, channels*kernel[0]*kernel[1]*n)
+	for c := 0; c < channels; c++ {
+		for ki := 0; ki < kernel[0]; ki++ {
+			for kj := 0; kj < kernel[1]; kj++ {
+				for oi := 0; oi < out[0]; oi++ {
+					i := paddedIndex(oi*stride[0]+ki*dilation[0]-padding[0], height, mode)
+					for oj := 0; oj < out[1]; oj++ {
+						j := paddedIndex(oj*stride[1]+kj*dilation[1]-padding[1], width, mode)
+						if i < 0 || j < 0 {
+							indices = append(indices, -1)
+							continue
+						}
+						indices = append(indices, (c*height+i)*width+j)
+					}
+				}
+			}
+		}
+	}
+	return indices
+}
+
+// paddedIndex maps the index i, along a dimension of the given size which
+// is padded according to the mode, to an index of the input, or to -1 if
+// the element is a padding zero.
+func paddedIndex(i, size int, mode PaddingMode) int {
+	if i >= 0 && i < size {
+		return i
+	}
+	switch mode {
+	case ZerosPadding:
+		return -1
+	case ReflectPadding:
+		if size == 1 {
+			return 0
+		}
+		period := 2 * (size - 1)
+		i = ((i % period) + period) % period
+		if i >= size {
+			i = period - i
+		}
+		return i
+	case ReplicatePadding:
+		if i < 0 {
+			return 0
+		}
+		return size - 1
+	case CircularPadding:
+		return ((i % size) + size) % size
+	default:
+		panic(fmt.Sprintf("fn: invalid padding mode %d", mode))
+	}
+}
+
+// OutputShape returns the shape of the output tensor.
+func (r *Conv[O]) OutputShape() []int {
+	return append([]int{}, r.outShape...)
+}
+
+// Operands returns the list of operands.
+func (r *Conv[O]) Operands() []O {
+	return []O{r.x, r.w}
+}
+
+// Forward computes the output of the function.
+func (r *Conv[O]) Forward() mat.Matrix {
+	xv, wv := r.x.Value(), r.w.Value()
+	if xv.Size() != r.xSize || wv.Size() != r.wSize {
+		panic("fn: the sizes of the matrices do not match the shapes of the convolution")
+	}
+	r.cols = r.im2col(xv)
+	return r.conv(r.cols, wv)
+}
+
+// Backward computes the backward pass.
+//
+// Given y = w·cols for each group, the gradients are gw = gy·colsᵀ and
+// gcols = wᵀ·gy, whose elements are then summed at the positions of the
+// input they come from (col2im).
+func (r *Conv[O]) Backward(gy mat.Matrix) {
+	if rows, cols := mat.TensorMatrixDims(r.outShape); gy.Rows() != rows || gy.Columns() != cols {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	gy = gy.Reshape(r.outChannels, r.positions)
+	defer mat.ReleaseMatrix(gy)
+
+	if r.w.RequiresGrad() {
+		gw := mat.BatchMul(gy, r.cols, r.groups, false, true)
+		defer mat.ReleaseMatrix(gw)
+		r.w.AccGrad(gw.ReshapeInPlace(r.w.Value().Dims()))
+	}
+	if r.x.RequiresGrad() {
+		w := r.stackedKernels(r.w.Value())
+		defer mat.ReleaseMatrix(w)
+		gcols := mat.BatchMul(w, gy, r.groups, true, false)
+		defer mat.ReleaseMatrix(gcols)
+		x := r.x.Value()
+		gx := scatterData(gcols, x.Rows(), x.Columns(), r.indices)
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Conv[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	rows, n := r.im2colDims()
+	gy = g.NewOperator(NewReshape(gy, r.outChannels, n))
+	if r.w.RequiresGrad() {
+		cols := g.NewOperator(&gather[O]{x: r.x, rows: rows, cols: n, indices: r.indices})
+		gw := g.NewOperator(NewBatchMul(gy, cols, r.groups, false, true))
+		rows, columns := r.w.Value().Dims()
+		gxs[1] = g.NewOperator(NewReshape(gw, rows, columns))
+	}
+	if r.x.RequiresGrad() {
+		w := g.NewOperator(NewReshape(r.w, r.outChannels, rows/r.groups))
+		gcols := g.NewOperator(NewBatchMul(w, gy, r.groups, true, false))
+		rows, columns := r.x.Value().Dims()
+		gxs[0] = g.NewOperator(&scatter[O]{x: gcols, rows: rows, cols: columns, indices: r.indices})
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Conv[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	var ts []mat.Matrix
+	if tx := tangents[0]; tx != nil {
+		cols := r.im2col(tx)
+		defer mat.ReleaseMatrix(cols)
+		ts = append(ts, r.conv(cols, r.w.Value()))
+	}
+	if tw := tangents[1]; tw != nil {
+		ts = append(ts, r.conv(r.cols, tw))
+	}
+	return sumTangents(ts...)
+}
+
+// im2col returns the im2col matrix of the input x.
+func (r *Conv[O]) im2col(x mat.Matrix) mat.Matrix {
+	rows, cols := r.im2colDims()
+	return gatherData(x, rows, cols, r.indices)
+}
+
+// im2colDims returns the dimensions of the im2col matrix of the input.
+func (r *Conv[O]) im2colDims() (rows, cols int) {
+	return len(r.indices) / r.positions, r.positions
+}
+
+// conv returns the output of the convolution, given the im2col matrix of
+// the input and the kernels.
+func (r *Conv[O]) conv(cols, w mat.Matrix) mat.Matrix {
+	sw := r.stackedKernels(w)
+	defer mat.ReleaseMatrix(sw)
+	y := mat.BatchMul(sw, cols, r.groups, false, false)
+	return y.ReshapeInPlace(mat.TensorMatrixDims(r.outShape))
+}
+
+// stackedKernels returns a copy of the kernels w as a stack of matrices, one
+// for each group, with a row for each output channel of the group.
+func (r *Conv[O]) stackedKernels(w mat.Matrix) mat.Matrix {
+	return w.Reshape(r.outChannels, w.Size()/r.outChannels)
+}
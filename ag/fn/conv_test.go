@@ -0,0 +1,185 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConv2D_Forward(t *testing.T) {
+	t.Run("float32", testConv2DForward[float32])
+	t.Run("float64", testConv2DForward[float64])
+}
+
+func testConv2DForward[T float.DType](t *testing.T) {
+	// two channels of 3×3 values
+	x := &variable{
+		value: mat.NewDense(6, 3, []T{
+			0.1, -0.2, 0.3,
+			0.4, 0.5, -0.6,
+			0.7, -0.8, 0.9,
+			-0.1, 0.2, 0.6,
+			0.3, -0.5, 0.4,
+			0.8, 0.2, -0.7,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	// two output channels of two 2×2 kernels
+	w := &variable{
+		value:        mat.NewVecDense([]T{0.5, -0.4, 0.3, 0.3, 0.2, 0.1, -0.3, 0.6, -0.2, 0.7, 0.4, -0.1, 0.9, -0.5, 0.2, 0.3}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewConv2D(x, w, []int{2, 3, 3}, []int{2, 2, 2, 2}, ConvConfig{
+		Stride:   []int{2, 1},
+		Dilation: []int{1, 2},
+		Padding:  []int{1, 1},
+	})
+	assert.Equal(t, []*variable{x, w}, f.Operands())
+	assert.Equal(t, []int{2, 2, 3}, f.OutputShape())
+
+	y := f.Forward()
+	assert.Equal(t, 4, y.Rows())
+	assert.Equal(t, 3, y.Columns())
+	assert.InDeltaSlice(t, []T{
+		0.06, 0.51, -0.12,
+		-0.37, 0.36, -0.15,
+		0.08, 0.17, -0.04,
+		0.74, -0.29, -0.83,
+	}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(4, 3, []T{
+		0.1, -0.2, 0.3,
+		-0.4, 0.5, -0.6,
+		0.7, -0.8, 0.9,
+		-1.0, 1.1, -1.2,
+	}))
+	assert.InDeltaSlice(t, []T{
+		-0.38, 0.41, 0.02,
+		0.03, -0.6, 0.57,
+		0.59, -0.68, 0.04,
+		-0.1, 0.36, -0.36,
+		1.09, -0.74, -0.5,
+		0.07, -0.6, 0.63,
+	}, x.grad.Data(), 1.0e-6)
+	assert.Equal(t, 16, w.grad.Rows())
+	assert.InDeltaSlice(t, []T{-0.1, -0.5, 0.75, 0.69, 0.45, 0.4, 0.36, -0.53, -0.16, -1.16, 1.47, 1.41, 0.93, 0.94, 0.9, -1.31}, w.grad.Data(), 1.0e-6)
+
+	assert.Panics(t, func() { f.Backward(mat.NewEmptyDense[T](2, 6)) })
+}
+
+func TestConv1D_Forward(t *testing.T) {
+	t.Run("float32", testConv1DForward[float32])
+	t.Run("float64", testConv1DForward[float64])
+}
+
+func testConv1DForward[T float.DType](t *testing.T) {
+	newX := func() *variable {
+		return &variable{
+			value: mat.NewDense(4, 5, []T{
+				0.2, 0.1, 0.5, 0.8, -0.3,
+				0.4, -0.3, -0.2, -0.3, 0.6,
+				0.5, -0.6, -0.4, 0.6, 0.1,
+				-0.3, 0.9, 0.5, 0.5, -0.2,
+			}),
+			grad:         nil,
+			requiresGrad: true,
+		}
+	}
+	newW := func() *variable {
+		return &variable{
+			value: mat.NewDense(4, 2, []T{
+				0.5, -0.4,
+				0.3, 0.3,
+				0.2, -0.6,
+				0.4, 0.1,
+			}),
+			grad:         nil,
+			requiresGrad: true,
+		}
+	}
+
+	testCases := []struct {
+		mode PaddingMode
+		y    []T
+	}{
+		{ZerosPadding, []T{0.04, -0.13, -0.04, -0.45, 0.49, 0.31, 0.03, -0.33, 0.45, 0.27, -0.07, 0.04, 0.32, -0.06}},
+		{ReflectPadding, []T{0.23, -0.17, -0.04, -0.45, 0.49, -0.1, -0.23, -0.21, 0.69, 0.27, -0.07, 0.04, 0.01, 0.23}},
+		{ReplicatePadding, []T{0.26, 0.09, -0.04, -0.45, 0.49, 0.61, 0.33, -0.35, 0.43, 0.27, -0.07, 0.04, 0.24, -0.14}},
+		{CircularPadding, []T{0.35, -0.1, -0.04, -0.45, 0.49, 0.35, -0.1, -0.01, 0.39, 0.27, -0.07, 0.04, -0.01, 0.39}},
+	}
+
+	for _, tc := range testCases {
+		x, w := newX(), newW()
+		// two groups of two input channels and one output channel
+		f := NewConv1D(x, w, []int{4, 5}, []int{2, 2, 2}, ConvConfig{
+			Dilation:    []int{2},
+			Padding:     []int{2},
+			PaddingMode: tc.mode,
+			Groups:      2,
+		})
+		assert.Equal(t, []int{2, 7}, f.OutputShape())
+
+		y := f.Forward()
+		assert.Equal(t, 2, y.Rows())
+		assert.Equal(t, 7, y.Columns())
+		assert.InDeltaSlice(t, tc.y, y.Data(), 1.0e-6)
+
+		if tc.mode != ReflectPadding {
+			continue
+		}
+		f.Backward(mat.NewDense(2, 7, []T{
+			0.1, -0.2, 0.3, -0.4, 0.5, -0.6, 0.7,
+			-0.8, 0.9, -1.0, 1.1, -1.2, 1.3, -1.4,
+		}))
+		assert.InDeltaSlice(t, []T{
+			0.11, -0.22, -0.1, 0.1, 0.15,
+			0.12, -0.24, 0.48, -0.48, 0.36,
+			0.28, -0.14, 1.04, -1.18, 0.44,
+			-0.48, 0.89, -1.04, 0.76, -0.68,
+		}, x.grad.Data(), 1.0e-6)
+		assert.InDeltaSlice(t, []T{
+			-0.39, -0.45,
+			0.78, 0.5,
+			-0.26, 1.34,
+			2.03, 1.29,
+		}, w.grad.Data(), 1.0e-6)
+	}
+}
+
+func TestConv_InvalidArguments(t *testing.T) {
+	x := &variable{value: mat.NewEmptyDense[float64](4, 5)}
+	w := &variable{value: mat.NewEmptyDense[float64](4, 2)}
+
+	assert.Panics(t, func() { NewConv1D(x, w, []int{4, 5}, []int{2, 2, 2}, ConvConfig{}) }, "wrong input channels")
+	assert.Panics(t, func() { NewConv1D(x, w, []int{4, 5}, []int{2, 2, 2}, ConvConfig{Groups: 3}) }, "indivisible groups")
+	assert.Panics(t, func() { NewConv1D(x, w, []int{4, 5}, []int{2, 2, 2}, ConvConfig{Groups: 2, Stride: []int{1, 1}}) }, "2D stride")
+	assert.Panics(t, func() { NewConv1D(x, w, []int{4, 5}, []int{2, 2, 2}, ConvConfig{Groups: 2, Stride: []int{0}}) }, "zero stride")
+	assert.Panics(t, func() { NewConv1D(x, w, []int{4, 5}, []int{2, 2, 2}, ConvConfig{Groups: 2, Dilation: []int{5}}) }, "large kernel")
+	assert.Panics(t, func() { NewConv1D(x, w, []int{2, 5}, []int{2, 2, 2}, ConvConfig{Groups: 1}).Forward() }, "wrong input size")
+	assert.Panics(t, func() { NewConv2D(x, w, []int{4, 5}, []int{2, 2, 2}, ConvConfig{}) }, "1D shapes")
+	assert.NotPanics(t, func() { NewConv1D(x, w, []int{4, 5}, []int{2, 2, 2}, ConvConfig{Groups: 2}).Forward() })
+}
+
+func TestPaddedIndex(t *testing.T) {
+	indices := func(mode PaddingMode) []int {
+		var out []int
+		for i := -4; i < 7; i++ {
+			out = append(out, paddedIndex(i, 3, mode))
+		}
+		return out
+	}
+	assert.Equal(t, []int{-1, -1, -1, -1, 0, 1, 2, -1, -1, -1, -1}, indices(ZerosPadding))
+	assert.Equal(t, []int{0, 1, 2, 1, 0, 1, 2, 1, 0, 1, 2}, indices(ReflectPadding))
+	assert.Equal(t, []int{0, 0, 0, 0, 0, 1, 2, 2, 2, 2, 2}, indices(ReplicatePadding))
+	assert.Equal(t, []int{2, 0, 1, 2, 0, 1, 2, 0, 1, 2, 0}, indices(CircularPadding))
+	assert.Equal(t, 0, paddedIndex(-2, 1, ReflectPadding))
+}
@@ -0,0 +1,247 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// ConvTransposeConfig provides the hyper-parameters of a transposed
+// convolution. Stride, Dilation, Padding and Groups are the ones of the
+// convolution being transposed (see ConvConfig), so that a transposed
+// convolution maps the output of that convolution back to the shape of its
+// input. The padding is always made of zeros.
+type ConvTransposeConfig struct {
+	// Stride is the step between the positions of the kernel over the
+	// output (default 1).
+	Stride []int
+	// Dilation is the step between the elements of the output produced by
+	// consecutive elements of the kernel (default 1).
+	Dilation []int
+	// Padding is the number of values removed from both sides of the output
+	// (default 0).
+	Padding []int
+	// OutputPadding is the number of values added to one side of the output
+	// (default 0). It must be smaller than either the stride or the
+	// dilation, and it resolves the ambiguity of the output size when the
+	// stride is greater than 1, since many input sizes are mapped to the
+	// same output size by the convolution.
+	OutputPadding []int
+	// Groups is the number of groups in which the input and the output
+	// channels are divided (default 1, see ConvConfig).
+	Groups int
+}
+
+// ConvTranspose is a Function performing a 1D or 2D transposed convolution
+// (also known as fractionally-strided convolution, or deconvolution), which
+// is the gradient of a convolution with respect to its input. Each element of
+// the input is multiplied by the kernels, and the results are summed at the
+// positions of the output they are mapped to (col2im).
+//
+// The input and the kernels are tensors (see mat.TensorView), as for Conv.
+type ConvTranspose[O Operand] struct {
+	x          O
+	w          O
+	inChannels int
+	groups     int
+	xSize      int // the size of the input
+	wSize      int // the size of the kernels
+	outShape   []int
+	positions  int // the number of elements of each input channel
+	// indices are the flat positions, in the output, of the elements of the
+	// col2im matrix, or -1 for the elements removed by the padding
+	indices []int
+}
+
+// NewConvTranspose1D returns a new ConvTranspose Function performing the
+// 1D transposed convolution of the input x, with shape [inChannels, length],
+// by the kernels w, with shape [inChannels, outChannels/groups, kernelSize].
+//
+// The output has shape [outChannels, outLength] (see
+// ConvTranspose.OutputShape), being outLength = (length - 1)·stride -
+// 2·padding + dilation·(kernelSize-1) + outputPadding + 1.
+func NewConvTranspose1D[O Operand](x, w O, xShape, wShape []int, config ConvTransposeConfig) *ConvTranspose[O] {
+	if len(xShape) != 2 || len(wShape) != 3 {
+		panic(fmt.Sprintf("fn: invalid shapes %v and %v for a 1D transposed convolution", xShape, wShape))
+	}
+	r := newConvTranspose(x, w, []int{xShape[0], 1, xShape[1]}, []int{wShape[0], wShape[1], 1, wShape[2]}, config, 1)
+	r.outShape = []int{r.outShape[0], r.outShape[2]}
+	return r
+}
+
+// NewConvTranspose2D returns a new ConvTranspose Function performing the 2D
+// transposed convolution of the input x, with shape [inChannels, height,
+// width], by the kernels w, with shape [inChannels, outChannels/groups,
+// kernelHeight, kernelWidth].
+//
+// The output has shape [outChannels, outHeight, outWidth] (see
+// ConvTranspose.OutputShape), where each output dimension is computed as
+// for NewConvTranspose1D.
+func NewConvTranspose2D[O Operand](x, w O, xShape, wShape []int, config ConvTransposeConfig) *ConvTranspose[O] {
+	if len(xShape) != 3 || len(wShape) != 4 {
+		panic(fmt.Sprintf("fn: invalid shapes %v and %v for a 2D transposed convolution", xShape, wShape))
+	}
+	return newConvTranspose(x, w, xShape, wShape, config, 2)
+}
+
+// newConvTranspose returns a new ConvTranspose Function performing the 2D
+// transposed convolution of x, with shape [inChannels, height, width], by w,
+// with shape [inChannels, outChannels/groups, kernelHeight, kernelWidth].
+// The config has the given number of spatial dimensions, which are the last
+// ones.
+func newConvTranspose[O Operand](x, w O, xShape, wShape []int, config ConvTransposeConfig, dims int) *ConvTranspose[O] {
+	stride := convParams(config.Stride, dims, 1, "stride")
+	dilation := convParams(config.Dilation, dims, 1, "dilation")
+	padding := convParams(config.Padding, dims, 0, "padding")
+	outPadding := convParams(config.OutputPadding, dims, 0, "output padding")
+	groups := config.Groups
+	if groups == 0 {
+		groups = 1
+	}
+
+	inChannels := xShape[0]
+	if groups < 0 || inChannels%groups != 0 || wShape[0] != inChannels {
+		panic(fmt.Sprintf("fn: invalid shapes %v and %v for a transposed convolution with %d groups", xShape, wShape, groups))
+	}
+	for _, v := range [][]int{stride, dilation} {
+		if v[0] <= 0 || v[1] <= 0 {
+			panic("fn: the stride and the dilation of a transposed convolution must be positive")
+		}
+	}
+
+	outShape := []int{wShape[1] * groups, 0, 0}
+	for d := 0; d < 2; d++ {
+		if outPadding[d] < 0 || outPadding[d] >= stride[d] && outPadding[d] >= dilation[d] {
+			panic(fmt.Sprintf("fn: invalid output padding %d", outPadding[d]))
+		}
+		if padding[d] < 0 {
+			panic(fmt.Sprintf("fn: invalid padding %d", padding[d]))
+		}
+		size := (xShape[d+1]-1)*stride[d] - 2*padding[d] + dilation[d]*(wShape[d+2]-1) + outPadding[d] + 1
+		if xShape[d+1] <= 0 || wShape[d+2] <= 0 || size <= 0 {
+			panic("fn: the padding is larger than the output of the transposed convolution")
+		}
+		outShape[d+1] = size
+	}
+
+	r := &ConvTranspose[O]{
+		x:          x,
+		w:          w,
+		inChannels: inChannels,
+		groups:     groups,
+		xSize:      mat.ShapeSize(xShape),
+		wSize:      mat.ShapeSize(wShape),
+		outShape:   outShape,
+		positions:  xShape[1] * xShape[2],
+	}
+	r.indices = im2colIndices(outShape, wShape[2:], xShape[1:], stride, dilation, padding, ZerosPadding)
+	return r
+}
+
+// OutputShape returns the shape of the output tensor.
+func (r *ConvTranspose[O]) OutputShape() []int {
+	return append([]int{}, r.outShape...)
+}
+
+// Operands returns the list of operands.
+func (r *ConvTranspose[O]) Operands() []O {
+	return []O{r.x, r.w}
+}
+
+// Forward computes the output of the function.
+func (r *ConvTranspose[O]) Forward() mat.Matrix {
+	xv, wv := r.x.Value(), r.w.Value()
+	if xv.Size() != r.xSize || wv.Size() != r.wSize {
+		panic("fn: the sizes of the matrices do not match the shapes of the transposed convolution")
+	}
+	return r.convTranspose(xv, wv)
+}
+
+// Backward computes the backward pass.
+//
+// Given cols = wᵀ·x for each group, whose elements are summed into the
+// output, the gradients are gx = w·gcols and gw = x·gcolsᵀ, where gcols are
+// the elements of gy at the positions of the output of each element of cols
+// (im2col). The gradient of the input is therefore a convolution of gy.
+func (r *ConvTranspose[O]) Backward(gy mat.Matrix) {
+	if rows, cols := mat.TensorMatrixDims(r.outShape); gy.Rows() != rows || gy.Columns() != cols {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	gcols := gatherData(gy, len(r.indices)/r.positions, r.positions, r.indices)
+	defer mat.ReleaseMatrix(gcols)
+
+	if r.w.RequiresGrad() {
+		x := r.stackedInput(r.x.Value())
+		defer mat.ReleaseMatrix(x)
+		gw := mat.BatchMul(x, gcols, r.groups, false, true)
+		defer mat.ReleaseMatrix(gw)
+		r.w.AccGrad(gw.ReshapeInPlace(r.w.Value().Dims()))
+	}
+	if r.x.RequiresGrad() {
+		w := r.stackedKernels(r.w.Value())
+		defer mat.ReleaseMatrix(w)
+		gx := mat.BatchMul(w, gcols, r.groups, false, false)
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx.ReshapeInPlace(r.x.Value().Dims()))
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *ConvTranspose[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	gcols := g.NewOperator(&gather[O]{x: gy, rows: len(r.indices) / r.positions, cols: r.positions, indices: r.indices})
+	if r.w.RequiresGrad() {
+		x := g.NewOperator(NewReshape(r.x, r.inChannels, r.positions))
+		gw := g.NewOperator(NewBatchMul(x, gcols, r.groups, false, true))
+		rows, columns := r.w.Value().Dims()
+		gxs[1] = g.NewOperator(NewReshape(gw, rows, columns))
+	}
+	if r.x.RequiresGrad() {
+		w := g.NewOperator(NewReshape(r.w, r.inChannels, r.w.Value().Size()/r.inChannels))
+		gx := g.NewOperator(NewBatchMul(w, gcols, r.groups, false, false))
+		rows, columns := r.x.Value().Dims()
+		gxs[0] = g.NewOperator(NewReshape(gx, rows, columns))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *ConvTranspose[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	var ts []mat.Matrix
+	if tx := tangents[0]; tx != nil {
+		ts = append(ts, r.convTranspose(tx, r.w.Value()))
+	}
+	if tw := tangents[1]; tw != nil {
+		ts = append(ts, r.convTranspose(r.x.Value(), tw))
+	}
+	return sumTangents(ts...)
+}
+
+// convTranspose returns the output of the transposed convolution of the
+// input x by the kernels w.
+func (r *ConvTranspose[O]) convTranspose(x, w mat.Matrix) mat.Matrix {
+	sx := r.stackedInput(x)
+	defer mat.ReleaseMatrix(sx)
+	sw := r.stackedKernels(w)
+	defer mat.ReleaseMatrix(sw)
+	cols := mat.BatchMul(sw, sx, r.groups, true, false)
+	defer mat.ReleaseMatrix(cols)
+	rows, columns := mat.TensorMatrixDims(r.outShape)
+	return scatterData(cols, rows, columns, r.indices)
+}
+
+// stackedInput returns a copy of the input x as a stack of matrices, one for
+// each group, with a row for each input channel of the group.
+func (r *ConvTranspose[O]) stackedInput(x mat.Matrix) mat.Matrix {
+	return x.Reshape(r.inChannels, r.positions)
+}
+
+// stackedKernels returns a copy of the kernels w as a stack of matrices, one
+// for each group, with a row for each input channel of the group.
+func (r *ConvTranspose[O]) stackedKernels(w mat.Matrix) mat.Matrix {
+	return w.Reshape(r.inChannels, w.Size()/r.inChannels)
+}
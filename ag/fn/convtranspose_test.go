@@ -0,0 +1,152 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvTranspose2D_Forward(t *testing.T) {
+	t.Run("float32", testConvTranspose2DForward[float32])
+	t.Run("float64", testConvTranspose2DForward[float64])
+}
+
+func testConvTranspose2DForward[T float.DType](t *testing.T) {
+	// two channels of 2×2 values
+	x := &variable{
+		value: mat.NewDense(4, 2, []T{
+			0.1, -0.2,
+			0.3, 0.4,
+			0.5, -0.6,
+			0.7, 0.8,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	// two input channels of two 2×2 kernels
+	w := &variable{
+		value:        mat.NewVecDense([]T{0.5, -0.4, 0.3, 0.3, 0.2, 0.1, -0.3, 0.6, -0.2, 0.7, 0.4, -0.1, 0.9, -0.5, 0.2, 0.3}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewConvTranspose2D(x, w, []int{2, 2, 2}, []int{2, 2, 2, 2}, ConvTransposeConfig{
+		Stride:        []int{2, 1},
+		Padding:       []int{1, 0},
+		OutputPadding: []int{1, 0},
+	})
+	assert.Equal(t, []*variable{x, w}, f.Operands())
+	assert.Equal(t, []int{2, 3, 3}, f.OutputShape())
+
+	y := f.Forward()
+	assert.Equal(t, 6, y.Rows())
+	assert.Equal(t, 3, y.Columns())
+	assert.InDeltaSlice(t, []T{
+		0.23, -0.32, 0,
+		0.01, 0.41, 0.4,
+		0.37, 0.46, 0.04,
+		0.07, 0.15, -0.3,
+		0.69, 0.48, -0.36,
+		0.05, 0.43, 0.48,
+	}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(6, 3, []T{
+		0.1, -0.2, 0.3,
+		-0.4, 0.5, -0.6,
+		0.7, -0.8, 0.9,
+		-1.0, 1.1, -1.2,
+		1.3, -1.4, 1.5,
+		-1.6, 1.7, -1.8,
+	}))
+	assert.InDeltaSlice(t, []T{0.93, -1.02, 1.19, -1.2, 0.19, -0.25, 2.85, -3.14}, x.grad.Data(), 1.0e-6)
+	assert.Equal(t, 16, w.grad.Rows())
+	assert.InDeltaSlice(t, []T{0.08, -0.09, -0.06, 0.04, -0.17, 0.18, -0.12, 0.14, 0.12, -0.13, 0.02, -0.12, -0.21, 0.22, -0.92, 1.02}, w.grad.Data(), 1.0e-6)
+
+	assert.Panics(t, func() { f.Backward(mat.NewEmptyDense[T](3, 6)) })
+}
+
+func TestConvTranspose1D_Forward(t *testing.T) {
+	t.Run("float32", testConvTranspose1DForward[float32])
+	t.Run("float64", testConvTranspose1DForward[float64])
+}
+
+func testConvTranspose1DForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(4, 3, []T{
+			0.2, 0.1, 0.5,
+			0.4, -0.3, -0.2,
+			0.5, -0.6, -0.4,
+			-0.3, 0.9, 0.5,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	w := &variable{
+		value: mat.NewDense(4, 3, []T{
+			0.5, -0.4, 0.3,
+			0.3, 0.2, -0.6,
+			0.4, 0.1, -0.2,
+			0.7, -0.5, 0.6,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	// two groups of two input channels and one output channel
+	f := NewConvTranspose1D(x, w, []int{4, 3}, []int{4, 1, 3}, ConvTransposeConfig{
+		Stride:        []int{2},
+		Padding:       []int{1},
+		OutputPadding: []int{1},
+		Groups:        2,
+	})
+	assert.Equal(t, []int{2, 6}, f.OutputShape())
+
+	y := f.Forward()
+	assert.Equal(t, 2, y.Rows())
+	assert.Equal(t, 6, y.Columns())
+	assert.InDeltaSlice(t, []T{
+		0, -0.22, -0.1, 0.4, -0.24, 0.27,
+		0.2, 0.11, -0.51, 0.85, -0.29, 0.38,
+	}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(2, 6, []T{
+		0.1, -0.2, 0.3, -0.4, 0.5, -0.6,
+		0.7, -0.8, 0.9, -1.0, 1.1, -1.2,
+	}))
+	assert.InDeltaSlice(t, []T{
+		-0.1, -0.34, -0.58,
+		0.14, 0.24, 0.34,
+		0.23, -0.03, -0.05,
+		-0.83, -1.61, -1.97,
+	}, x.grad.Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{
+		-0.22, 0.3, -0.38,
+		0.14, -0.15, 0.16,
+		0.88, -0.63, 0.68,
+		-1.22, 1.15, -1.26,
+	}, w.grad.Data(), 1.0e-6)
+}
+
+func TestConvTranspose_InvalidArguments(t *testing.T) {
+	x := &variable{value: mat.NewEmptyDense[float64](4, 3)}
+	w := &variable{value: mat.NewEmptyDense[float64](4, 3)}
+
+	assert.Panics(t, func() { NewConvTranspose1D(x, w, []int{4, 3}, []int{2, 2, 3}, ConvTransposeConfig{}) }, "wrong input channels")
+	assert.Panics(t, func() { NewConvTranspose1D(x, w, []int{4, 3}, []int{4, 1, 3}, ConvTransposeConfig{Groups: 3}) }, "indivisible groups")
+	assert.Panics(t, func() { NewConvTranspose1D(x, w, []int{4, 3}, []int{4, 1, 3}, ConvTransposeConfig{Stride: []int{0}}) }, "zero stride")
+	assert.Panics(t, func() {
+		NewConvTranspose1D(x, w, []int{4, 3}, []int{4, 1, 3}, ConvTransposeConfig{OutputPadding: []int{1}})
+	}, "large output padding")
+	assert.Panics(t, func() { NewConvTranspose1D(x, w, []int{4, 3}, []int{4, 1, 3}, ConvTransposeConfig{Padding: []int{3}}) }, "large padding")
+	assert.Panics(t, func() { NewConvTranspose1D(x, w, []int{4, 2}, []int{4, 1, 3}, ConvTransposeConfig{}).Forward() }, "wrong input size")
+	assert.Panics(t, func() { NewConvTranspose2D(x, w, []int{4, 3}, []int{4, 1, 3}, ConvTransposeConfig{}) }, "1D shapes")
+	assert.NotPanics(t, func() {
+		NewConvTranspose1D(x, w, []int{4, 3}, []int{4, 1, 3}, ConvTransposeConfig{Dilation: []int{2}, OutputPadding: []int{1}}).Forward()
+	})
+}
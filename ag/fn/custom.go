@@ -0,0 +1,174 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// CustomForwardFunc computes the output of a Custom function, given the
+// values of the operands.
+type CustomForwardFunc func(xs []mat.Matrix) mat.Matrix
+
+// CustomBackwardFunc computes the gradients of the operands of a Custom
+// function, given the values of the operands, the output y and its
+// gradients gy.
+//
+// It must return one gradient for each operand, in the same order. A nil
+// gradient is equivalent to a matrix of zeros, so it can be returned for the
+// operands that do not require gradients, or that do not contribute to the
+// output. It is still accumulated into the operands that require gradients,
+// since an operator expects the gradients from all of its consumers to
+// complete its own backward step.
+type CustomBackwardFunc func(xs []mat.Matrix, y, gy mat.Matrix) []mat.Matrix
+
+// CustomBackwardGraphFunc computes the gradients of the operands of a Custom
+// function as new nodes of the graph g, given the operands xs and the
+// gradients gy of the output.
+//
+// It must return one gradient for each operand, in the same order. A nil
+// gradient is equivalent to a matrix of zeros, as with CustomBackwardFunc.
+type CustomBackwardGraphFunc[O Operand] func(g Graph[O], xs []O, gy O) []O
+
+// CustomJVPFunc computes the tangent of the output of a Custom function,
+// given the values of the operands, the output y and the tangents of the
+// operands, in the same order. A nil tangent is equivalent to a matrix of
+// zeros.
+type CustomJVPFunc func(xs []mat.Matrix, y mat.Matrix, tangents []mat.Matrix) mat.Matrix
+
+// Custom is a Function defined by a pair of forward and backward functions
+// over matrices, allowing the definition of new differentiable operators
+// without implementing a dedicated type.
+//
+// The graph-based backward pass (see GraphFunction) and the forward-mode
+// differentiation (see JVPFunction) are optional, and are only supported
+// once the related functions are set with WithBackwardGraph and WithJVP.
+type Custom[O Operand] struct {
+	name          string
+	xs            []O
+	forward       CustomForwardFunc
+	backward      CustomBackwardFunc
+	backwardGraph CustomBackwardGraphFunc[O]
+	jvp           CustomJVPFunc
+	y             mat.Matrix // memoized by Forward
+}
+
+var (
+	_ GraphFunction[Operand] = &Custom[Operand]{}
+	_ JVPFunction[Operand]   = &Custom[Operand]{}
+)
+
+// NewCustom returns a new Custom Function.
+// The name is used to identify the function, e.g. for debugging purposes.
+func NewCustom[O Operand](name string, forward CustomForwardFunc, backward CustomBackwardFunc, xs ...O) *Custom[O] {
+	if forward == nil || backward == nil {
+		panic("fn: the forward and backward functions of Custom cannot be nil")
+	}
+	return &Custom[O]{
+		name:     name,
+		xs:       xs,
+		forward:  forward,
+		backward: backward,
+	}
+}
+
+// WithBackwardGraph sets the function computing the backward pass as new
+// nodes of the graph, enabling the computation of higher-order derivatives.
+func (r *Custom[O]) WithBackwardGraph(f CustomBackwardGraphFunc[O]) *Custom[O] {
+	r.backwardGraph = f
+	return r
+}
+
+// WithJVP sets the function computing the Jacobian-vector product, enabling
+// the forward-mode differentiation.
+func (r *Custom[O]) WithJVP(f CustomJVPFunc) *Custom[O] {
+	r.jvp = f
+	return r
+}
+
+// Name returns the name of the function.
+func (r *Custom[O]) Name() string {
+	return r.name
+}
+
+// Operands returns the list of operands.
+func (r *Custom[O]) Operands() []O {
+	return r.xs
+}
+
+// Forward computes the output of the function.
+func (r *Custom[O]) Forward() mat.Matrix {
+	r.y = r.forward(r.values())
+	return r.y
+}
+
+// Backward computes the backward pass.
+func (r *Custom[O]) Backward(gy mat.Matrix) {
+	gxs := r.OperandGrads(gy)
+	for i, x := range r.xs {
+		if x.RequiresGrad() {
+			x.AccGrad(gxs[i])
+		}
+	}
+}
+
+// OperandGrads computes the gradients of the operands, like Backward, but
+// returns them instead of accumulating them into the operands. The gradients
+// of the operands that do not require gradients are nil.
+func (r *Custom[O]) OperandGrads(gy mat.Matrix) []mat.Matrix {
+	xs := r.values()
+	gxs := r.backward(xs, r.y, gy)
+	if len(gxs) != len(r.xs) {
+		panic(fmt.Sprintf("fn: %s: expected %d gradients, actual %d", r.name, len(r.xs), len(gxs)))
+	}
+	for i, x := range r.xs {
+		if !x.RequiresGrad() {
+			gxs[i] = nil
+			continue
+		}
+		if gxs[i] != nil && !mat.SameDims(xs[i], gxs[i]) {
+			panic(fmt.Sprintf("fn: %s: the gradients of operand %d have incompatible dimensions", r.name, i))
+		}
+	}
+	return gxs
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+// It panics if the function was not set with WithBackwardGraph.
+func (r *Custom[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if r.backwardGraph == nil {
+		panic(fmt.Sprintf("fn: %s: graph-based backward is not supported", r.name))
+	}
+	gxs := r.backwardGraph(g, r.xs, gy)
+	if len(gxs) != len(r.xs) {
+		panic(fmt.Sprintf("fn: %s: expected %d gradients, actual %d", r.name, len(r.xs), len(gxs)))
+	}
+	for i, x := range r.xs {
+		if !x.RequiresGrad() {
+			var zero O
+			gxs[i] = zero
+		}
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product of the function.
+// It panics if the function was not set with WithJVP.
+func (r *Custom[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	if r.jvp == nil {
+		panic(fmt.Sprintf("fn: %s: forward-mode differentiation is not supported", r.name))
+	}
+	return r.jvp(r.values(), r.y, tangents)
+}
+
+func (r *Custom[O]) values() []mat.Matrix {
+	xs := make([]mat.Matrix, len(r.xs))
+	for i, x := range r.xs {
+		xs[i] = x.Value()
+	}
+	return xs
+}
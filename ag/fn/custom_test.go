@@ -0,0 +1,160 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustom_Forward(t *testing.T) {
+	t.Run("float32", testCustomForward[float32])
+	t.Run("float64", testCustomForward[float64])
+}
+
+func testCustomForward[T float.DType](t *testing.T) {
+	x1 := &variable{
+		value:        mat.NewVecDense([]T{0.1, 0.2, -0.3}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	x2 := &variable{
+		value:        mat.NewVecDense([]T{0.4, 0.5, 0.6}),
+		grad:         nil,
+		requiresGrad: false,
+	}
+
+	// y = x1 * x2^2
+	f := NewCustom("Foo",
+		func(xs []mat.Matrix) mat.Matrix {
+			return xs[0].Prod(xs[1]).Prod(xs[1])
+		},
+		func(xs []mat.Matrix, y, gy mat.Matrix) []mat.Matrix {
+			return []mat.Matrix{
+				gy.Prod(xs[1]).Prod(xs[1]),
+				gy.Prod(xs[0]).Prod(xs[1]).ProdScalar(2),
+			}
+		},
+		x1, x2,
+	)
+	assert.Equal(t, []*variable{x1, x2}, f.Operands())
+	assert.Equal(t, "Foo", f.Name())
+
+	y := f.Forward()
+	assert.InDeltaSlice(t, []T{0.016, 0.05, -0.108}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewVecDense([]T{-1, 0.5, 1}))
+	assert.InDeltaSlice(t, []T{-0.16, 0.125, 0.36}, x1.grad.Data(), 1.0e-6)
+	assert.Nil(t, x2.grad)
+}
+
+func TestCustom_Backward(t *testing.T) {
+	t.Run("float32", testCustomBackward[float32])
+	t.Run("float64", testCustomBackward[float64])
+}
+
+func testCustomBackward[T float.DType](t *testing.T) {
+	newX := func() *variable {
+		return &variable{
+			value:        mat.NewVecDense([]T{1, 2}),
+			grad:         nil,
+			requiresGrad: true,
+		}
+	}
+	forward := func(xs []mat.Matrix) mat.Matrix { return xs[0].Clone() }
+
+	t.Run("nil gradients are equivalent to zeros", func(t *testing.T) {
+		x := newX()
+		f := NewCustom("Foo", forward, func([]mat.Matrix, mat.Matrix, mat.Matrix) []mat.Matrix {
+			return []mat.Matrix{nil}
+		}, x)
+		f.Forward()
+		f.Backward(mat.NewVecDense([]T{1, 1}))
+		assert.Nil(t, x.grad)
+	})
+
+	t.Run("operand gradients", func(t *testing.T) {
+		x, z := newX(), newX()
+		z.requiresGrad = false
+		f := NewCustom("Foo", forward, func(_ []mat.Matrix, _, gy mat.Matrix) []mat.Matrix {
+			return []mat.Matrix{gy.ProdScalar(2), gy}
+		}, x, z)
+		f.Forward()
+		gxs := f.OperandGrads(mat.NewVecDense([]T{1, -1}))
+		require.Len(t, gxs, 2)
+		assert.Equal(t, []T{2, -2}, mat.Data[T](gxs[0]))
+		assert.Nil(t, gxs[1])
+		assert.Nil(t, x.grad)
+	})
+
+	t.Run("wrong number of gradients", func(t *testing.T) {
+		f := NewCustom("Foo", forward, func([]mat.Matrix, mat.Matrix, mat.Matrix) []mat.Matrix {
+			return nil
+		}, newX())
+		f.Forward()
+		assert.Panics(t, func() { f.Backward(mat.NewVecDense([]T{1, 1})) })
+	})
+
+	t.Run("wrong dimensions of gradients", func(t *testing.T) {
+		f := NewCustom("Foo", forward, func([]mat.Matrix, mat.Matrix, mat.Matrix) []mat.Matrix {
+			return []mat.Matrix{mat.NewVecDense([]T{1, 2, 3})}
+		}, newX())
+		f.Forward()
+		assert.Panics(t, func() { f.Backward(mat.NewVecDense([]T{1, 1})) })
+	})
+
+	t.Run("nil functions", func(t *testing.T) {
+		assert.Panics(t, func() { NewCustom[*variable]("Foo", forward, nil, newX()) })
+		assert.Panics(t, func() { NewCustom[*variable]("Foo", nil, nil, newX()) })
+	})
+}
+
+func TestCustom_BackwardGraph(t *testing.T) {
+	t.Run("float32", testCustomBackwardGraph[float32])
+	t.Run("float64", testCustomBackwardGraph[float64])
+}
+
+func testCustomBackwardGraph[T float.DType](t *testing.T) {
+	newX := func(requiresGrad bool) *variable {
+		return &variable{
+			value:        mat.NewVecDense([]T{1, 2}),
+			grad:         nil,
+			requiresGrad: requiresGrad,
+		}
+	}
+	forward := func(xs []mat.Matrix) mat.Matrix { return xs[0].Clone() }
+	backward := func(xs []mat.Matrix, y, gy mat.Matrix) []mat.Matrix { return []mat.Matrix{gy} }
+	gy := &variable{value: mat.NewVecDense([]T{1, 1})}
+
+	t.Run("operands not requiring gradients", func(t *testing.T) {
+		f := NewCustom("Foo", forward, backward, newX(true), newX(false)).
+			WithBackwardGraph(func(_ Graph[*variable], xs []*variable, gy *variable) []*variable {
+				return []*variable{gy, gy}
+			})
+		f.Forward()
+		gxs := f.BackwardGraph(testGraph{}, gy)
+		assert.Equal(t, []*variable{gy, nil}, gxs)
+	})
+
+	t.Run("wrong number of gradients", func(t *testing.T) {
+		f := NewCustom("Foo", forward, backward, newX(true)).
+			WithBackwardGraph(func(Graph[*variable], []*variable, *variable) []*variable {
+				return nil
+			})
+		f.Forward()
+		assert.Panics(t, func() { f.BackwardGraph(testGraph{}, gy) })
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		f := NewCustom("Foo", forward, backward, newX(true))
+		f.Forward()
+		assert.Panics(t, func() { f.BackwardGraph(testGraph{}, gy) })
+		assert.Panics(t, func() { f.JVP([]mat.Matrix{nil}) })
+	})
+}
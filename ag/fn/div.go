@@ -9,6 +9,9 @@ import (
 )
 
 // Div is an operator to perform element-wise division over two values.
+//
+// The values are broadcast against each other if their dimensions differ
+// (see mat.BroadcastDims), and the gradients are reduced accordingly.
 type Div[O Operand] struct {
 	x1 O
 	x2 O
@@ -34,23 +37,51 @@ func (r *Div[O]) Forward() mat.Matrix {
 
 // Backward computes the backward pass.
 func (r *Div[O]) Backward(gy mat.Matrix) {
-	x1v := r.x1.Value()
-	x2v := r.x2.Value()
-	if !mat.SameDims(x1v, gy) || !mat.SameDims(x2v, gy) {
-		panic("fn: matrices have incompatible dimensions")
-	}
+	broadcastGradDims(r.x1.Value(), r.x2.Value(), gy)
 	if r.x1.RequiresGrad() {
 		gx := gy.Div(r.x2.Value())
 		defer mat.ReleaseMatrix(gx)
-		r.x1.AccGrad(gx)
+		accUnbroadcastGrad(r.x1, gx)
 	}
 	if r.x2.RequiresGrad() {
 		x2sq := r.x2.Value().Prod(r.x2.Value())
 		defer mat.ReleaseMatrix(x2sq)
-		gx := r.x1.Value().Prod(gy)
+		num := r.x1.Value().Prod(gy)
+		defer mat.ReleaseMatrix(num)
+		gx := num.Div(x2sq)
 		defer mat.ReleaseMatrix(gx)
 		gx.ProdScalarInPlace(-1)
-		gx.DivInPlace(x2sq)
-		r.x2.AccGrad(gx)
+		accUnbroadcastGrad(r.x2, gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Div[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x1.RequiresGrad() {
+		gxs[0] = unbroadcastGraph(g, g.NewOperator(NewDiv(gy, r.x2)), r.x1)
 	}
+	if r.x2.RequiresGrad() {
+		num := g.NewOperator(NewProd(r.x1, gy))
+		den := g.NewOperator(NewSquare(r.x2))
+		gxs[1] = unbroadcastGraph(g, g.NewOperator(NewNeg(g.NewOperator(NewDiv(num, den)))), r.x2)
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Div[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	x2v := r.x2.Value()
+	return sumTangents(
+		mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+			return t.Div(x2v)
+		}),
+		mapTangent(tangents[1], func(t mat.Matrix) mat.Matrix {
+			x2Square := x2v.Prod(x2v)
+			defer mat.ReleaseMatrix(x2Square)
+			num := t.Prod(r.x1.Value())
+			defer mat.ReleaseMatrix(num)
+			return num.Div(x2Square).ProdScalarInPlace(-1)
+		}),
+	)
 }
@@ -55,3 +55,27 @@ func (r *DivScalar[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *DivScalar[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x1.RequiresGrad() {
+		gxs[0] = g.NewOperator(NewDivScalar(gy, r.x2))
+	}
+	if r.x2.RequiresGrad() {
+		sum := g.NewOperator(NewReduceSum(g.NewOperator(NewProd(gy, r.x1))))
+		gxs[1] = g.NewOperator(NewNeg(g.NewOperator(NewDiv(sum, g.NewOperator(NewSquare(r.x2))))))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *DivScalar[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	x2 := r.x2.Value().Scalar().F64()
+	return sumTangents(
+		scaleTangent(tangents[0], 1/x2),
+		mapTangent(tangents[1], func(t mat.Matrix) mat.Matrix {
+			return r.x1.Value().ProdScalar(-t.Scalar().F64() / (x2 * x2))
+		}),
+	)
+}
@@ -59,3 +59,31 @@ func (r *Dot[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Dot[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x1.RequiresGrad() {
+		gxs[0] = g.NewOperator(NewProdScalar(r.x2, gy))
+	}
+	if r.x2.RequiresGrad() {
+		gxs[1] = g.NewOperator(NewProdScalar(r.x1, gy))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Dot[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return sumTangents(
+		mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+			prod := t.Prod(r.x2.Value())
+			defer mat.ReleaseMatrix(prod)
+			return prod.Sum()
+		}),
+		mapTangent(tangents[1], func(t mat.Matrix) mat.Matrix {
+			prod := t.Prod(r.x1.Value())
+			defer mat.ReleaseMatrix(prod)
+			return prod.Sum()
+		}),
+	)
+}
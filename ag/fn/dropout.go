@@ -59,3 +59,17 @@ func (r *Dropout[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Dropout[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	mask := g.NewConstant(r.mask.Clone())
+	return []O{g.NewOperator(NewProd(mask, gy))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Dropout[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return prodTangent(tangents[0], r.mask)
+}
@@ -0,0 +1,214 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"math"
+	"sort"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// entmaxBisectIterations is the number of iterations of the bisection,
+// enough to halve the initial interval (at most 1 wide) down to the
+// precision of float64.
+const entmaxBisectIterations = 60
+
+// Entmax is the alpha-entmax transformation, which maps the values of x to
+// a probability distribution, like Softmax, but possibly assigning exactly
+// zero probability to the smallest values (see "Sparse Sequence-to-Sequence
+// Models", Peters et al., 2019):
+//
+//	y = [(alpha - 1)x - tau]₊^(1/(alpha - 1))
+//
+// where tau is the threshold such that sum(y) = 1. With alpha = 2 it is
+// equal to SparseMax, and for alpha approaching 1 it approaches Softmax.
+// The threshold is computed exactly by sorting for alpha = 1.5, and by
+// bisection otherwise.
+type Entmax[O Operand] struct {
+	x     O
+	alpha float64
+	y     mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewEntmax returns a new Entmax Function. It panics if alpha is not
+// greater than 1.
+func NewEntmax[O Operand](x O, alpha float64) *Entmax[O] {
+	if !(alpha > 1) {
+		panic("fn: entmax alpha must be greater than 1")
+	}
+	return &Entmax[O]{
+		x:     x,
+		alpha: alpha,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *Entmax[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *Entmax[O]) Forward() mat.Matrix {
+	x := r.x.Value()
+	rows, cols := x.Dims()
+	r.y = x.NewMatrix(rows, cols, float.SliceInterface(entmax(x.Data().F64(), r.alpha)))
+	return r.y
+}
+
+// entmax returns the alpha-entmax of the values x.
+func entmax(x []float64, alpha float64) []float64 {
+	if alpha == 1.5 {
+		return entmax15(x)
+	}
+	return entmaxBisect(x, alpha)
+}
+
+// entmax15 returns the 1.5-entmax of the values x, that is
+// [x/2 - tau]₊², finding the exact threshold tau by sorting the values.
+func entmax15(x []float64) []float64 {
+	// translate the input by max for numerical stability
+	max := math.Inf(-1)
+	for _, v := range x {
+		max = math.Max(max, v)
+	}
+	z := make([]float64, len(x))
+	for i, v := range x {
+		z[i] = (v - max) / 2
+	}
+	zs := make([]float64, len(z))
+	copy(zs, z)
+	sort.Sort(sort.Reverse(sort.Float64Slice(zs)))
+
+	// Given the support of the k largest values, tau solves
+	// sum((zs[i] - tau)²) = 1 for i < k, and the support is the largest k
+	// for which tau does not exceed zs[k-1]. The values -Inf (e.g. masked
+	// scores) are never part of the support.
+	var tau, sum, sumSq float64
+	for k := 1; k <= len(zs); k++ {
+		if math.IsInf(zs[k-1], -1) {
+			break
+		}
+		sum += zs[k-1]
+		sumSq += zs[k-1] * zs[k-1]
+		n := float64(k)
+		mean := sum / n
+		ss := sumSq - n*mean*mean
+		tauK := mean - math.Sqrt(math.Max(0, (1-ss)/n))
+		if tauK > zs[k-1] {
+			break
+		}
+		tau = tauK
+	}
+
+	for i, v := range z {
+		d := math.Max(0, v-tau)
+		z[i] = d * d
+	}
+	return z
+}
+
+// entmaxBisect returns the alpha-entmax of the values x, finding the
+// threshold by bisection. The result is normalized to sum to one.
+func entmaxBisect(x []float64, alpha float64) []float64 {
+	am1 := alpha - 1
+	z := make([]float64, len(x))
+	max := math.Inf(-1)
+	for i, v := range x {
+		z[i] = am1 * v
+		max = math.Max(max, z[i])
+	}
+
+	y := make([]float64, len(x))
+	eval := func(tau float64) float64 {
+		var sum float64
+		for i, v := range z {
+			y[i] = math.Pow(math.Max(0, v-tau), 1/am1)
+			sum += y[i]
+		}
+		return sum
+	}
+
+	// the sum is at least one at lo, and at most one at hi
+	lo := max - 1
+	hi := max - math.Pow(1/float64(len(x)), am1)
+	for i := 0; i < entmaxBisectIterations; i++ {
+		mid := (lo + hi) / 2
+		if eval(mid) >= 1 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	sum := eval(lo)
+	for i := range y {
+		y[i] /= sum
+	}
+	return y
+}
+
+// Backward computes the backward pass.
+func (r *Entmax[O]) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x.Value(), gy) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		gx := r.vjp(gy)
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// vjp returns the product of gy with the Jacobian of the function, which is
+// symmetric:
+//
+//	gx = s ⊙ gy - (s·gy / sum(s)) s
+//
+// where s = y^(2 - alpha) on the support of y, and zero elsewhere.
+func (r *Entmax[O]) vjp(gy mat.Matrix) mat.Matrix {
+	s := r.weights()
+	defer mat.ReleaseMatrix(s)
+	gx := s.Prod(gy)
+	q := gx.Sum().Scalar().F64() / s.Sum().Scalar().F64()
+	s.ProdScalarInPlace(q)
+	gx.SubInPlace(s)
+	return gx
+}
+
+// weights returns a new matrix with y^(2 - alpha) on the support of y, and
+// zero elsewhere.
+func (r *Entmax[O]) weights() mat.Matrix {
+	exp := 2 - r.alpha
+	return r.y.Apply(func(_, _ int, v float64) float64 {
+		if v <= 0 {
+			return 0
+		}
+		return math.Pow(v, exp)
+	})
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Entmax[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	// The weights y^(2 - alpha) are computed on the support of y only,
+	// raising 1 instead of 0 elsewhere, and then masking the result.
+	y := g.NewOperator(NewEntmax(r.x, r.alpha))
+	support := newMaskConstant(g, y, func(v float64) bool { return v > 0 })
+	offset := newMaskConstant(g, y, func(v float64) bool { return v <= 0 })
+	pow := g.NewOperator(NewPow(g.NewOperator(NewAdd(y, offset)), 2-r.alpha))
+	weights := g.NewOperator(NewProd(support, pow))
+	sSum := g.NewOperator(NewReduceSum(weights))
+	q := g.NewOperator(NewDivScalar(g.NewOperator(NewDot(weights, gy)), sSum))
+	return []O{g.NewOperator(NewProd(weights, g.NewOperator(NewSubScalar(gy, q))))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Entmax[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], r.vjp)
+}
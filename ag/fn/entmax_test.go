@@ -0,0 +1,100 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntmax_Forward(t *testing.T) {
+	t.Run("float32", testEntmaxForward[float32])
+	t.Run("float64", testEntmaxForward[float64])
+}
+
+func testEntmaxForward[T float.DType](t *testing.T) {
+	testCases := []struct {
+		alpha float64
+		y     []T
+		gx    []T
+	}{
+		{1.5, []T{0.332614, 0.163036, 0, 0, 0.504351}, []T{0.186675, 0.009561, 0, 0, -0.196236}},
+		{1.25, []T{0.319812, 0.196193, 0.024912, 0.009744, 0.449339}, []T{0.123557, -0.00279, 0.030759, 0.018315, -0.169841}},
+		{2, []T{0.359667, 0.013767, 0, 0, 0.626567}, []T{0.3, 0, 0, 0, -0.3}},
+	}
+
+	for _, tc := range testCases {
+		x := &variable{
+			value:        mat.NewVecDense([]T{0.8053, 0.4594, -0.6136, -0.9460, 1.0722}),
+			grad:         nil,
+			requiresGrad: true,
+		}
+
+		f := NewEntmax(x, tc.alpha)
+		assert.Equal(t, []*variable{x}, f.Operands())
+
+		y := f.Forward()
+		assert.InDeltaSlice(t, tc.y, y.Data(), 1.0e-5, "alpha %g", tc.alpha)
+
+		f.Backward(mat.NewVecDense([]T{0.1, -0.2, 0.3, 0.4, -0.5}))
+		assert.InDeltaSlice(t, tc.gx, x.grad.Data(), 1.0e-5, "alpha %g", tc.alpha)
+	}
+
+	x := &variable{value: mat.NewVecDense([]T{0.1, 0.2})}
+	assert.Panics(t, func() { NewEntmax(x, 1) })
+	assert.Panics(t, func() { NewEntmax(x, 1.5).Backward(mat.NewVecDense([]T{0.1, 0.2, 0.3})) })
+}
+
+func TestEntmax_Exact(t *testing.T) {
+	t.Run("float32", testEntmaxExact[float32])
+	t.Run("float64", testEntmaxExact[float64])
+}
+
+func testEntmaxExact[T float.DType](t *testing.T) {
+	x := []float64{1000, 999.5, 0.3, -2, 999.9, 998}
+	assert.InDeltaSlice(t, entmaxBisect(x, 1.5), entmax15(x), 1.0e-9)
+
+	y := NewEntmax(&variable{value: mat.NewVecDense([]T{1000, 0, -1000})}, 1.5).Forward()
+	assert.InDeltaSlice(t, []T{1, 0, 0}, y.Data(), 1.0e-6)
+}
+
+func TestEntmax_Masked(t *testing.T) {
+	t.Run("float32", testEntmaxMasked[float32])
+	t.Run("float64", testEntmaxMasked[float64])
+}
+
+func testEntmaxMasked[T float.DType](t *testing.T) {
+	inf := T(math.Inf(-1))
+	for _, alpha := range []float64{1.5, 1.25, 2} {
+		x := &variable{
+			value:        mat.NewVecDense([]T{0.8053, inf, 0.4594, -0.6136, inf, -0.9460, 1.0722}),
+			grad:         nil,
+			requiresGrad: true,
+		}
+		unmasked := NewEntmax(&variable{value: mat.NewVecDense([]T{0.8053, 0.4594, -0.6136, -0.9460, 1.0722})}, alpha).Forward()
+		expected := mat.Data[T](unmasked)
+
+		f := NewEntmax(x, alpha)
+		y := f.Forward()
+		assert.InDeltaSlice(t, []T{expected[0], 0, expected[1], expected[2], 0, expected[3], expected[4]}, y.Data(), 1.0e-5, "alpha %g", alpha)
+
+		f.Backward(mat.NewVecDense([]T{0.1, 0.7, -0.2, 0.3, -0.8, 0.4, -0.5}))
+		for i, v := range mat.Data[T](x.grad) {
+			assert.False(t, math.IsNaN(float64(v)), "alpha %g", alpha)
+			if i == 1 || i == 4 {
+				assert.Equal(t, T(0), v, "alpha %g", alpha)
+			}
+		}
+
+		// all the values which are not masked are in the support
+		y = NewEntmax(&variable{value: mat.NewVecDense([]T{0.1, inf, 0.2, 0.15})}, alpha).Forward()
+		expected = mat.Data[T](NewEntmax(&variable{value: mat.NewVecDense([]T{0.1, 0.2, 0.15})}, alpha).Forward())
+		assert.InDeltaSlice(t, []T{expected[0], 0, expected[1], expected[2]}, y.Data(), 1.0e-5, "alpha %g", alpha)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// EntmaxLoss is the Fenchel-Young loss of the alpha-entmax transformation
+// (see Entmax), computing the loss of the raw scores of vector x with
+// respect to the gold class c:
+//
+//	y = p·x + (1 - sum(p^alpha)) / (alpha(alpha - 1)) - x[c]
+//
+// where p = entmax(x). The gradient is simply p - onehot(c). With alpha = 2
+// it is the sparsemax loss, and for alpha approaching 1 it approaches the
+// cross-entropy loss (see SoftmaxCrossEntropy).
+type EntmaxLoss[O Operand] struct {
+	x     O
+	c     int
+	alpha float64
+	p     mat.Matrix // the entmax of x, initialized during the forward pass (required by the backward pass)
+}
+
+// NewEntmaxLoss returns a new EntmaxLoss Function. It panics if alpha is
+// not greater than 1.
+func NewEntmaxLoss[O Operand](x O, c int, alpha float64) *EntmaxLoss[O] {
+	if !(alpha > 1) {
+		panic("fn: entmax alpha must be greater than 1")
+	}
+	return &EntmaxLoss[O]{
+		x:     x,
+		c:     c,
+		alpha: alpha,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *EntmaxLoss[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of this function.
+func (r *EntmaxLoss[O]) Forward() mat.Matrix {
+	xv := r.x.Value()
+	if !mat.IsVector(xv) {
+		panic("fn: the input must be a vector")
+	}
+	if r.c < 0 || r.c >= xv.Size() {
+		panic("fn: invalid class index")
+	}
+	// FIXME: avoid casting to specific type
+	xData := xv.Data().F64()
+	p := entmax(xData, r.alpha)
+
+	var dot, sumPow float64
+	for i, v := range p {
+		dot += v * xData[i]
+		sumPow += math.Pow(v, r.alpha)
+	}
+	entropy := (1 - sumPow) / (r.alpha * (r.alpha - 1))
+
+	r.p = xv.NewMatrix(xv.Rows(), xv.Columns(), float.SliceInterface(p))
+	return xv.NewScalar(dot + entropy - xData[r.c])
+}
+
+// Backward computes the backward pass.
+func (r *EntmaxLoss[O]) Backward(gy mat.Matrix) {
+	if !mat.IsScalar(gy) {
+		panic("fn: the gradient had to be a scalar")
+	}
+	if r.x.RequiresGrad() {
+		gx := r.entmaxMinusOneHot()
+		defer mat.ReleaseMatrix(gx)
+		gx.ProdScalarInPlace(gy.Scalar().F64())
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *EntmaxLoss[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	p := g.NewOperator(NewEntmax(r.x, r.alpha))
+	oneHot := r.x.Value().ZerosLike()
+	oneHot.SetVecScalar(r.c, float.Interface(1.0))
+	return []O{g.NewOperator(NewProdScalar(g.NewOperator(NewSub(p, g.NewConstant(oneHot))), gy))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *EntmaxLoss[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		d := r.entmaxMinusOneHot()
+		defer mat.ReleaseMatrix(d)
+		return d.ProdInPlace(t).Sum()
+	})
+}
+
+// entmaxMinusOneHot returns a new matrix with the entmax of x, minus one at
+// the position of the gold class.
+func (r *EntmaxLoss[O]) entmaxMinusOneHot() mat.Matrix {
+	d := r.p.Clone()
+	d.SetVecScalar(r.c, float.Interface(d.ScalarAtVec(r.c).F64()-1))
+	return d
+}
@@ -0,0 +1,72 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntmaxLoss_Forward(t *testing.T) {
+	t.Run("float32", testEntmaxLossForward[float32])
+	t.Run("float64", testEntmaxLossForward[float64])
+}
+
+func testEntmaxLossForward[T float.DType](t *testing.T) {
+	testCases := []struct {
+		alpha float64
+		y     float64
+		gx    []T
+	}{
+		{1.5, 0.987956, []T{0.01963, 0, -0.418739, 0.351239, 0.047475, 0.000396}},
+		{2, 0.874225, []T{0, 0, -0.4675, 0.4675, 0, 0}},
+	}
+
+	for _, tc := range testCases {
+		x := &variable{
+			value:        mat.NewVecDense([]T{-0.41, -1.08, 0, 0.87, -0.19, -0.75}),
+			grad:         nil,
+			requiresGrad: true,
+		}
+
+		f := NewEntmaxLoss(x, 2, tc.alpha)
+		assert.Equal(t, []*variable{x}, f.Operands())
+
+		y := f.Forward()
+		assert.InDelta(t, tc.y, y.Scalar().F64(), 1.0e-5, "alpha %g", tc.alpha)
+
+		f.Backward(mat.NewScalar[T](0.5))
+		assert.InDeltaSlice(t, tc.gx, x.grad.Data(), 1.0e-5, "alpha %g", tc.alpha)
+	}
+
+	x := &variable{value: mat.NewVecDense([]T{0.1, 0.2})}
+	assert.Panics(t, func() { NewEntmaxLoss(x, 2, 1.5).Forward() })
+	assert.Panics(t, func() { NewEntmaxLoss(x, 0, 0.5) })
+	assert.Panics(t, func() { NewEntmaxLoss(&variable{value: mat.NewEmptyDense[T](2, 2)}, 0, 1.5).Forward() })
+}
+
+func TestEntmaxLoss_Separable(t *testing.T) {
+	t.Run("float32", testEntmaxLossSeparable[float32])
+	t.Run("float64", testEntmaxLossSeparable[float64])
+}
+
+// testEntmaxLossSeparable verifies that the loss is exactly zero when the
+// gold class is separated from the others by a large enough margin.
+func testEntmaxLossSeparable[T float.DType](t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]T{0.1, 5, -0.3}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewEntmaxLoss(x, 1, 1.5)
+	assert.InDelta(t, 0, f.Forward().Scalar().F64(), 1.0e-6)
+
+	f.Backward(mat.NewScalar[T](1))
+	assert.InDeltaSlice(t, []T{0, 0, 0}, x.grad.Data(), 1.0e-6)
+}
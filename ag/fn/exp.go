@@ -40,3 +40,18 @@ func (e *Exp[O]) Backward(gy mat.Matrix) {
 		e.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (e *Exp[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !e.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	return []O{g.NewOperator(NewProd(g.NewOperator(NewExp(e.x)), gy))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (e *Exp[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return e.x.Value().Exp().ProdInPlace(t)
+	})
+}
@@ -41,3 +41,17 @@ func (r *Flatten[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Flatten[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	rows, cols := r.x.Value().Dims()
+	return []O{g.NewOperator(NewReshape(gy, rows, cols))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Flatten[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], mat.Matrix.Flatten)
+}
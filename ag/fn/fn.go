@@ -37,3 +37,46 @@ func operandIsNil[O Operand](o O) bool {
 	v := reflect.ValueOf(o)
 	return v.Kind() == reflect.Pointer && v.IsNil()
 }
+
+// Graph is implemented by any value that can create new nodes of a
+// computational graph.
+//
+// It allows a Function to express its own backward pass by means of other
+// Functions (see GraphFunction), so that the resulting gradients are nodes
+// of the graph themselves and can be differentiated again.
+type Graph[O Operand] interface {
+	// NewOperator returns a new node, as result of the given Function.
+	NewOperator(f Function[O]) O
+	// NewConstant returns a new node holding the given value.
+	// The node does not require gradients.
+	NewConstant(value mat.Matrix) O
+}
+
+// GraphFunction is a Function whose backward pass can also be expressed
+// as a composition of Functions, allowing the computation of higher-order
+// derivatives.
+type GraphFunction[O Operand] interface {
+	Function[O]
+	// BackwardGraph computes the backward pass building new nodes of the
+	// graph g, given the gradients gy of the output as a node.
+	//
+	// It returns the gradients of the operands, in the same order of
+	// Operands. An element is the zero value of O (nil) if the related
+	// operand does not require gradients.
+	BackwardGraph(g Graph[O], gy O) []O
+}
+
+// JVPFunction is a Function which also supports forward-mode automatic
+// differentiation.
+type JVPFunction[O Operand] interface {
+	Function[O]
+	// JVP computes the Jacobian-vector product of the function, that is the
+	// tangent of the output, given the tangents of the operands in the same
+	// order of Operands.
+	//
+	// A nil tangent is equivalent to a matrix of zeros. The function returns
+	// nil if the tangent of the output is zero too.
+	//
+	// It must be called after Forward.
+	JVP(tangents []mat.Matrix) mat.Matrix
+}
@@ -28,6 +28,9 @@ func (v *variable) Value() mat.Matrix {
 }
 
 func (v *variable) AccGrad(gx mat.Matrix) {
+	if gx == nil {
+		return
+	}
 	if v.grad == nil {
 		v.grad = gx.Clone()
 		return
@@ -0,0 +1,61 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Gather is a Function which selects a single element from each column
+// or from each row of the input matrix, with the indices taken along the
+// given dimension.
+//
+// With dim 1, the result is a column vector with the element indices[i] of
+// each row i (e.g. the scores of the target labels from a batch of logits);
+// with dim 0, the result is a row vector with the element indices[j] of each
+// column j.
+type Gather[O Operand] struct {
+	gather[O]
+	dim        int
+	dimIndices []int
+}
+
+// NewGather returns a new Gather Function.
+func NewGather[O Operand](x O, dim int, indices []int) *Gather[O] {
+	checkDim(dim)
+	return &Gather[O]{
+		gather:     gather[O]{x: x},
+		dim:        dim,
+		dimIndices: indices,
+	}
+}
+
+// Forward computes the output of the function.
+func (r *Gather[O]) Forward() mat.Matrix {
+	rows, cols := r.x.Value().Dims()
+	indices := r.dimIndices
+	if r.dim == 0 && len(indices) != cols || r.dim == 1 && len(indices) != rows {
+		panic(fmt.Sprintf("fn: invalid number of indices %d for a %d×%d matrix along dimension %d", len(indices), rows, cols, r.dim))
+	}
+	checkIndices(indices, rows, cols, r.dim)
+
+	flat := make([]int, len(indices))
+	for k, index := range indices {
+		if r.dim == 0 {
+			flat[k] = index*cols + k
+		} else {
+			flat[k] = k*cols + index
+		}
+	}
+	r.indices = flat
+	if r.dim == 0 {
+		r.rows, r.cols = 1, cols
+	} else {
+		r.rows, r.cols = rows, 1
+	}
+	return r.gather.Forward()
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGather_Forward(t *testing.T) {
+	t.Run("float32", testGatherForward[float32])
+	t.Run("float64", testGatherForward[float64])
+}
+
+func testGatherForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(3, 4, []T{
+			0.1, 0.2, 0.3, 0.0,
+			0.4, 0.5, -0.6, 0.7,
+			-0.5, 0.8, -0.8, -0.1,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	t.Run("rows", func(t *testing.T) {
+		x.grad = nil
+		f := NewGather(x, 1, []int{2, 0, 2})
+		assert.Equal(t, []*variable{x}, f.Operands())
+
+		y := f.Forward()
+		assert.Equal(t, 3, y.Rows())
+		assert.Equal(t, 1, y.Columns())
+		assert.InDeltaSlice(t, []T{0.3, 0.4, -0.8}, y.Data(), 1.0e-6)
+
+		f.Backward(mat.NewVecDense([]T{1, 2, 3}))
+		assert.InDeltaSlice(t, []T{
+			0, 0, 1, 0,
+			2, 0, 0, 0,
+			0, 0, 3, 0,
+		}, x.grad.Data(), 1.0e-6)
+	})
+
+	t.Run("columns", func(t *testing.T) {
+		x.grad = nil
+		f := NewGather(x, 0, []int{1, 2, 0, 1})
+
+		y := f.Forward()
+		assert.Equal(t, 1, y.Rows())
+		assert.Equal(t, 4, y.Columns())
+		assert.InDeltaSlice(t, []T{0.4, 0.8, 0.3, 0.7}, y.Data(), 1.0e-6)
+
+		f.Backward(mat.NewDense(1, 4, []T{1, 2, 3, 4}))
+		assert.InDeltaSlice(t, []T{
+			0, 0, 3, 0,
+			1, 0, 0, 4,
+			0, 2, 0, 0,
+		}, x.grad.Data(), 1.0e-6)
+	})
+
+	assert.Panics(t, func() { NewGather(x, 1, []int{0, 1}).Forward() })
+	assert.Panics(t, func() { NewGather(x, 1, []int{0, 1, 4}).Forward() })
+	assert.Panics(t, func() { NewGather(x, 0, []int{0, 1, 3, 0}).Forward() })
+}
@@ -0,0 +1,175 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// scatter is a Function which places the elements of x, taken in row-major
+// order, at the given flat positions of a new rows×cols matrix filled with
+// zeros. Elements mapped to the same position are summed, and elements
+// mapped to negative positions are discarded.
+//
+// It is primarily used to express as graph nodes the gradients of the
+// functions which extract a portion of a matrix (e.g. At, RowView, Slice).
+type scatter[O Operand] struct {
+	x       O
+	rows    int
+	cols    int
+	indices []int
+}
+
+// gather is a Function returning a new rows×cols matrix, whose elements,
+// in row-major order, are taken from the given flat positions of x, or are
+// zeros for negative positions.
+//
+// It is the counterpart of scatter.
+type gather[O Operand] struct {
+	x       O
+	rows    int
+	cols    int
+	indices []int
+}
+
+// Operands returns the list of operands.
+func (r *scatter[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *scatter[O]) Forward() mat.Matrix {
+	return scatterData(r.x.Value(), r.rows, r.cols, r.indices)
+}
+
+// Backward computes the backward pass.
+func (r *scatter[O]) Backward(gy mat.Matrix) {
+	if r.x.RequiresGrad() {
+		gx := gatherData(gy, r.x.Value().Rows(), r.x.Value().Columns(), r.indices)
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *scatter[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	rows, cols := r.x.Value().Dims()
+	return []O{g.NewOperator(&gather[O]{x: gy, rows: rows, cols: cols, indices: r.indices})}
+}
+
+// Operands returns the list of operands.
+func (r *gather[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *gather[O]) Forward() mat.Matrix {
+	return gatherData(r.x.Value(), r.rows, r.cols, r.indices)
+}
+
+// Backward computes the backward pass.
+func (r *gather[O]) Backward(gy mat.Matrix) {
+	if r.x.RequiresGrad() {
+		gx := scatterData(gy, r.x.Value().Rows(), r.x.Value().Columns(), r.indices)
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *gather[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	rows, cols := r.x.Value().Dims()
+	return []O{g.NewOperator(&scatter[O]{x: gy, rows: rows, cols: cols, indices: r.indices})}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *scatter[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return scatterData(t, r.rows, r.cols, r.indices)
+	})
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *gather[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return gatherData(t, r.rows, r.cols, r.indices)
+	})
+}
+
+func scatterData(x mat.Matrix, rows, cols int, indices []int) mat.Matrix {
+	// FIXME: avoid casting to specific type
+	xData := x.Data().F64()
+	yData := make([]float64, rows*cols)
+	for i, index := range indices {
+		if index >= 0 {
+			yData[index] += xData[i]
+		}
+	}
+	return x.NewMatrix(rows, cols, float.SliceInterface(yData))
+}
+
+func gatherData(x mat.Matrix, rows, cols int, indices []int) mat.Matrix {
+	// FIXME: avoid casting to specific type
+	xData := x.Data().F64()
+	yData := make([]float64, rows*cols)
+	for i, index := range indices {
+		if index >= 0 {
+			yData[i] = xData[index]
+		}
+	}
+	return x.NewMatrix(rows, cols, float.SliceInterface(yData))
+}
+
+// rangeIndices returns the flat positions, in row-major order, of the
+// elements of the portion [fromRow:toRow, fromCol:toCol] of a matrix
+// with the given number of columns.
+func rangeIndices(cols, fromRow, fromCol, toRow, toCol int) []int {
+	indices := make([]int, 0, (toRow-fromRow)*(toCol-fromCol))
+	for i := fromRow; i < toRow; i++ {
+		for j := fromCol; j < toCol; j++ {
+			indices = append(indices, i*cols+j)
+		}
+	}
+	return indices
+}
+
+// newScalarConstant returns a new constant node of g holding a scalar
+// value, of the same type of the value of x.
+func newScalarConstant[O Operand](g Graph[O], x O, v float64) O {
+	return g.NewConstant(x.Value().NewScalar(v))
+}
+
+// newMaskConstant returns a new constant node of g, with the same shape of
+// the value of x, holding 1 where the condition is satisfied by the element
+// of x at the same position, and 0 elsewhere.
+func newMaskConstant[O Operand](g Graph[O], x O, cond func(v float64) bool) O {
+	return g.NewConstant(x.Value().Apply(func(_, _ int, v float64) float64 {
+		if cond(v) {
+			return 1
+		}
+		return 0
+	}))
+}
+
+// piecewiseDerivGraph returns the derivative of a single-input element-wise
+// function, consisting of the node lower where x <= threshold, and of the
+// constant value c elsewhere.
+func piecewiseDerivGraph[O Operand](g Graph[O], x O, threshold, c float64, lower O) O {
+	lowerMask := newMaskConstant(g, x, func(v float64) bool { return v <= threshold })
+	upper := g.NewConstant(x.Value().Apply(func(_, _ int, v float64) float64 {
+		if v > threshold {
+			return c
+		}
+		return 0
+	}))
+	return g.NewOperator(NewAdd(upper, g.NewOperator(NewProd(lowerMask, lower))))
+}
@@ -0,0 +1,502 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/stretchr/testify/assert"
+)
+
+// testGraph is a simple implementation satisfying the Graph interface,
+// which eagerly evaluates the new operators.
+type testGraph struct{}
+
+func (testGraph) NewOperator(f Function[*variable]) *variable {
+	requiresGrad := false
+	for _, op := range f.Operands() {
+		if op.RequiresGrad() {
+			requiresGrad = true
+			break
+		}
+	}
+	return &variable{
+		value:        f.Forward(),
+		requiresGrad: requiresGrad,
+	}
+}
+
+func (testGraph) NewConstant(value mat.Matrix) *variable {
+	return &variable{value: value}
+}
+
+func TestGraphFunction_BackwardGraph(t *testing.T) {
+	t.Run("float32", testGraphFunctionBackwardGraph[float32])
+	t.Run("float64", testGraphFunctionBackwardGraph[float64])
+}
+
+// differentiableTestCase is a named constructor of a Function, used for
+// testing the consistency of the different differentiation methods.
+type differentiableTestCase struct {
+	name string
+	new  func() GraphFunction[*variable]
+}
+
+func differentiableTestCases[T float.DType]() []differentiableTestCase {
+	vec := func(vs ...T) *variable {
+		return newVarWithGrad(mat.NewVecDense(vs))
+	}
+	matrix := func(r, c int, vs ...T) *variable {
+		return newVarWithGrad(mat.NewDense(r, c, vs))
+	}
+	scalar := func(v T) *variable {
+		return newVarWithGrad(mat.NewScalar(v))
+	}
+	constant := func(v T) *variable {
+		return &variable{value: mat.NewScalar(v)}
+	}
+
+	return []differentiableTestCase{
+		{"Add", func() GraphFunction[*variable] {
+			return NewAdd(vec(0.1, 0.2, -0.3), vec(0.4, -0.5, 0.6))
+		}},
+		{"Sub", func() GraphFunction[*variable] {
+			return NewSub(vec(0.1, 0.2, -0.3), vec(0.4, -0.5, 0.6))
+		}},
+		{"Prod", func() GraphFunction[*variable] {
+			return NewProd(vec(0.1, 0.2, -0.3), vec(0.4, -0.5, 0.6))
+		}},
+		{"Square", func() GraphFunction[*variable] {
+			return NewSquare(vec(0.1, 0.2, -0.3))
+		}},
+		{"Div", func() GraphFunction[*variable] {
+			return NewDiv(vec(0.1, 0.2, -0.3), vec(0.4, -0.5, 0.6))
+		}},
+		{"Dot", func() GraphFunction[*variable] {
+			return NewDot(vec(0.1, 0.2, -0.3), vec(0.4, -0.5, 0.6))
+		}},
+		{"Mul", func() GraphFunction[*variable] {
+			return NewMul(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), vec(0.4, -0.5, 0.6))
+		}},
+		{"MulT", func() GraphFunction[*variable] {
+			return NewMulT(matrix(3, 2, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), vec(0.4, -0.5, 0.6))
+		}},
+		{"AddScalar", func() GraphFunction[*variable] {
+			return NewAddScalar(vec(0.1, 0.2, -0.3), scalar(0.7))
+		}},
+		{"SubScalar", func() GraphFunction[*variable] {
+			return NewSubScalar(vec(0.1, 0.2, -0.3), scalar(0.7))
+		}},
+		{"ReverseSubScalar", func() GraphFunction[*variable] {
+			return NewReverseSubScalar(vec(0.1, 0.2, -0.3), scalar(0.7))
+		}},
+		{"ProdScalar", func() GraphFunction[*variable] {
+			return NewProdScalar(vec(0.1, 0.2, -0.3), scalar(0.7))
+		}},
+		{"DivScalar", func() GraphFunction[*variable] {
+			return NewDivScalar(vec(0.1, 0.2, -0.3), scalar(0.7))
+		}},
+		{"Affine", func() GraphFunction[*variable] {
+			return NewAffine(
+				vec(0.1, 0.2),
+				matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), vec(0.4, -0.5, 0.6),
+				matrix(2, 2, 0.7, -0.8, 0.9, 0.1), vec(-0.2, 0.3),
+			)
+		}},
+		{"Pow", func() GraphFunction[*variable] {
+			return NewPow(vec(0.1, 0.2, 0.3), 3)
+		}},
+		{"Exp", func() GraphFunction[*variable] {
+			return NewExp(vec(0.1, 0.2, -0.3))
+		}},
+		{"Log", func() GraphFunction[*variable] {
+			return NewLog(vec(0.1, 0.2, 0.3))
+		}},
+		{"Sqrt", func() GraphFunction[*variable] {
+			return NewSqrt(vec(0.1, 0.2, 0.3))
+		}},
+		{"Identity", func() GraphFunction[*variable] {
+			return NewIdentity(vec(0.1, 0.2, -0.3))
+		}},
+		{"Tan", func() GraphFunction[*variable] {
+			return NewTan(vec(0.1, 0.2, -0.3))
+		}},
+		{"Tanh", func() GraphFunction[*variable] {
+			return NewTanh(vec(0.1, 0.2, -0.3))
+		}},
+		{"Sigmoid", func() GraphFunction[*variable] {
+			return NewSigmoid(vec(0.1, 0.2, -0.3))
+		}},
+		{"HardSigmoid", func() GraphFunction[*variable] {
+			return NewHardSigmoid(vec(0.1, 3.2, -0.3))
+		}},
+		{"HardTanh", func() GraphFunction[*variable] {
+			return NewHardTanh(vec(0.1, 1.2, -0.3))
+		}},
+		{"ReLU", func() GraphFunction[*variable] {
+			return NewReLU(vec(0.1, 0.2, -0.3))
+		}},
+		{"Softsign", func() GraphFunction[*variable] {
+			return NewSoftsign(vec(0.1, 0.2, -0.3))
+		}},
+		{"Cos", func() GraphFunction[*variable] {
+			return NewCos(vec(0.1, 0.2, -0.3))
+		}},
+		{"Sin", func() GraphFunction[*variable] {
+			return NewSin(vec(0.1, 0.2, -0.3))
+		}},
+		{"Neg", func() GraphFunction[*variable] {
+			return NewNeg(vec(0.1, 0.2, -0.3))
+		}},
+		{"Reciprocal", func() GraphFunction[*variable] {
+			return NewReciprocal(vec(0.1, 0.2, -0.3))
+		}},
+		{"Abs", func() GraphFunction[*variable] {
+			return NewAbs(vec(0.1, 0.2, -0.3))
+		}},
+		{"Mish", func() GraphFunction[*variable] {
+			return NewMish(vec(0.1, 0.2, -0.3))
+		}},
+		{"GELU", func() GraphFunction[*variable] {
+			return NewGELU(vec(0.1, 0.2, -0.3))
+		}},
+		{"Swish", func() GraphFunction[*variable] {
+			return NewSwish(vec(0.1, 0.2, -0.3))
+		}},
+		{"SwishB", func() GraphFunction[*variable] {
+			return NewSwishB(vec(0.1, 0.2, -0.3), scalar(1.5))
+		}},
+		{"CELU", func() GraphFunction[*variable] {
+			return NewCELU(vec(0.1, 0.2, -0.3), constant(2))
+		}},
+		{"ELU", func() GraphFunction[*variable] {
+			return NewELU(vec(0.1, 0.2, -0.3), constant(2))
+		}},
+		{"SELU", func() GraphFunction[*variable] {
+			return NewSELU(vec(0.1, 0.2, -0.3), constant(1.67), constant(1.05))
+		}},
+		{"SoftPlus", func() GraphFunction[*variable] {
+			return NewSoftPlus(vec(0.1, 0.2, 3.5), constant(2), constant(3))
+		}},
+		{"LeakyReLU", func() GraphFunction[*variable] {
+			return NewLeakyReLU(vec(0.1, 0.2, -0.3), constant(0.01))
+		}},
+		{"SoftShrink", func() GraphFunction[*variable] {
+			return NewSoftShrink(vec(0.1, 0.2, -0.3), constant(0.15))
+		}},
+		{"Threshold", func() GraphFunction[*variable] {
+			return NewThreshold(vec(0.1, 0.2, -0.3), constant(0.15), constant(2))
+		}},
+		{"At", func() GraphFunction[*variable] {
+			return NewAt(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 1, 2)
+		}},
+		{"AtVec", func() GraphFunction[*variable] {
+			return NewAtVec(vec(0.1, 0.2, -0.3), 1)
+		}},
+		{"RowView", func() GraphFunction[*variable] {
+			return NewRowView(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 1)
+		}},
+		{"ColView", func() GraphFunction[*variable] {
+			return NewColView(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 1)
+		}},
+		{"Slice", func() GraphFunction[*variable] {
+			return NewSlice(matrix(3, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6, 0.7, 0.8, 0.9), 1, 1, 3, 3)
+		}},
+		{"Concat", func() GraphFunction[*variable] {
+			return NewConcat([]*variable{vec(0.1, 0.2), vec(0.3, -0.4, 0.5)})
+		}},
+		{"Stack", func() GraphFunction[*variable] {
+			return NewStack([]*variable{vec(0.1, 0.2, 0.3), vec(-0.4, 0.5, -0.6)})
+		}},
+		{"AppendRows", func() GraphFunction[*variable] {
+			return NewAppendRows(matrix(2, 2, 0.1, 0.2, 0.3, -0.4), vec(0.5, -0.6), vec(0.7, 0.8))
+		}},
+		{"Reshape", func() GraphFunction[*variable] {
+			return NewReshape(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 3, 2)
+		}},
+		{"Flatten", func() GraphFunction[*variable] {
+			return NewFlatten(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6))
+		}},
+		{"Transpose", func() GraphFunction[*variable] {
+			return NewTranspose(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6))
+		}},
+		{"RotateR", func() GraphFunction[*variable] {
+			return NewRotateR(vec(0.1, 0.2, -0.3, 0.4), 1)
+		}},
+		{"ReduceSum", func() GraphFunction[*variable] {
+			return NewReduceSum(vec(0.1, 0.2, -0.3))
+		}},
+		{"ReduceMean", func() GraphFunction[*variable] {
+			return NewReduceMean(vec(0.1, 0.2, -0.3))
+		}},
+		{"ReduceMax", func() GraphFunction[*variable] {
+			return NewReduceMax(vec(0.1, 0.2, -0.3))
+		}},
+		{"ScalarMax", func() GraphFunction[*variable] {
+			return NewScalarMax([]*variable{scalar(0.1), scalar(0.3), scalar(0.2)})
+		}},
+		{"Max", func() GraphFunction[*variable] {
+			return NewMax(vec(0.1, 0.2, -0.3), vec(0.4, -0.5, 0.6))
+		}},
+		{"Min", func() GraphFunction[*variable] {
+			return NewMin(vec(0.1, 0.2, -0.3), vec(0.4, -0.5, 0.6))
+		}},
+		{"MaxPooling", func() GraphFunction[*variable] {
+			return NewMaxPooling(matrix(2, 4, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6, 0.7, 0.8), 2, 2)
+		}},
+		{"Dropout", func() GraphFunction[*variable] {
+			return NewDropout(vec(0.1, 0.2, -0.3, 0.4), 0.5, rand.NewLockedRand(1))
+		}},
+		{"Softmax", func() GraphFunction[*variable] {
+			return NewSoftmax(vec(0.1, 0.2, -0.3))
+		}},
+		{"SparseMax", func() GraphFunction[*variable] {
+			return NewSparseMax(vec(0.8, 0.6, -0.3))
+		}},
+		{"SparseMaxLoss", func() GraphFunction[*variable] {
+			return NewSparseMaxLoss(vec(0.8, 0.6, -0.3))
+		}},
+		{"Entmax15", func() GraphFunction[*variable] {
+			return NewEntmax(vec(0.8, 0.6, -0.3), 1.5)
+		}},
+		{"EntmaxBisect", func() GraphFunction[*variable] {
+			return NewEntmax(vec(0.8, 0.6, -0.3), 1.25)
+		}},
+		{"EntmaxSparse", func() GraphFunction[*variable] {
+			return NewEntmax(vec(0.8, 0.6, -0.9), 2)
+		}},
+		{"EntmaxAlpha3", func() GraphFunction[*variable] {
+			return NewEntmax(vec(0.8, 0.6, -0.9), 3)
+		}},
+		{"EntmaxLoss", func() GraphFunction[*variable] {
+			return NewEntmaxLoss(vec(0.8, 0.6, -0.3), 1, 1.5)
+		}},
+		{"BroadcastTo", func() GraphFunction[*variable] {
+			return NewBroadcastTo(matrix(1, 3, 0.1, 0.2, -0.3), 2, 3)
+		}},
+		{"SumTo", func() GraphFunction[*variable] {
+			return NewSumTo(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 2, 1)
+		}},
+		{"BatchMul", func() GraphFunction[*variable] {
+			return NewBatchMul(matrix(4, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6, 0.7, 0.8, -0.9, 0.1, 0.2, 0.3), matrix(6, 2, 0.4, -0.5, 0.6, 0.7, -0.8, 0.9, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 2, false, false)
+		}},
+		{"BatchMulTransA", func() GraphFunction[*variable] {
+			return NewBatchMul(matrix(6, 2, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6, 0.7, 0.8, -0.9, 0.1, 0.2, 0.3), matrix(6, 2, 0.4, -0.5, 0.6, 0.7, -0.8, 0.9, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 2, true, false)
+		}},
+		{"BatchMulTransB", func() GraphFunction[*variable] {
+			return NewBatchMul(matrix(4, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6, 0.7, 0.8, -0.9, 0.1, 0.2, 0.3), matrix(4, 3, 0.4, -0.5, 0.6, 0.7, -0.8, 0.9, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 2, false, true)
+		}},
+		{"BatchMulTransAB", func() GraphFunction[*variable] {
+			return NewBatchMul(matrix(6, 2, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6, 0.7, 0.8, -0.9, 0.1, 0.2, 0.3), matrix(4, 3, 0.4, -0.5, 0.6, 0.7, -0.8, 0.9, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 2, true, true)
+		}},
+		{"RowwiseSoftmax", func() GraphFunction[*variable] {
+			return NewRowwiseSoftmax(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6))
+		}},
+		{"TensorPermute", func() GraphFunction[*variable] {
+			return NewTensorPermute(vec(0.1, 0.2, 0.3, -0.4, 0.5, -0.6, 0.7, 0.8, -0.9, 0.1, 0.2, 0.3), []int{2, 3, 2}, []int{2, 0, 1})
+		}},
+		{"TensorBroadcastTo", func() GraphFunction[*variable] {
+			return NewTensorBroadcastTo(vec(0.1, 0.2, -0.3), []int{3, 1}, []int{2, 3, 2})
+		}},
+		{"TensorSumTo", func() GraphFunction[*variable] {
+			return NewTensorSumTo(vec(0.1, 0.2, 0.3, -0.4, 0.5, -0.6), []int{2, 1, 3}, []int{1, 3})
+		}},
+		{"IndexSelectRows", func() GraphFunction[*variable] {
+			return NewIndexSelect(matrix(3, 2, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 0, []int{2, 0, 2, 1})
+		}},
+		{"IndexSelectColumns", func() GraphFunction[*variable] {
+			return NewIndexSelect(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 1, []int{1, 1})
+		}},
+		{"Gather", func() GraphFunction[*variable] {
+			return NewGather(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 1, []int{2, 0})
+		}},
+		{"ScatterAdd", func() GraphFunction[*variable] {
+			return NewScatterAdd(matrix(3, 2, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 0, []int{1, 1}, matrix(2, 2, 0.4, -0.5, 0.6, 0.7))
+		}},
+		{"Where", func() GraphFunction[*variable] {
+			cond := mat.NewMaskDense[T](2, 3, []bool{true, false, true, false, false, true})
+			return NewWhere(cond, matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), matrix(2, 3, 0.4, -0.5, 0.6, 0.7, -0.8, 0.9))
+		}},
+		{"MaskedFill", func() GraphFunction[*variable] {
+			mask := mat.NewMaskDense[T](2, 3, []bool{false, true, true, false, false, true})
+			return NewMaskedFill(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), mask, 0.5)
+		}},
+		{"LogSoftmax", func() GraphFunction[*variable] {
+			return NewLogSoftmax(vec(0.1, 0.2, 0.3, -0.4))
+		}},
+		{"SoftmaxCrossEntropy", func() GraphFunction[*variable] {
+			return NewSoftmaxCrossEntropy(vec(0.1, 0.2, 0.3, -0.4), 1)
+		}},
+		{"Inverse", func() GraphFunction[*variable] {
+			return NewInverse(matrix(3, 3, 0.9, 0.2, -0.3, 0.1, 0.8, 0.4, -0.2, 0.3, 1.1))
+		}},
+		{"LogDet", func() GraphFunction[*variable] {
+			return NewLogDet(matrix(3, 3, 0.9, 0.2, -0.3, 0.1, -0.8, 0.4, -0.2, 0.3, 1.1))
+		}},
+		{"Solve", func() GraphFunction[*variable] {
+			return NewSolve(matrix(3, 3, 0.2, 0.9, -0.3, 0.8, 0.1, 0.4, -0.2, 0.3, 1.1), matrix(3, 2, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6))
+		}},
+		{"Cholesky", func() GraphFunction[*variable] {
+			return NewCholesky(matrix(3, 3, 2.0, 0.4, -0.3, 0.4, 1.5, 0.2, -0.3, 0.2, 1.2))
+		}},
+		{"SumAxis0", func() GraphFunction[*variable] {
+			return NewSumAxis(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 0)
+		}},
+		{"SumAxis1", func() GraphFunction[*variable] {
+			return NewSumAxis(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 1)
+		}},
+		{"MeanAxis0", func() GraphFunction[*variable] {
+			return NewMeanAxis(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 0)
+		}},
+		{"MeanAxis1", func() GraphFunction[*variable] {
+			return NewMeanAxis(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 1)
+		}},
+		{"MaxAxis0", func() GraphFunction[*variable] {
+			return NewMaxAxis(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 0)
+		}},
+		{"MaxAxis1", func() GraphFunction[*variable] {
+			return NewMaxAxis(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 1)
+		}},
+		{"VarAxis0", func() GraphFunction[*variable] {
+			return NewVarAxis(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 0)
+		}},
+		{"VarAxis1", func() GraphFunction[*variable] {
+			return NewVarAxis(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 1)
+		}},
+		{"StdAxis0", func() GraphFunction[*variable] {
+			return NewStdAxis(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 0)
+		}},
+		{"StdAxis1", func() GraphFunction[*variable] {
+			return NewStdAxis(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 1)
+		}},
+		{"LogSumExpAxis0", func() GraphFunction[*variable] {
+			return NewLogSumExpAxis(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 0)
+		}},
+		{"LogSumExpAxis1", func() GraphFunction[*variable] {
+			return NewLogSumExpAxis(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), 1)
+		}},
+		{"Conv2D", func() GraphFunction[*variable] {
+			return NewConv2D(matrix(4, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6, 0.7, -0.8, 0.9, 0.2, -0.3, 0.4), vec(0.5, -0.4, 0.3, 0.3, 0.2, -0.1, -0.3, 0.6),
+				[]int{2, 2, 3}, []int{2, 1, 2, 2}, ConvConfig{Padding: []int{1, 0}, Stride: []int{2, 1}, Groups: 2})
+		}},
+		{"Conv1D", func() GraphFunction[*variable] {
+			return NewConv1D(matrix(2, 4, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6, 0.7, -0.8), matrix(3, 4, 0.5, -0.4, 0.3, 0.3, 0.2, -0.1, -0.3, 0.6, 0.4, 0.1, -0.2, 0.8),
+				[]int{2, 4}, []int{3, 2, 2}, ConvConfig{Dilation: []int{2}, Padding: []int{1}, PaddingMode: ReflectPadding})
+		}},
+		{"ConvTranspose2D", func() GraphFunction[*variable] {
+			return NewConvTranspose2D(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), vec(0.5, -0.4, 0.3, 0.3, 0.2, -0.1, -0.3, 0.6),
+				[]int{2, 1, 3}, []int{2, 1, 2, 2}, ConvTransposeConfig{Padding: []int{0, 1}, Stride: []int{1, 2}, OutputPadding: []int{0, 1}, Groups: 2})
+		}},
+		{"ConvTranspose1D", func() GraphFunction[*variable] {
+			return NewConvTranspose1D(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), matrix(2, 4, 0.5, -0.4, 0.3, 0.3, 0.2, -0.1, -0.3, 0.6),
+				[]int{2, 3}, []int{2, 2, 2}, ConvTransposeConfig{Dilation: []int{2}, Padding: []int{1}})
+		}},
+		{"PoolingMax", func() GraphFunction[*variable] {
+			return NewPooling(matrix(3, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6, 0.7, -0.8, 0.9), PoolingConfig{Mode: MaxPool, Rows: 2, Columns: 2, RowStride: 1, ColumnStride: 1})
+		}},
+		{"PoolingAvg", func() GraphFunction[*variable] {
+			return NewPooling(matrix(3, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6, 0.7, -0.8, 0.9), PoolingConfig{Mode: AvgPool, Rows: 2, Columns: 1, ColumnStride: 2, Truncate: true})
+		}},
+		{"AdaptivePoolingAvg", func() GraphFunction[*variable] {
+			return NewAdaptivePooling(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), AvgPool, 3, 2)
+		}},
+		{"AddBroadcast", func() GraphFunction[*variable] {
+			return NewAdd(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), matrix(1, 3, 0.4, -0.5, 0.6))
+		}},
+		{"SubBroadcast", func() GraphFunction[*variable] {
+			return NewSub(matrix(2, 1, 0.1, 0.2), matrix(1, 3, 0.4, -0.5, 0.6))
+		}},
+		{"ProdBroadcast", func() GraphFunction[*variable] {
+			return NewProd(matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6), matrix(2, 1, 0.4, -0.5))
+		}},
+		{"DivBroadcast", func() GraphFunction[*variable] {
+			return NewDiv(scalar(0.7), matrix(2, 3, 0.1, 0.2, 0.3, -0.4, 0.5, -0.6))
+		}},
+		{"Custom", func() GraphFunction[*variable] {
+			return newTestCustom(vec(0.1, 0.2, -0.3), vec(0.4, -0.5, 0.6))
+		}},
+	}
+}
+
+// newTestCustom returns a Custom function computing y = x1 * x2^2, with
+// support for all the differentiation modes.
+func newTestCustom(x1, x2 *variable) *Custom[*variable] {
+	forward := func(xs []mat.Matrix) mat.Matrix {
+		return xs[0].Prod(xs[1]).Prod(xs[1])
+	}
+	backward := func(xs []mat.Matrix, y, gy mat.Matrix) []mat.Matrix {
+		return []mat.Matrix{
+			gy.Prod(xs[1]).Prod(xs[1]),
+			gy.Prod(xs[0]).Prod(xs[1]).ProdScalar(2),
+		}
+	}
+	backwardGraph := func(g Graph[*variable], xs []*variable, gy *variable) []*variable {
+		gx0 := g.NewOperator(NewProd(g.NewOperator(NewProd(gy, xs[1])), xs[1]))
+		gx1 := g.NewOperator(NewProd(g.NewOperator(NewProd(gy, xs[0])), xs[1]))
+		return []*variable{gx0, g.NewOperator(NewProdScalar(gx1, newScalarConstant(g, gx1, 2)))}
+	}
+	jvp := func(xs []mat.Matrix, y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+		ty := y.ZerosLike()
+		if tangents[0] != nil {
+			ty.AddInPlace(tangents[0].Prod(xs[1]).Prod(xs[1]))
+		}
+		if tangents[1] != nil {
+			ty.AddInPlace(tangents[1].Prod(xs[0]).Prod(xs[1]).ProdScalar(2))
+		}
+		return ty
+	}
+	return NewCustom("Foo", forward, backward, x1, x2).
+		WithBackwardGraph(backwardGraph).
+		WithJVP(jvp)
+}
+
+func testGraphFunctionBackwardGraph[T float.DType](t *testing.T) {
+	for _, tc := range differentiableTestCases[T]() {
+		t.Run(tc.name, func(t *testing.T) {
+			f := tc.new()
+			y := f.Forward()
+			gy := y.Apply(func(i, j int, _ float64) float64 {
+				return 0.1*float64(i*y.Columns()+j+1) - 0.25
+			})
+
+			// BackwardGraph must come first, since Backward may release
+			// resources of the function (e.g. the Dropout mask).
+			gxs := f.BackwardGraph(testGraph{}, &variable{value: gy})
+			operands := f.Operands()
+			assert.Len(t, gxs, len(operands))
+
+			graphGrads := make(map[*variable]mat.Matrix)
+			for i, operand := range operands {
+				if gxs[i] == nil {
+					continue
+				}
+				if g, ok := graphGrads[operand]; ok {
+					graphGrads[operand] = g.Add(gxs[i].Value())
+					continue
+				}
+				graphGrads[operand] = gxs[i].Value()
+			}
+
+			f.Backward(gy)
+
+			for i, operand := range operands {
+				if !operand.RequiresGrad() {
+					continue
+				}
+				if operand.grad == nil {
+					assert.Nilf(t, graphGrads[operand], "operand %d", i)
+					continue
+				}
+				if !assert.NotNilf(t, graphGrads[operand], "operand %d", i) {
+					continue
+				}
+				assert.InDeltaSlicef(t, operand.grad.Data(), graphGrads[operand].Data(), 1.0e-5, "operand %d", i)
+			}
+		})
+	}
+}
@@ -36,3 +36,16 @@ func (r *Identity[O]) Backward(gy mat.Matrix) {
 	}
 	r.x.AccGrad(gy)
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Identity[O]) BackwardGraph(_ Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	return []O{gy}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Identity[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], mat.Matrix.Clone)
+}
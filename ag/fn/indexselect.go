@@ -0,0 +1,83 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// IndexSelect is a Function which selects the rows (dim 0) or the columns
+// (dim 1) of the input matrix at the given indices, in the given order.
+// The same index can be selected multiple times.
+type IndexSelect[O Operand] struct {
+	gather[O]
+	dim        int
+	dimIndices []int
+}
+
+// NewIndexSelect returns a new IndexSelect Function.
+func NewIndexSelect[O Operand](x O, dim int, indices []int) *IndexSelect[O] {
+	checkDim(dim)
+	return &IndexSelect[O]{
+		gather:     gather[O]{x: x},
+		dim:        dim,
+		dimIndices: indices,
+	}
+}
+
+// Forward computes the output of the function.
+func (r *IndexSelect[O]) Forward() mat.Matrix {
+	rows, cols := r.x.Value().Dims()
+	checkIndices(r.dimIndices, rows, cols, r.dim)
+	r.indices = indexSelectIndices(rows, cols, r.dim, r.dimIndices)
+	if r.dim == 0 {
+		r.rows, r.cols = len(r.dimIndices), cols
+	} else {
+		r.rows, r.cols = rows, len(r.dimIndices)
+	}
+	return r.gather.Forward()
+}
+
+// indexSelectIndices returns the flat positions, in row-major order, of the
+// elements of the rows (dim 0) or the columns (dim 1) at the given indices
+// of a rows×cols matrix.
+func indexSelectIndices(rows, cols, dim int, indices []int) []int {
+	if dim == 0 {
+		flat := make([]int, 0, len(indices)*cols)
+		for _, i := range indices {
+			flat = append(flat, rangeIndices(cols, i, 0, i+1, cols)...)
+		}
+		return flat
+	}
+	flat := make([]int, 0, rows*len(indices))
+	for i := 0; i < rows; i++ {
+		for _, j := range indices {
+			flat = append(flat, i*cols+j)
+		}
+	}
+	return flat
+}
+
+func checkDim(dim int) {
+	if dim != 0 && dim != 1 {
+		panic(fmt.Sprintf("fn: invalid dimension %d", dim))
+	}
+}
+
+// checkIndices panics if any of the indices is out of the range of the rows
+// (dim 0) or the columns (dim 1) of a rows×cols matrix.
+func checkIndices(indices []int, rows, cols, dim int) {
+	size := rows
+	if dim == 1 {
+		size = cols
+	}
+	for _, i := range indices {
+		if i < 0 || i >= size {
+			panic(fmt.Sprintf("fn: index %d out of range [0, %d)", i, size))
+		}
+	}
+}
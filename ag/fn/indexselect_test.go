@@ -0,0 +1,85 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexSelect_Forward(t *testing.T) {
+	t.Run("float32", testIndexSelectForward[float32])
+	t.Run("float64", testIndexSelectForward[float64])
+}
+
+func testIndexSelectForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(3, 4, []T{
+			0.1, 0.2, 0.3, 0.0,
+			0.4, 0.5, -0.6, 0.7,
+			-0.5, 0.8, -0.8, -0.1,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	t.Run("rows", func(t *testing.T) {
+		x.grad = nil
+		f := NewIndexSelect(x, 0, []int{2, 0, 2})
+		assert.Equal(t, []*variable{x}, f.Operands())
+
+		y := f.Forward()
+		assert.Equal(t, 3, y.Rows())
+		assert.Equal(t, 4, y.Columns())
+		assert.InDeltaSlice(t, []T{
+			-0.5, 0.8, -0.8, -0.1,
+			0.1, 0.2, 0.3, 0.0,
+			-0.5, 0.8, -0.8, -0.1,
+		}, y.Data(), 1.0e-6)
+
+		f.Backward(mat.NewDense(3, 4, []T{
+			1, 2, 3, 4,
+			5, 6, 7, 8,
+			9, 10, 11, 12,
+		}))
+		assert.InDeltaSlice(t, []T{
+			5, 6, 7, 8,
+			0, 0, 0, 0,
+			10, 12, 14, 16,
+		}, x.grad.Data(), 1.0e-6)
+	})
+
+	t.Run("columns", func(t *testing.T) {
+		x.grad = nil
+		f := NewIndexSelect(x, 1, []int{3, 1})
+
+		y := f.Forward()
+		assert.Equal(t, 3, y.Rows())
+		assert.Equal(t, 2, y.Columns())
+		assert.InDeltaSlice(t, []T{
+			0.0, 0.2,
+			0.7, 0.5,
+			-0.1, 0.8,
+		}, y.Data(), 1.0e-6)
+
+		f.Backward(mat.NewDense(3, 2, []T{
+			1, 2,
+			3, 4,
+			5, 6,
+		}))
+		assert.InDeltaSlice(t, []T{
+			0, 2, 0, 1,
+			0, 4, 0, 3,
+			0, 6, 0, 5,
+		}, x.grad.Data(), 1.0e-6)
+	})
+
+	assert.Panics(t, func() { NewIndexSelect(x, 0, []int{3}).Forward() })
+	assert.Panics(t, func() { NewIndexSelect(x, 1, []int{-1}).Forward() })
+	assert.Panics(t, func() { NewIndexSelect(x, 2, []int{0}) })
+}
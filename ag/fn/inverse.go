@@ -0,0 +1,78 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// Inverse is a Function computing the inverse of a square matrix.
+//
+//	y = x⁻¹
+type Inverse[O Operand] struct {
+	x O
+	y mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewInverse returns a new Inverse Function.
+func NewInverse[O Operand](x O) *Inverse[O] {
+	return &Inverse[O]{
+		x: x,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *Inverse[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *Inverse[O]) Forward() mat.Matrix {
+	r.y = inverse(r.x.Value())
+	return r.y
+}
+
+// inverse returns the inverse of the square matrix x, computed by LU
+// decomposition with partial pivoting (see mat.Solve).
+func inverse(x mat.Matrix) mat.Matrix {
+	eye := x.NewIdentityMatrix(x.Rows())
+	defer mat.ReleaseMatrix(eye)
+	return mat.Solve(x, eye)
+}
+
+// Backward computes the backward pass.
+func (r *Inverse[O]) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x.Value(), gy) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		// gx = -yᵀ·gy·yᵀ
+		yT := r.y.T()
+		defer mat.ReleaseMatrix(yT)
+		tmp := yT.Mul(gy)
+		defer mat.ReleaseMatrix(tmp)
+		gx := tmp.Mul(yT)
+		defer mat.ReleaseMatrix(gx)
+		gx.ProdScalarInPlace(-1)
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Inverse[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	yT := g.NewOperator(NewTranspose(g.NewOperator(NewInverse(r.x))))
+	gx := g.NewOperator(NewMul(g.NewOperator(NewMul(yT, gy)), yT))
+	return []O{g.NewOperator(NewNeg(gx))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Inverse[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		tmp := r.y.Mul(t)
+		defer mat.ReleaseMatrix(tmp)
+		return tmp.Mul(r.y).ProdScalarInPlace(-1)
+	})
+}
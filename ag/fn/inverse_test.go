@@ -0,0 +1,48 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInverse_Forward(t *testing.T) {
+	t.Run("float32", testInverseForward[float32])
+	t.Run("float64", testInverseForward[float64])
+}
+
+func testInverseForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value:        mat.NewDense(2, 2, []T{4, 7, 2, 6}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewInverse(x)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.InDeltaSlice(t, []T{0.6, -0.7, -0.2, 0.4}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(2, 2, []T{1, 0, 0, 0}))
+	assert.InDeltaSlice(t, []T{-0.36, 0.12, 0.42, -0.14}, x.grad.Data(), 1.0e-6)
+
+	// zero pivot, requiring a row swap
+	x = &variable{
+		value:        mat.NewDense(2, 2, []T{0, 1, -2, 0}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f = NewInverse(x)
+	y = f.Forward()
+	assert.InDeltaSlice(t, []T{0, -0.5, 1, 0}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(2, 2, []T{1, 0, 0, 0}))
+	assert.InDeltaSlice(t, []T{0, 0, 0, 0.5}, x.grad.Data(), 1.0e-6)
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// sumTangents returns a new matrix with the sum of the non-nil tangents,
+// or nil if all of them are nil.
+func sumTangents(ts ...mat.Matrix) mat.Matrix {
+	var y mat.Matrix
+	for _, t := range ts {
+		if t == nil {
+			continue
+		}
+		if y == nil {
+			y = t.Clone()
+			continue
+		}
+		if !mat.SameDims(y, t) {
+			// the tangents of broadcast operands
+			sum := y.Add(t)
+			mat.ReleaseMatrix(y)
+			y = sum
+			continue
+		}
+		y.AddInPlace(t)
+	}
+	return y
+}
+
+// prodTangent returns the element-wise product between the tangent t and
+// the matrix m, or nil if t is nil.
+func prodTangent(t, m mat.Matrix) mat.Matrix {
+	if t == nil {
+		return nil
+	}
+	return t.Prod(m)
+}
+
+// scaleTangent returns the tangent t multiplied by the scalar c, or nil
+// if t is nil.
+func scaleTangent(t mat.Matrix, c float64) mat.Matrix {
+	if t == nil {
+		return nil
+	}
+	return t.ProdScalar(c)
+}
+
+// mapTangent returns the result of f(t), or nil if t is nil.
+//
+// It is primarily used for linear functions, whose tangent is obtained
+// applying the function itself to the tangent of the operand.
+func mapTangent(t mat.Matrix, f func(t mat.Matrix) mat.Matrix) mat.Matrix {
+	if t == nil {
+		return nil
+	}
+	return f(t)
+}
+
+// tangentsOrZeros replaces the nil tangents with matrices of zeros having
+// the same shape of the value of the corresponding operand. It returns nil
+// if all the tangents are nil.
+func tangentsOrZeros[O Operand](tangents []mat.Matrix, operands []O) []mat.Matrix {
+	if allNil(tangents) {
+		return nil
+	}
+	ts := make([]mat.Matrix, len(tangents))
+	for i, t := range tangents {
+		if t == nil {
+			t = operands[i].Value().ZerosLike()
+		}
+		ts[i] = t
+	}
+	return ts
+}
+
+// broadcastTangent returns a new matrix, with the same shape of m, whose
+// elements are all equal to the scalar tangent t, or nil if t is nil.
+func broadcastTangent(t, m mat.Matrix) mat.Matrix {
+	if t == nil {
+		return nil
+	}
+	return m.ZerosLike().AddScalarInPlace(t.Scalar().F64())
+}
+
+func allNil(ts []mat.Matrix) bool {
+	for _, t := range ts {
+		if t != nil {
+			return false
+		}
+	}
+	return true
+}
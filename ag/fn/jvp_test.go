@@ -0,0 +1,87 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJVPFunction_JVP(t *testing.T) {
+	t.Run("float32", testJVPFunctionJVP[float32])
+	t.Run("float64", testJVPFunctionJVP[float64])
+}
+
+// testJVPFunctionJVP verifies that, for each function, the Jacobian-vector
+// product is the adjoint of the vector-Jacobian product computed by
+// Backward, that is <gy, J·t> = <Jᵀ·gy, t>.
+func testJVPFunctionJVP[T float.DType](t *testing.T) {
+	for _, tc := range differentiableTestCases[T]() {
+		t.Run(tc.name, func(t *testing.T) {
+			f := tc.new()
+			jf, ok := f.(JVPFunction[*variable])
+			require.True(t, ok)
+
+			y := f.Forward()
+			gy := y.Apply(func(i, j int, _ float64) float64 {
+				return 0.1*float64(i*y.Columns()+j+1) - 0.25
+			})
+
+			// The same operand can occur more than once (e.g. Square),
+			// always with the same tangent.
+			operands := f.Operands()
+			tangents := make([]mat.Matrix, len(operands))
+			operandTangents := make(map[*variable]mat.Matrix)
+			for k, operand := range operands {
+				if !operand.RequiresGrad() {
+					continue
+				}
+				if _, ok := operandTangents[operand]; !ok {
+					v := operand.Value()
+					operandTangents[operand] = v.Apply(func(i, j int, _ float64) float64 {
+						return 0.3 - 0.15*float64((i*v.Columns()+j+k)%5)
+					})
+				}
+				tangents[k] = operandTangents[operand]
+			}
+
+			// JVP must come first, since Backward may release resources
+			// of the function (e.g. the Dropout mask).
+			ty := jf.JVP(tangents)
+
+			f.Backward(gy)
+
+			var expected float64
+			for operand, tangent := range operandTangents {
+				if operand.grad == nil {
+					continue
+				}
+				for i, g := range operand.grad.Data().F64() {
+					expected += g * tangent.Data().F64()[i]
+				}
+			}
+
+			var actual float64
+			if ty != nil {
+				require.Equal(t, y.Size(), ty.Size())
+				for i, v := range ty.Data().F64() {
+					actual += v * gy.Data().F64()[i]
+				}
+			}
+			assert.InDelta(t, expected, actual, 1.0e-5)
+		})
+	}
+
+	t.Run("nil tangents", func(t *testing.T) {
+		x := newVarWithGrad(mat.NewVecDense([]T{1, 2}))
+		f := NewProd(x, x)
+		f.Forward()
+		assert.Nil(t, f.JVP([]mat.Matrix{nil, nil}))
+	})
+}
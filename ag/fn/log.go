@@ -50,3 +50,18 @@ func safeLogDeriv(_, _ int, v float64) float64 {
 	}
 	panic("ag: invalid log for negative values")
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (l *Log[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !l.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	return []O{g.NewOperator(NewDiv(gy, l.x))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (l *Log[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return t.Div(l.x.Value())
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// LogDet is a Function computing the natural logarithm of the absolute
+// value of the determinant of a square matrix (see mat.LogAbsDet).
+//
+//	y = log|det(x)|
+type LogDet[O Operand] struct {
+	x    O
+	xInv mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewLogDet returns a new LogDet Function.
+func NewLogDet[O Operand](x O) *LogDet[O] {
+	return &LogDet[O]{
+		x: x,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *LogDet[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *LogDet[O]) Forward() mat.Matrix {
+	xv := r.x.Value()
+	logAbsDet, _ := mat.LogAbsDet(xv)
+	r.xInv = inverse(xv)
+	return xv.NewScalar(logAbsDet)
+}
+
+// Backward computes the backward pass.
+func (r *LogDet[O]) Backward(gy mat.Matrix) {
+	if !mat.IsScalar(gy) {
+		panic("fn: the gradient had to be a scalar")
+	}
+	if r.x.RequiresGrad() {
+		// gx = gy * x⁻ᵀ
+		gx := r.xInv.T()
+		defer mat.ReleaseMatrix(gx)
+		gx.ProdScalarInPlace(gy.Scalar().F64())
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *LogDet[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	xInvT := g.NewOperator(NewTranspose(g.NewOperator(NewInverse(r.x))))
+	return []O{g.NewOperator(NewProdScalar(xInvT, gy))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *LogDet[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		// tr(x⁻¹·t)
+		xInvT := r.xInv.T()
+		defer mat.ReleaseMatrix(xInvT)
+		prod := xInvT.Prod(t)
+		defer mat.ReleaseMatrix(prod)
+		return prod.Sum()
+	})
+}
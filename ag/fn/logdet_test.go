@@ -0,0 +1,52 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogDet_Forward(t *testing.T) {
+	t.Run("float32", testLogDetForward[float32])
+	t.Run("float64", testLogDetForward[float64])
+}
+
+func testLogDetForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value:        mat.NewDense(2, 2, []T{4, 7, 2, 6}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewLogDet(x)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.InDelta(t, 2.302585, y.Scalar().F64(), 1.0e-6)
+
+	f.Backward(mat.NewScalar[T](2))
+	assert.InDeltaSlice(t, []T{1.2, -0.4, -1.4, 0.8}, x.grad.Data(), 1.0e-6)
+
+	// negative determinant
+	y = NewLogDet(&variable{value: mat.NewDense(2, 2, []T{1, 2, 3, 4})}).Forward()
+	assert.InDelta(t, 0.693147, y.Scalar().F64(), 1.0e-6)
+
+	// zero pivot, requiring a row swap
+	x = &variable{
+		value:        mat.NewDense(2, 2, []T{0, 1, -2, 0}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f = NewLogDet(x)
+	y = f.Forward()
+	assert.InDelta(t, 0.693147, y.Scalar().F64(), 1.0e-6)
+
+	f.Backward(mat.NewScalar[T](1))
+	assert.InDeltaSlice(t, []T{0, 1, -0.5, 0}, x.grad.Data(), 1.0e-6)
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// LogSoftmax is a single-input log-softmax function, computed with the
+// log-sum-exp trick, which avoids the underflow of Log(Softmax(x)) for
+// large logits.
+//
+//	y = x - log(sum(exp(x)))
+type LogSoftmax[O Operand] struct {
+	x O
+	y mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewLogSoftmax returns a new LogSoftmax Function.
+func NewLogSoftmax[O Operand](x O) *LogSoftmax[O] {
+	return &LogSoftmax[O]{
+		x: x,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *LogSoftmax[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of this function.
+func (r *LogSoftmax[O]) Forward() mat.Matrix {
+	xv := r.x.Value()
+	r.y = xv.SubScalar(logSumExp(xv))
+	return r.y
+}
+
+// Backward computes the backward pass.
+func (r *LogSoftmax[O]) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x.Value(), gy) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		// gx = gy - softmax(x) * sum(gy)
+		gx := r.y.Exp()
+		defer mat.ReleaseMatrix(gx)
+		gx.ProdScalarInPlace(-gy.Sum().Scalar().F64())
+		gx.AddInPlace(gy)
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *LogSoftmax[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	softmax := g.NewOperator(NewExp(g.NewOperator(NewLogSoftmax(r.x))))
+	sum := g.NewOperator(NewReduceSum(gy))
+	return []O{g.NewOperator(NewSub(gy, g.NewOperator(NewProdScalar(softmax, sum))))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *LogSoftmax[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		softmax := r.y.Exp()
+		defer mat.ReleaseMatrix(softmax)
+		softmax.ProdInPlace(t)
+		return t.SubScalar(softmax.Sum().Scalar().F64())
+	})
+}
+
+// logSumExp returns the log of the sum of the exponentials of the elements
+// of x, subtracting their maximum value for numerical stability.
+func logSumExp(x mat.Matrix) float64 {
+	// FIXME: avoid casting to specific type
+	data := x.Data().F64()
+	max := math.Inf(-1)
+	for _, v := range data {
+		if v > max {
+			max = v
+		}
+	}
+	if math.IsInf(max, 0) {
+		return max
+	}
+	var sum float64
+	for _, v := range data {
+		sum += math.Exp(v - max)
+	}
+	return max + math.Log(sum)
+}
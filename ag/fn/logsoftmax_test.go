@@ -0,0 +1,52 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogSoftmax_Forward(t *testing.T) {
+	t.Run("float32", testLogSoftmaxForward[float32])
+	t.Run("float64", testLogSoftmaxForward[float64])
+}
+
+func testLogSoftmaxForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]T{-0.41, -1.08, 0, 0.87, -0.19, -0.75}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewLogSoftmax(x)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.InDeltaSlice(t, []T{-2.148619, -2.818619, -1.738619, -0.868619, -1.928619, -2.488619}, y.Data(), 1.0e-5)
+
+	f.Backward(mat.NewVecDense([]T{0, 0, -1, 0, 0, 0}))
+	assert.InDeltaSlice(t, []T{0.116645, 0.059688, -0.824237, 0.41953, 0.145349, 0.083025}, x.grad.Data(), 1.0e-5)
+}
+
+func TestLogSoftmax_LargeLogits(t *testing.T) {
+	t.Run("float32", testLogSoftmaxLargeLogits[float32])
+	t.Run("float64", testLogSoftmaxLargeLogits[float64])
+}
+
+func testLogSoftmaxLargeLogits[T float.DType](t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]T{1000, 0, -1000}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	// Log(Softmax(x)) underflows to -inf for the smallest logits
+	y := NewLogSoftmax(x).Forward()
+	assert.InDeltaSlice(t, []T{0, -1000, -2000}, y.Data(), 1.0e-3)
+}
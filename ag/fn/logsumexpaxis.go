@@ -0,0 +1,78 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// LogSumExpAxis is a Function which computes the logarithms of the sums of
+// the exponentials of the values of a matrix along the given axis (see
+// SumAxis), subtracting the maximum values for numerical stability.
+//
+//	y = log(sum(exp(x)))
+type LogSumExpAxis[O Operand] struct {
+	x    O
+	axis int
+	y    mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewLogSumExpAxis returns a new LogSumExpAxis Function.
+func NewLogSumExpAxis[O Operand](x O, axis int) *LogSumExpAxis[O] {
+	checkDim(axis)
+	return &LogSumExpAxis[O]{
+		x:    x,
+		axis: axis,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *LogSumExpAxis[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *LogSumExpAxis[O]) Forward() mat.Matrix {
+	r.y = r.x.Value().LogSumExpAxis(r.axis)
+	return r.y
+}
+
+// Backward computes the backward pass.
+func (r *LogSumExpAxis[O]) Backward(gy mat.Matrix) {
+	checkAxisGrad(r.x.Value(), gy, r.axis)
+	if r.x.RequiresGrad() {
+		// gx = gy * softmax(x)
+		softmax := r.softmax()
+		defer mat.ReleaseMatrix(softmax)
+		gx := softmax.Prod(gy)
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *LogSumExpAxis[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	y := g.NewOperator(NewLogSumExpAxis(r.x, r.axis))
+	softmax := g.NewOperator(NewExp(g.NewOperator(NewSub(r.x, y))))
+	return []O{g.NewOperator(NewProd(softmax, gy))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *LogSumExpAxis[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		softmax := r.softmax()
+		defer mat.ReleaseMatrix(softmax)
+		return softmax.ProdInPlace(t).SumAxis(r.axis)
+	})
+}
+
+// softmax returns a new matrix with the softmax of the values of x along
+// the axis.
+func (r *LogSumExpAxis[O]) softmax() mat.Matrix {
+	d := r.x.Value().Sub(r.y)
+	defer mat.ReleaseMatrix(d)
+	return d.Exp()
+}
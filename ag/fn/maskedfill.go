@@ -0,0 +1,80 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// MaskedFill is a Function which replaces the elements of x with a constant
+// value where the mask is true (e.g. -inf for the padding and the attention
+// masks, before a softmax).
+//
+// The mask is a matrix, with the same dimensions of x, whose non-zero
+// elements are true (see mat.NewMaskDense). The gradients only flow to the
+// elements of x which are not replaced.
+type MaskedFill[O Operand] struct {
+	x     O
+	mask  []bool
+	rows  int // the rows of the mask
+	cols  int // the columns of the mask
+	value float64
+}
+
+// NewMaskedFill returns a new MaskedFill Function.
+func NewMaskedFill[O Operand](x O, mask mat.Matrix, value float64) *MaskedFill[O] {
+	return &MaskedFill[O]{
+		x:     x,
+		mask:  maskValues(mask),
+		rows:  mask.Rows(),
+		cols:  mask.Columns(),
+		value: value,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *MaskedFill[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *MaskedFill[O]) Forward() mat.Matrix {
+	xv := r.x.Value()
+	if xv.Rows() != r.rows || xv.Columns() != r.cols {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	cols := xv.Columns()
+	return xv.Apply(func(i, j int, v float64) float64 {
+		if r.mask[i*cols+j] {
+			return r.value
+		}
+		return v
+	})
+}
+
+// Backward computes the backward pass.
+func (r *MaskedFill[O]) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x.Value(), gy) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		gx := maskData(gy, r.mask, false)
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *MaskedFill[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	return []O{g.NewOperator(NewProd(gy, newBoolMaskConstant(g, gy, r.mask, false)))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *MaskedFill[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return maskData(t, r.mask, false)
+	})
+}
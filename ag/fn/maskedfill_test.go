@@ -0,0 +1,40 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskedFill_Forward(t *testing.T) {
+	t.Run("float32", testMaskedFillForward[float32])
+	t.Run("float64", testMaskedFillForward[float64])
+}
+
+func testMaskedFillForward[T float.DType](t *testing.T) {
+	mask := mat.NewMaskDense[T](2, 3, []bool{false, true, true, false, false, true})
+	x := &variable{
+		value:        mat.NewDense(2, 3, []T{0.1, 0.2, 0.3, 0.4, 0.5, 0.6}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewMaskedFill(x, mask, math.Inf(-1))
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	negInf := T(math.Inf(-1))
+	assert.Equal(t, []T{0.1, negInf, negInf, 0.4, 0.5, negInf}, mat.Data[T](y))
+
+	f.Backward(mat.NewDense(2, 3, []T{1, 2, 3, 4, 5, 6}))
+	assert.InDeltaSlice(t, []T{1, 0, 0, 4, 5, 0}, x.grad.Data(), 1.0e-6)
+
+	assert.Panics(t, func() { NewMaskedFill(x, mat.NewEmptyVecDense[T](6), 0).Forward() })
+}
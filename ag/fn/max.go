@@ -71,3 +71,53 @@ func (r *Max[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Max[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	x1v := r.x1.Value()
+	x2v := r.x2.Value()
+	if r.x1.RequiresGrad() {
+		mask := g.NewConstant(x1v.Apply(func(i, j int, v float64) float64 {
+			if v > x2v.ScalarAt(i, j).F64() {
+				return 1
+			}
+			return 0
+		}))
+		gxs[0] = g.NewOperator(NewProd(mask, gy))
+	}
+	if r.x2.RequiresGrad() {
+		mask := g.NewConstant(x2v.Apply(func(i, j int, v float64) float64 {
+			if v > x1v.ScalarAt(i, j).F64() {
+				return 1
+			}
+			return 0
+		}))
+		gxs[1] = g.NewOperator(NewProd(mask, gy))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Max[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	x1v := r.x1.Value()
+	x2v := r.x2.Value()
+	return sumTangents(
+		mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+			return t.Apply(func(i, j int, v float64) float64 {
+				if x1v.ScalarAt(i, j).F64() > x2v.ScalarAt(i, j).F64() {
+					return v
+				}
+				return 0
+			})
+		}),
+		mapTangent(tangents[1], func(t mat.Matrix) mat.Matrix {
+			return t.Apply(func(i, j int, v float64) float64 {
+				if x2v.ScalarAt(i, j).F64() > x1v.ScalarAt(i, j).F64() {
+					return v
+				}
+				return 0
+			})
+		}),
+	)
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// MaxAxis is a Function which computes the maximum values of a matrix along
+// the given axis (see SumAxis). The gradients flow only to the first
+// maximum value of each column (axis 0) or row (axis 1).
+type MaxAxis[O Operand] struct {
+	x       O
+	axis    int
+	indices []int // initialized during the forward pass (required by the backward pass)
+}
+
+// NewMaxAxis returns a new MaxAxis Function.
+func NewMaxAxis[O Operand](x O, axis int) *MaxAxis[O] {
+	checkDim(axis)
+	return &MaxAxis[O]{
+		x:    x,
+		axis: axis,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *MaxAxis[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *MaxAxis[O]) Forward() mat.Matrix {
+	x := r.x.Value()
+	cols := x.Columns()
+	r.indices = x.ArgMaxAxis(r.axis)
+	// flat positions of the maximum values
+	for i, j := range r.indices {
+		if r.axis == 0 {
+			r.indices[i] = j*cols + i
+		} else {
+			r.indices[i] = i*cols + j
+		}
+	}
+	return x.MaxAxis(r.axis)
+}
+
+// Backward computes the backward pass.
+func (r *MaxAxis[O]) Backward(gy mat.Matrix) {
+	x := r.x.Value()
+	checkAxisGrad(x, gy, r.axis)
+	if r.x.RequiresGrad() {
+		gx := scatterData(gy, x.Rows(), x.Columns(), r.indices)
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *MaxAxis[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	rows, cols := r.x.Value().Dims()
+	return []O{g.NewOperator(&scatter[O]{x: gy, rows: rows, cols: cols, indices: r.indices})}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *MaxAxis[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		if r.axis == 0 {
+			return gatherData(t, 1, len(r.indices), r.indices)
+		}
+		return gatherData(t, len(r.indices), 1, r.indices)
+	})
+}
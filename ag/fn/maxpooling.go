@@ -52,11 +52,11 @@ func (r *MaxPooling[O]) Forward() mat.Matrix {
 
 	for row := 0; row < r.y.Rows(); row++ {
 		for col := 0; col < r.y.Columns(); col++ {
-			maximum := math.SmallestNonzeroFloat64
+			maximum := math.Inf(-1)
 
 			maxRows := (row * r.rows) + r.rows
 			for i := row * r.rows; i < maxRows; i++ {
-				maxCols := (col * r.cols) + r.rows
+				maxCols := (col * r.cols) + r.cols
 				for j := col * r.cols; j < maxCols; j++ {
 					// FIXME: avoid casting to specific type
 					val := xv.ScalarAt(i, j).F64()
@@ -98,3 +98,31 @@ func (r *MaxPooling[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *MaxPooling[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	rows, cols := r.x.Value().Dims()
+	indices := make([]int, 0, r.y.Size())
+	for row := 0; row < r.y.Rows(); row++ {
+		for col := 0; col < r.y.Columns(); col++ {
+			indices = append(indices, r.argmaxI[row][col]*cols+r.argmaxJ[row][col])
+		}
+	}
+	return []O{g.NewOperator(&scatter[O]{x: gy, rows: rows, cols: cols, indices: indices})}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *MaxPooling[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		ty := r.y.ZerosLike()
+		for row := 0; row < r.y.Rows(); row++ {
+			for col := 0; col < r.y.Columns(); col++ {
+				ty.SetScalar(row, col, t.ScalarAt(r.argmaxI[row][col], r.argmaxJ[row][col]))
+			}
+		}
+		return ty
+	})
+}
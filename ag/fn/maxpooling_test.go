@@ -58,3 +58,21 @@ func testMaxPoolForward[T float.DType](t *testing.T) {
 		t.Error("The rows and columns of the resulting x-gradients matrix are not correct")
 	}
 }
+
+func TestMaxPool_ForwardNegativeRectangular(t *testing.T) {
+	t.Run("float32", testMaxPoolForwardNegativeRectangular[float32])
+	t.Run("float64", testMaxPoolForwardNegativeRectangular[float64])
+}
+
+func testMaxPoolForwardNegativeRectangular[T float.DType](t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(2, 6, []T{
+			-0.4, -0.1, -0.9, -0.5, 0.2, -0.3,
+			-0.4, -0.3, -0.7, -0.3, 0.1, 0.4,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	y := NewMaxPooling(x, 2, 3).Forward()
+	assert.InDeltaSlice(t, []T{-0.1, 0.4}, y.Data(), 1.0e-6)
+}
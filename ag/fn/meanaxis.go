@@ -0,0 +1,63 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// MeanAxis is a Function which computes the means of the values of a matrix
+// along the given axis (see SumAxis).
+type MeanAxis[O Operand] struct {
+	x    O
+	axis int
+}
+
+// NewMeanAxis returns a new MeanAxis Function.
+func NewMeanAxis[O Operand](x O, axis int) *MeanAxis[O] {
+	checkDim(axis)
+	return &MeanAxis[O]{
+		x:    x,
+		axis: axis,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *MeanAxis[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *MeanAxis[O]) Forward() mat.Matrix {
+	return r.x.Value().MeanAxis(r.axis)
+}
+
+// Backward computes the backward pass.
+func (r *MeanAxis[O]) Backward(gy mat.Matrix) {
+	x := r.x.Value()
+	checkAxisGrad(x, gy, r.axis)
+	if r.x.RequiresGrad() {
+		gx := mat.BroadcastTo(gy, x.Rows(), x.Columns())
+		defer mat.ReleaseMatrix(gx)
+		gx.ProdScalarInPlace(1 / float64(axisSize(x, r.axis)))
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *MeanAxis[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	x := r.x.Value()
+	gx := g.NewOperator(NewBroadcastTo(gy, x.Rows(), x.Columns()))
+	n := newScalarConstant(g, r.x, 1/float64(axisSize(x, r.axis)))
+	return []O{g.NewOperator(NewProdScalar(gx, n))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *MeanAxis[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return t.MeanAxis(r.axis)
+	})
+}
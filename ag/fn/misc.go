@@ -17,9 +17,10 @@ type Tan[O Operand] struct {
 func NewTan[O Operand](x O) *Tan[O] {
 	return &Tan[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  tan,
-			df: tanDeriv,
+			x:       x,
+			f:       tan,
+			df:      tanDeriv,
+			dfGraph: tanDerivGraph[O],
 		},
 	}
 }
@@ -33,9 +34,10 @@ type Tanh[O Operand] struct {
 func NewTanh[O Operand](x O) *Tanh[O] {
 	return &Tanh[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  tanh,
-			df: tanhDeriv,
+			x:       x,
+			f:       tanh,
+			df:      tanhDeriv,
+			dfGraph: tanhDerivGraph[O],
 		},
 	}
 }
@@ -49,9 +51,10 @@ type Sigmoid[O Operand] struct {
 func NewSigmoid[O Operand](x O) *Sigmoid[O] {
 	return &Sigmoid[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  sigmoid,
-			df: sigmoidDeriv,
+			x:       x,
+			f:       sigmoid,
+			df:      sigmoidDeriv,
+			dfGraph: sigmoidDerivGraph[O],
 		},
 	}
 }
@@ -113,9 +116,10 @@ type Softsign[O Operand] struct {
 func NewSoftsign[O Operand](x O) *Softsign[O] {
 	return &Softsign[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  softsign,
-			df: softsignDeriv,
+			x:       x,
+			f:       softsign,
+			df:      softsignDeriv,
+			dfGraph: softsignDerivGraph[O],
 		},
 	}
 }
@@ -129,9 +133,10 @@ type Cos[O Operand] struct {
 func NewCos[O Operand](x O) *Cos[O] {
 	return &Cos[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  func(_, _ int, v float64) float64 { return math.Cos(v) },
-			df: func(_, _ int, v float64) float64 { return -math.Sin(v) },
+			x:       x,
+			f:       func(_, _ int, v float64) float64 { return math.Cos(v) },
+			df:      func(_, _ int, v float64) float64 { return -math.Sin(v) },
+			dfGraph: cosDerivGraph[O],
 		},
 	}
 }
@@ -145,9 +150,10 @@ type Sin[O Operand] struct {
 func NewSin[O Operand](x O) *Sin[O] {
 	return &Sin[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  func(i, j int, v float64) float64 { return math.Sin(v) },
-			df: func(i, j int, v float64) float64 { return math.Cos(v) },
+			x:       x,
+			f:       func(i, j int, v float64) float64 { return math.Sin(v) },
+			df:      func(i, j int, v float64) float64 { return math.Cos(v) },
+			dfGraph: sinDerivGraph[O],
 		},
 	}
 }
@@ -177,9 +183,10 @@ type Reciprocal[O Operand] struct {
 func NewReciprocal[O Operand](x O) *Reciprocal[O] {
 	return &Reciprocal[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  func(i, j int, v float64) float64 { return 1.0 / v },
-			df: func(i, j int, v float64) float64 { return -1.0 / (v * v) },
+			x:       x,
+			f:       func(i, j int, v float64) float64 { return 1.0 / v },
+			df:      func(i, j int, v float64) float64 { return -1.0 / (v * v) },
+			dfGraph: reciprocalDerivGraph[O],
 		},
 	}
 }
@@ -215,9 +222,10 @@ type Mish[O Operand] struct {
 func NewMish[O Operand](x O) *Mish[O] {
 	return &Mish[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  mish,
-			df: mishDeriv,
+			x:       x,
+			f:       mish,
+			df:      mishDeriv,
+			dfGraph: mishDerivGraph[O],
 		},
 	}
 }
@@ -231,9 +239,10 @@ type GELU[O Operand] struct {
 func NewGELU[O Operand](x O) *GELU[O] {
 	return &GELU[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  gelu,
-			df: geluDeriv,
+			x:       x,
+			f:       gelu,
+			df:      geluDeriv,
+			dfGraph: geluDerivGraph[O],
 		},
 	}
 }
@@ -244,6 +253,70 @@ func NewSiLU[O Operand](x O) *Swish[O] {
 	return NewSwish[O](x)
 }
 
+func tanDerivGraph[O Operand](g Graph[O], x O) O {
+	cos := g.NewOperator(NewCos(x))
+	return g.NewOperator(NewReciprocal(g.NewOperator(NewSquare(cos))))
+}
+
+func tanhDerivGraph[O Operand](g Graph[O], x O) O {
+	y := g.NewOperator(NewTanh(x))
+	return g.NewOperator(NewReverseSubScalar(g.NewOperator(NewSquare(y)), newScalarConstant(g, x, 1)))
+}
+
+func sigmoidDerivGraph[O Operand](g Graph[O], x O) O {
+	y := g.NewOperator(NewSigmoid(x))
+	return g.NewOperator(NewProd(y, g.NewOperator(NewReverseSubScalar(y, newScalarConstant(g, x, 1)))))
+}
+
+func softsignDerivGraph[O Operand](g Graph[O], x O) O {
+	den := g.NewOperator(NewAddScalar(g.NewOperator(NewAbs(x)), newScalarConstant(g, x, 1)))
+	return g.NewOperator(NewReciprocal(g.NewOperator(NewSquare(den))))
+}
+
+func cosDerivGraph[O Operand](g Graph[O], x O) O {
+	return g.NewOperator(NewNeg(g.NewOperator(NewSin(x))))
+}
+
+func sinDerivGraph[O Operand](g Graph[O], x O) O {
+	return g.NewOperator(NewCos(x))
+}
+
+func reciprocalDerivGraph[O Operand](g Graph[O], x O) O {
+	return g.NewOperator(NewNeg(g.NewOperator(NewReciprocal(g.NewOperator(NewSquare(x))))))
+}
+
+// mishDerivGraph expresses the derivative of mish as
+// tanh(softplus(x)) + x * (1 - tanh²(softplus(x))) * sigmoid(x).
+func mishDerivGraph[O Operand](g Graph[O], x O) O {
+	sp := g.NewOperator(NewSoftPlus(x, newScalarConstant(g, x, 1), newScalarConstant(g, x, 20)))
+	t := g.NewOperator(NewTanh(sp))
+	dt := g.NewOperator(NewReverseSubScalar(g.NewOperator(NewSquare(t)), newScalarConstant(g, x, 1)))
+	b := g.NewOperator(NewProd(x, g.NewOperator(NewProd(dt, g.NewOperator(NewSigmoid(x))))))
+	return g.NewOperator(NewAdd(t, b))
+}
+
+// geluDerivGraph expresses the derivative of gelu as
+// 0.5 * (1 + tanh(u)) + 0.5 * x * (1 - tanh²(u)) * du/dx,
+// where u = sqrt(2/π) * (x + 0.044715 * x³).
+func geluDerivGraph[O Operand](g Graph[O], x O) O {
+	c := math.Sqrt(2 / math.Pi)
+	x3 := g.NewOperator(NewPow(x, 3))
+	u := g.NewOperator(NewProdScalar(
+		g.NewOperator(NewAdd(x, g.NewOperator(NewProdScalar(x3, newScalarConstant(g, x, 0.044715))))),
+		newScalarConstant(g, x, c),
+	))
+	t := g.NewOperator(NewTanh(u))
+	half := newScalarConstant(g, x, 0.5)
+	a := g.NewOperator(NewProdScalar(g.NewOperator(NewAddScalar(t, newScalarConstant(g, x, 1))), half))
+	dt := g.NewOperator(NewReverseSubScalar(g.NewOperator(NewSquare(t)), newScalarConstant(g, x, 1)))
+	du := g.NewOperator(NewAddScalar(
+		g.NewOperator(NewProdScalar(g.NewOperator(NewSquare(x)), newScalarConstant(g, x, 3*0.044715*c))),
+		newScalarConstant(g, x, c),
+	))
+	b := g.NewOperator(NewProdScalar(g.NewOperator(NewProd(x, g.NewOperator(NewProd(dt, du)))), half))
+	return g.NewOperator(NewAdd(a, b))
+}
+
 func absDeriv(_, _ int, v float64) float64 {
 	if v < 0 {
 		return -1
@@ -71,3 +71,27 @@ func (r *Mul[O]) Backward(gy mat.Matrix) {
 	}
 	wg.Wait()
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Mul[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x1.RequiresGrad() {
+		gxs[0] = g.NewOperator(NewMul(gy, g.NewOperator(NewTranspose(r.x2))))
+	}
+	if r.x2.RequiresGrad() {
+		gxs[1] = g.NewOperator(NewMulT(r.x1, gy))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Mul[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return sumTangents(
+		mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+			return t.Mul(r.x2.Value())
+		}),
+		mapTangent(tangents[1], func(t mat.Matrix) mat.Matrix {
+			return r.x1.Value().Mul(t)
+		}),
+	)
+}
@@ -0,0 +1,256 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// PoolingMode specifies how the values of each window of a pooling are
+// combined.
+type PoolingMode int
+
+const (
+	// MaxPool takes the maximum value of each window.
+	MaxPool PoolingMode = iota
+	// AvgPool takes the average value of each window.
+	AvgPool
+)
+
+// PoolingConfig provides the hyper-parameters of a pooling.
+type PoolingConfig struct {
+	Mode PoolingMode
+	// Rows and Columns are the size of the window.
+	Rows    int
+	Columns int
+	// RowStride and ColumnStride are the steps between the positions of the
+	// window (default to the size of the window). Strides smaller than the
+	// window result in overlapping windows.
+	RowStride    int
+	ColumnStride int
+	// Truncate allows the last rows and columns of the input which do not
+	// fill a window to be discarded. Otherwise, the windows must cover the
+	// whole input.
+	Truncate bool
+}
+
+// Pooling is a Function combining the values of a matrix within rectangular
+// windows, one for each element of the output, according to a PoolingMode.
+//
+// The windows are separable: all the windows of the same output row span
+// the same rows of the input, and all the windows of the same output column
+// span the same columns of the input.
+type Pooling[O Operand] struct {
+	x    O
+	mode PoolingMode
+	// windows returns the windows of the rows and of the columns of an input
+	// with the given dimensions
+	windows func(rows, cols int) (rowWindows, colWindows [][2]int)
+	// initialized during the forward pass (required by the backward pass)
+	// rowWindows and colWindows are the ranges [start, end) of the rows and
+	// of the columns of the input spanned by each row and column of the output
+	rowWindows [][2]int
+	colWindows [][2]int
+	argmax     []int // the flat positions of the maximum values (MaxPool)
+}
+
+// NewPooling returns a new Pooling Function with windows of the same size,
+// at regular steps along the rows and the columns of x, so that the output
+// has (rows - config.Rows) / config.RowStride + 1 rows, and the same applies
+// to the columns.
+//
+// The windows must cover the whole input, unless config.Truncate is true:
+// in that case, the last rows and columns which do not fill a window are
+// discarded.
+func NewPooling[O Operand](x O, config PoolingConfig) *Pooling[O] {
+	rowStride, colStride := config.RowStride, config.ColumnStride
+	if rowStride == 0 {
+		rowStride = config.Rows
+	}
+	if colStride == 0 {
+		colStride = config.Columns
+	}
+	checkWindow(config.Rows, rowStride)
+	checkWindow(config.Columns, colStride)
+	return &Pooling[O]{
+		x:    x,
+		mode: config.Mode,
+		windows: func(rows, cols int) ([][2]int, [][2]int) {
+			return regularWindows(rows, config.Rows, rowStride, config.Truncate),
+				regularWindows(cols, config.Columns, colStride, config.Truncate)
+		},
+	}
+}
+
+// NewAdaptivePooling returns a new Pooling Function whose output is a
+// rows×cols matrix, regardless of the size of x. The windows have about the
+// same size and cover the whole input: the window of the output row i spans
+// the rows of x from floor(i·xRows/rows) to ceil((i+1)·xRows/rows), and the
+// same applies to the columns.
+func NewAdaptivePooling[O Operand](x O, mode PoolingMode, rows, cols int) *Pooling[O] {
+	if rows <= 0 || cols <= 0 {
+		panic(fmt.Sprintf("fn: invalid adaptive pooling size %d×%d", rows, cols))
+	}
+	return &Pooling[O]{
+		x:    x,
+		mode: mode,
+		windows: func(xRows, xCols int) ([][2]int, [][2]int) {
+			return adaptiveWindows(xRows, rows), adaptiveWindows(xCols, cols)
+		},
+	}
+}
+
+func checkWindow(size, stride int) {
+	if size <= 0 || stride <= 0 {
+		panic(fmt.Sprintf("fn: invalid pooling window %d with stride %d", size, stride))
+	}
+}
+
+// regularWindows returns the windows of the given size and stride which fit
+// in a dimension of the given length. Unless truncate is true, the windows
+// must cover the whole dimension.
+func regularWindows(length, size, stride int, truncate bool) [][2]int {
+	if size > length || !truncate && (length-size)%stride != 0 {
+		panic(fmt.Sprintf("fn: invalid pooling window %d with stride %d for length %d", size, stride, length))
+	}
+	windows := make([][2]int, (length-size)/stride+1)
+	for i := range windows {
+		windows[i] = [2]int{i * stride, i*stride + size}
+	}
+	return windows
+}
+
+// adaptiveWindows returns the given number of windows covering a dimension
+// of the given length.
+func adaptiveWindows(length, n int) [][2]int {
+	if n <= 0 || length <= 0 {
+		panic(fmt.Sprintf("fn: invalid adaptive pooling size %d for length %d", n, length))
+	}
+	windows := make([][2]int, n)
+	for i := range windows {
+		windows[i] = [2]int{i * length / n, ((i+1)*length + n - 1) / n}
+	}
+	return windows
+}
+
+// Operands returns the list of operands.
+func (r *Pooling[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *Pooling[O]) Forward() mat.Matrix {
+	x := r.x.Value()
+	r.rowWindows, r.colWindows = r.windows(x.Dims())
+	switch r.mode {
+	case MaxPool:
+		return r.forwardMax(x)
+	case AvgPool:
+		return r.average(x, false)
+	default:
+		panic(fmt.Sprintf("fn: invalid pooling mode %d", r.mode))
+	}
+}
+
+// forwardMax returns the maximum value of each window, keeping track of its
+// position in the input.
+func (r *Pooling[O]) forwardMax(x mat.Matrix) mat.Matrix {
+	// FIXME: avoid casting to specific type
+	xData := x.Data().F64()
+	cols := x.Columns()
+	y := make([]float64, 0, len(r.rowWindows)*len(r.colWindows))
+	r.argmax = make([]int, 0, cap(y))
+	for _, rw := range r.rowWindows {
+		for _, cw := range r.colWindows {
+			argmax := rw[0]*cols + cw[0]
+			for i := rw[0]; i < rw[1]; i++ {
+				for j := cw[0]; j < cw[1]; j++ {
+					if xData[i*cols+j] > xData[argmax] {
+						argmax = i*cols + j
+					}
+				}
+			}
+			r.argmax = append(r.argmax, argmax)
+			y = append(y, xData[argmax])
+		}
+	}
+	return x.NewMatrix(len(r.rowWindows), len(r.colWindows), float.SliceInterface(y))
+}
+
+// average returns the average value of each window of x, computed as
+// p·x·qᵀ, where p and q are the averaging matrices of the rows and of the
+// columns. If transposed is true, it returns instead pᵀ·x·q, which maps the
+// gradients of the output to the gradients of the input.
+func (r *Pooling[O]) average(x mat.Matrix, transposed bool) mat.Matrix {
+	rows, cols := r.x.Value().Dims()
+	p := averagingMatrix(x, r.rowWindows, rows)
+	defer mat.ReleaseMatrix(p)
+	q := averagingMatrix(x, r.colWindows, cols)
+	defer mat.ReleaseMatrix(q)
+	if transposed {
+		return p.T().Mul(x).Mul(q)
+	}
+	return p.Mul(x).Mul(q.T())
+}
+
+// averagingMatrix returns a new len(windows)×length matrix, of the same
+// type of x, whose rows average the values within each window.
+func averagingMatrix(x mat.Matrix, windows [][2]int, length int) mat.Matrix {
+	data := make([]float64, len(windows)*length)
+	for i, w := range windows {
+		v := 1 / float64(w[1]-w[0])
+		for j := w[0]; j < w[1]; j++ {
+			data[i*length+j] = v
+		}
+	}
+	return x.NewMatrix(len(windows), length, float.SliceInterface(data))
+}
+
+// Backward computes the backward pass.
+func (r *Pooling[O]) Backward(gy mat.Matrix) {
+	if gy.Rows() != len(r.rowWindows) || gy.Columns() != len(r.colWindows) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if !r.x.RequiresGrad() {
+		return
+	}
+	var gx mat.Matrix
+	if r.mode == MaxPool {
+		rows, cols := r.x.Value().Dims()
+		gx = scatterData(gy, rows, cols, r.argmax)
+	} else {
+		gx = r.average(gy, true)
+	}
+	defer mat.ReleaseMatrix(gx)
+	r.x.AccGrad(gx)
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Pooling[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	rows, cols := r.x.Value().Dims()
+	if r.mode == MaxPool {
+		return []O{g.NewOperator(&scatter[O]{x: gy, rows: rows, cols: cols, indices: r.argmax})}
+	}
+	x := r.x.Value()
+	p := g.NewConstant(averagingMatrix(x, r.rowWindows, rows).T())
+	q := g.NewConstant(averagingMatrix(x, r.colWindows, cols))
+	return []O{g.NewOperator(NewMul(g.NewOperator(NewMul(p, gy)), q))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Pooling[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		if r.mode == MaxPool {
+			return gatherData(t, len(r.rowWindows), len(r.colWindows), r.argmax)
+		}
+		return r.average(t, false)
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPooling_Forward(t *testing.T) {
+	t.Run("float32", testPoolingForward[float32])
+	t.Run("float64", testPoolingForward[float64])
+}
+
+func testPoolingForward[T float.DType](t *testing.T) {
+	newX := func() *variable {
+		return &variable{
+			value: mat.NewDense(3, 4, []T{
+				0.4, -0.1, -0.9, -0.5,
+				-0.4, 0.3, 0.7, -0.3,
+				0.8, 0.2, -0.6, 0.7,
+			}),
+			grad:         nil,
+			requiresGrad: true,
+		}
+	}
+	gy := mat.NewDense(2, 3, []T{
+		0.1, -0.2, 0.3,
+		-0.4, 0.5, -0.6,
+	})
+
+	t.Run("overlapping max", func(t *testing.T) {
+		x := newX()
+		f := NewPooling(x, PoolingConfig{Mode: MaxPool, Rows: 2, Columns: 2, RowStride: 1, ColumnStride: 1})
+		assert.Equal(t, []*variable{x}, f.Operands())
+
+		y := f.Forward()
+		assert.Equal(t, 2, y.Rows())
+		assert.Equal(t, 3, y.Columns())
+		assert.InDeltaSlice(t, []T{
+			0.4, 0.7, 0.7,
+			0.8, 0.7, 0.7,
+		}, y.Data(), 1.0e-6)
+
+		f.Backward(gy)
+		assert.InDeltaSlice(t, []T{
+			0.1, 0, 0, 0,
+			0, 0, 0, 0,
+			-0.4, 0, 0, 0,
+		}, x.grad.Data(), 1.0e-6)
+	})
+
+	t.Run("overlapping average", func(t *testing.T) {
+		x := newX()
+		f := NewPooling(x, PoolingConfig{Mode: AvgPool, Rows: 2, Columns: 2, RowStride: 1, ColumnStride: 1})
+		y := f.Forward()
+		assert.InDeltaSlice(t, []T{
+			0.05, 0, -0.25,
+			0.225, 0.15, 0.125,
+		}, y.Data(), 1.0e-6)
+
+		f.Backward(gy)
+		assert.InDeltaSlice(t, []T{
+			0.025, -0.025, 0.025, 0.075,
+			-0.075, 0, 0, -0.075,
+			-0.1, 0.025, -0.025, -0.15,
+		}, x.grad.Data(), 1.0e-6)
+	})
+
+	t.Run("non-overlapping average", func(t *testing.T) {
+		x := newX()
+		y := NewPooling(x, PoolingConfig{Mode: AvgPool, Rows: 1, Columns: 2}).Forward()
+		assert.Equal(t, 3, y.Rows())
+		assert.Equal(t, 2, y.Columns())
+		assert.InDeltaSlice(t, []T{
+			0.15, -0.7,
+			-0.05, 0.2,
+			0.5, 0.05,
+		}, y.Data(), 1.0e-6)
+	})
+
+	t.Run("truncated max", func(t *testing.T) {
+		x := newX()
+		f := NewPooling(x, PoolingConfig{Mode: MaxPool, Rows: 2, Columns: 3, ColumnStride: 2, Truncate: true})
+		y := f.Forward()
+		assert.Equal(t, 1, y.Rows())
+		assert.Equal(t, 1, y.Columns())
+		assert.InDeltaSlice(t, []T{0.7}, y.Data(), 1.0e-6)
+
+		f.Backward(mat.NewScalar[T](0.5))
+		assert.InDeltaSlice(t, []T{
+			0, 0, 0, 0,
+			0, 0, 0.5, 0,
+			0, 0, 0, 0,
+		}, x.grad.Data(), 1.0e-6)
+	})
+
+	t.Run("adaptive max", func(t *testing.T) {
+		x := newX()
+		f := NewAdaptivePooling(x, MaxPool, 2, 3)
+		y := f.Forward()
+		assert.InDeltaSlice(t, []T{
+			0.4, 0.7, 0.7,
+			0.8, 0.7, 0.7,
+		}, y.Data(), 1.0e-6)
+	})
+
+	t.Run("adaptive average", func(t *testing.T) {
+		x := newX()
+		f := NewAdaptivePooling(x, AvgPool, 1, 3)
+		y := f.Forward()
+		assert.Equal(t, 1, y.Rows())
+		assert.Equal(t, 3, y.Columns())
+		assert.InDeltaSlice(t, []T{0.2, -0.066667, -0.15}, y.Data(), 1.0e-6)
+
+		f.Backward(mat.NewDense(1, 3, []T{0.6, -1.2, 1.8}))
+		assert.InDeltaSlice(t, []T{
+			0.1, -0.1, 0.1, 0.3,
+			0.1, -0.1, 0.1, 0.3,
+			0.1, -0.1, 0.1, 0.3,
+		}, x.grad.Data(), 1.0e-6)
+	})
+
+	t.Run("incompatible gradients", func(t *testing.T) {
+		f := NewPooling(newX(), PoolingConfig{Rows: 2, Columns: 2, Truncate: true})
+		f.Forward()
+		assert.Panics(t, func() { f.Backward(mat.NewEmptyDense[T](2, 2)) })
+	})
+}
+
+func TestPooling_InvalidArguments(t *testing.T) {
+	x := &variable{value: mat.NewEmptyDense[float64](3, 4)}
+	assert.Panics(t, func() { NewPooling(x, PoolingConfig{Rows: 4, Columns: 2}).Forward() }, "large window")
+	assert.Panics(t, func() { NewPooling(x, PoolingConfig{Rows: 2, Columns: 2}).Forward() }, "uncovered input")
+	assert.Panics(t, func() { NewPooling(x, PoolingConfig{Rows: 0, Columns: 2}) }, "empty window")
+	assert.Panics(t, func() { NewPooling(x, PoolingConfig{Rows: 2, Columns: 2, RowStride: -1}) }, "negative stride")
+	assert.Panics(t, func() { NewAdaptivePooling(x, MaxPool, 0, 2) }, "empty output")
+	assert.Panics(t, func() { NewPooling(x, PoolingConfig{Mode: 2, Rows: 1, Columns: 2}).Forward() }, "invalid mode")
+}
+
+func TestAdaptiveWindows(t *testing.T) {
+	assert.Equal(t, [][2]int{{0, 2}, {1, 3}, {2, 4}}, adaptiveWindows(4, 3))
+	assert.Equal(t, [][2]int{{0, 1}, {0, 1}}, adaptiveWindows(1, 2))
+	assert.Equal(t, [][2]int{{0, 3}, {2, 5}}, adaptiveWindows(5, 2))
+}
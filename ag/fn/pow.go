@@ -44,3 +44,22 @@ func (r *Pow[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Pow[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	df := g.NewOperator(NewProdScalar(
+		g.NewOperator(NewPow(r.x, r.power-1)),
+		newScalarConstant(g, r.x, r.power),
+	))
+	return []O{g.NewOperator(NewProd(df, gy))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Pow[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return r.x.Value().Pow(r.power - 1).ProdScalarInPlace(r.power).ProdInPlace(t)
+	})
+}
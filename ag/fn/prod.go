@@ -9,6 +9,9 @@ import (
 )
 
 // Prod is an operator to perform element-wise product over two values.
+//
+// The values are broadcast against each other if their dimensions differ
+// (see mat.BroadcastDims), and the gradients are reduced accordingly.
 type Prod[O Operand] struct {
 	x1 O
 	x2 O
@@ -34,19 +37,35 @@ func (r *Prod[O]) Forward() mat.Matrix {
 
 // Backward computes the backward pass.
 func (r *Prod[O]) Backward(gy mat.Matrix) {
-	x1v := r.x1.Value()
-	x2v := r.x2.Value()
-	if !mat.SameDims(x1v, gy) || !mat.SameDims(x2v, gy) {
-		panic("fn: matrices have incompatible dimensions")
-	}
+	broadcastGradDims(r.x1.Value(), r.x2.Value(), gy)
 	if r.x1.RequiresGrad() {
 		gx := r.x2.Value().Prod(gy)
 		defer mat.ReleaseMatrix(gx)
-		r.x1.AccGrad(gx)
+		accUnbroadcastGrad(r.x1, gx)
 	}
 	if r.x2.RequiresGrad() {
 		gx := r.x1.Value().Prod(gy)
 		defer mat.ReleaseMatrix(gx)
-		r.x2.AccGrad(gx)
+		accUnbroadcastGrad(r.x2, gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Prod[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x1.RequiresGrad() {
+		gxs[0] = unbroadcastGraph(g, g.NewOperator(NewProd(r.x2, gy)), r.x1)
 	}
+	if r.x2.RequiresGrad() {
+		gxs[1] = unbroadcastGraph(g, g.NewOperator(NewProd(r.x1, gy)), r.x2)
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Prod[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return sumTangents(
+		prodTangent(tangents[0], r.x2.Value()),
+		prodTangent(tangents[1], r.x1.Value()),
+	)
 }
@@ -50,3 +50,25 @@ func (r *ProdScalar[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *ProdScalar[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x1.RequiresGrad() {
+		gxs[0] = g.NewOperator(NewProdScalar(gy, r.x2))
+	}
+	if r.x2.RequiresGrad() {
+		gxs[1] = g.NewOperator(NewReduceSum(g.NewOperator(NewProd(gy, r.x1))))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *ProdScalar[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return sumTangents(
+		scaleTangent(tangents[0], r.x2.Value().Scalar().F64()),
+		mapTangent(tangents[1], func(t mat.Matrix) mat.Matrix {
+			return r.x1.Value().ProdScalar(t.Scalar().F64())
+		}),
+	)
+}
@@ -47,3 +47,19 @@ func (r *ReduceMax[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *ReduceMax[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	rows, cols := r.x.Value().Dims()
+	return []O{g.NewOperator(&scatter[O]{x: gy, rows: rows, cols: cols, indices: []int{r.argmax}})}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *ReduceMax[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return t.AtVec(r.argmax)
+	})
+}
@@ -43,3 +43,21 @@ func (r *ReduceMean[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *ReduceMean[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	x := r.x.Value()
+	size := x.Size()
+	c := g.NewConstant(x.NewInitVec(size, 1/float64(size)))
+	return []O{g.NewOperator(NewProdScalar(c, gy))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *ReduceMean[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return t.Sum().ProdScalarInPlace(1 / float64(t.Size()))
+	})
+}
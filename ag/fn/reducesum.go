@@ -42,3 +42,18 @@ func (r *ReduceSum[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *ReduceSum[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	x := r.x.Value()
+	ones := g.NewConstant(x.NewInitVec(x.Size(), 1))
+	return []O{g.NewOperator(NewProdScalar(ones, gy))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *ReduceSum[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], mat.Matrix.Sum)
+}
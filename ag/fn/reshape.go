@@ -46,3 +46,19 @@ func (r *Reshape[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Reshape[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	rows, cols := r.x.Value().Dims()
+	return []O{g.NewOperator(NewReshape(gy, rows, cols))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Reshape[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return t.Reshape(r.rows, r.cols)
+	})
+}
@@ -48,3 +48,20 @@ func (r *ReverseSubScalar[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *ReverseSubScalar[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x1.RequiresGrad() {
+		gxs[0] = g.NewOperator(NewNeg(gy))
+	}
+	if r.x2.RequiresGrad() {
+		gxs[1] = g.NewOperator(NewReduceSum(gy))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *ReverseSubScalar[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return sumTangents(scaleTangent(tangents[0], -1), broadcastTangent(tangents[1], r.x1.Value()))
+}
@@ -52,3 +52,18 @@ func rotate(m mat.Matrix, i int) mat.Matrix {
 
 	return m.NewConcatV(right, left)
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *RotateR[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	return []O{g.NewOperator(NewRotateR(gy, r.x.Value().Size()-r.i))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *RotateR[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return rotate(t, t.Size()-r.i)
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// RowwiseSoftmax is a Function which applies the softmax function to each
+// row of a matrix independently (see mat.RowwiseSoftmax).
+type RowwiseSoftmax[O Operand] struct {
+	x O
+	y mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewRowwiseSoftmax returns a new RowwiseSoftmax Function.
+func NewRowwiseSoftmax[O Operand](x O) *RowwiseSoftmax[O] {
+	return &RowwiseSoftmax[O]{
+		x: x,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *RowwiseSoftmax[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of this function.
+func (r *RowwiseSoftmax[O]) Forward() mat.Matrix {
+	r.y = mat.RowwiseSoftmax(r.x.Value())
+	return r.y
+}
+
+// Backward computes the backward pass.
+func (r *RowwiseSoftmax[O]) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x.Value(), gy) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		gx := rowwiseSoftmaxJVP(r.y, gy)
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *RowwiseSoftmax[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	y := g.NewOperator(NewRowwiseSoftmax(r.x))
+	yg := g.NewOperator(NewProd(y, gy))
+	cols := gy.Value().Columns()
+	ones := g.NewConstant(gy.Value().NewInitMatrix(cols, 1, 1))
+	dots := g.NewOperator(NewMul(yg, ones)) // the sum of each row, broadcast below
+	return []O{g.NewOperator(NewProd(y, g.NewOperator(NewSub(gy, dots))))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *RowwiseSoftmax[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return rowwiseSoftmaxJVP(r.y, t)
+	})
+}
+
+// rowwiseSoftmaxJVP returns the product between the Jacobian of the softmax
+// of each row, given its output y, and the corresponding row of v. Since the
+// Jacobian is symmetric, it is used both for the forward and the backward
+// mode.
+func rowwiseSoftmaxJVP(y, v mat.Matrix) mat.Matrix {
+	rows, cols := y.Dims()
+	yData := y.Data().F64()
+	vData := v.Data().F64()
+	out := make([]float64, len(yData))
+	for i := 0; i < rows; i++ {
+		from, to := i*cols, (i+1)*cols
+		dot := 0.0
+		for j := from; j < to; j++ {
+			dot += yData[j] * vData[j]
+		}
+		for j := from; j < to; j++ {
+			out[j] = yData[j] * (vData[j] - dot)
+		}
+	}
+	return y.NewMatrix(rows, cols, float.SliceInterface(out))
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowwiseSoftmax_Forward(t *testing.T) {
+	t.Run("float32", testRowwiseSoftmaxForward[float32])
+	t.Run("float64", testRowwiseSoftmaxForward[float64])
+}
+
+func testRowwiseSoftmaxForward[T float.DType](t *testing.T) {
+	rows := [][]T{
+		{-0.41, -1.08, 0, 0.1},
+		{0.8, 0.2, -0.3, 0.4},
+	}
+	grads := [][]T{
+		{0.2, -0.5, 0.7, 0.1},
+		{-0.3, 0.6, 0.1, 0.0},
+	}
+
+	x := &variable{
+		value:        mat.NewDense(2, 4, append(append([]T{}, rows[0]...), rows[1]...)),
+		requiresGrad: true,
+	}
+	f := NewRowwiseSoftmax(x)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	f.Backward(mat.NewDense(2, 4, append(append([]T{}, grads[0]...), grads[1]...)))
+
+	// each row must be equal to the result of Softmax
+	for i, row := range rows {
+		xi := &variable{
+			value:        mat.NewVecDense(row),
+			requiresGrad: true,
+		}
+		fi := NewSoftmax(xi)
+		yi := fi.Forward()
+		fi.Backward(mat.NewVecDense(grads[i]))
+
+		assert.InDeltaSlice(t, yi.Data(), y.ExtractRow(i).Data(), 1.0e-6)
+		assert.InDeltaSlice(t, xi.grad.Data(), x.grad.ExtractRow(i).Data(), 1.0e-6)
+	}
+}
@@ -54,3 +54,17 @@ func (r *ScalarMax[O]) Backward(gy mat.Matrix) {
 		target.AccGrad(gy)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *ScalarMax[O]) BackwardGraph(_ Graph[O], gy O) []O {
+	gxs := make([]O, len(r.xs))
+	if r.xs[r.argmax].RequiresGrad() {
+		gxs[r.argmax] = gy
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *ScalarMax[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[r.argmax], mat.Matrix.Clone)
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// ScatterAdd is a Function which adds the rows (dim 0) or the columns
+// (dim 1) of the source matrix to the rows or the columns of the input
+// matrix at the given indices. The rows or the columns scattered to the
+// same index are summed.
+//
+// It is the counterpart of IndexSelect:
+//
+//	y = x
+//	y[indices[k], :] += src[k, :]  (dim 0)
+//	y[:, indices[k]] += src[:, k]  (dim 1)
+type ScatterAdd[O Operand] struct {
+	x          O
+	src        O
+	dim        int
+	dimIndices []int
+	indices    []int // flat positions, set by Forward
+}
+
+// NewScatterAdd returns a new ScatterAdd Function.
+func NewScatterAdd[O Operand](x O, dim int, indices []int, src O) *ScatterAdd[O] {
+	checkDim(dim)
+	return &ScatterAdd[O]{
+		x:          x,
+		src:        src,
+		dim:        dim,
+		dimIndices: indices,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *ScatterAdd[O]) Operands() []O {
+	return []O{r.x, r.src}
+}
+
+// Forward computes the output of the function.
+func (r *ScatterAdd[O]) Forward() mat.Matrix {
+	xv := r.x.Value()
+	rows, cols := xv.Dims()
+	checkIndices(r.dimIndices, rows, cols, r.dim)
+	srcRows, srcCols := r.src.Value().Dims()
+	if r.dim == 0 && (srcRows != len(r.dimIndices) || srcCols != cols) || r.dim == 1 && (srcRows != rows || srcCols != len(r.dimIndices)) {
+		panic(fmt.Sprintf("fn: the source matrix has incompatible dimensions %d×%d", srcRows, srcCols))
+	}
+	r.indices = indexSelectIndices(rows, cols, r.dim, r.dimIndices)
+
+	y := scatterData(r.src.Value(), rows, cols, r.indices)
+	y.AddInPlace(xv)
+	return y
+}
+
+// Backward computes the backward pass.
+func (r *ScatterAdd[O]) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x.Value(), gy) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		r.x.AccGrad(gy)
+	}
+	if r.src.RequiresGrad() {
+		gsrc := gatherData(gy, r.src.Value().Rows(), r.src.Value().Columns(), r.indices)
+		defer mat.ReleaseMatrix(gsrc)
+		r.src.AccGrad(gsrc)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *ScatterAdd[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x.RequiresGrad() {
+		gxs[0] = gy
+	}
+	if r.src.RequiresGrad() {
+		rows, cols := r.src.Value().Dims()
+		gxs[1] = g.NewOperator(&gather[O]{x: gy, rows: rows, cols: cols, indices: r.indices})
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *ScatterAdd[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	rows, cols := r.x.Value().Dims()
+	return sumTangents(tangents[0], mapTangent(tangents[1], func(t mat.Matrix) mat.Matrix {
+		return scatterData(t, rows, cols, r.indices)
+	}))
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScatterAdd_Forward(t *testing.T) {
+	t.Run("float32", testScatterAddForward[float32])
+	t.Run("float64", testScatterAddForward[float64])
+}
+
+func testScatterAddForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(3, 2, []T{
+			0.1, 0.2,
+			0.3, 0.4,
+			0.5, 0.6,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	t.Run("rows", func(t *testing.T) {
+		x.grad = nil
+		src := &variable{
+			value:        mat.NewDense(3, 2, []T{1, 2, 3, 4, 5, 6}),
+			grad:         nil,
+			requiresGrad: true,
+		}
+		f := NewScatterAdd(x, 0, []int{2, 0, 2}, src)
+		assert.Equal(t, []*variable{x, src}, f.Operands())
+
+		y := f.Forward()
+		assert.InDeltaSlice(t, []T{
+			3.1, 4.2,
+			0.3, 0.4,
+			6.5, 8.6,
+		}, y.Data(), 1.0e-6)
+
+		f.Backward(mat.NewDense(3, 2, []T{1, 2, 3, 4, 5, 6}))
+		assert.InDeltaSlice(t, []T{1, 2, 3, 4, 5, 6}, x.grad.Data(), 1.0e-6)
+		assert.InDeltaSlice(t, []T{5, 6, 1, 2, 5, 6}, src.grad.Data(), 1.0e-6)
+	})
+
+	t.Run("columns", func(t *testing.T) {
+		x.grad = nil
+		src := &variable{
+			value:        mat.NewVecDense([]T{1, 2, 3}),
+			grad:         nil,
+			requiresGrad: true,
+		}
+		f := NewScatterAdd(x, 1, []int{1}, src)
+
+		y := f.Forward()
+		assert.InDeltaSlice(t, []T{
+			0.1, 1.2,
+			0.3, 2.4,
+			0.5, 3.6,
+		}, y.Data(), 1.0e-6)
+
+		f.Backward(mat.NewDense(3, 2, []T{1, 2, 3, 4, 5, 6}))
+		assert.InDeltaSlice(t, []T{1, 2, 3, 4, 5, 6}, x.grad.Data(), 1.0e-6)
+		assert.InDeltaSlice(t, []T{2, 4, 6}, src.grad.Data(), 1.0e-6)
+	})
+
+	src := &variable{value: mat.NewDense(2, 2, []T{1, 2, 3, 4})}
+	assert.Panics(t, func() { NewScatterAdd(x, 0, []int{0}, src).Forward() })
+	assert.Panics(t, func() { NewScatterAdd(x, 0, []int{0, 3}, src).Forward() })
+	assert.Panics(t, func() { NewScatterAdd(x, 1, []int{0, 1}, src).Forward() })
+}
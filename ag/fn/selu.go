@@ -54,3 +54,27 @@ func (r *SELU[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *SELU[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 3)
+	if r.x.RequiresGrad() {
+		scale := r.scale.Value().Scalar().F64()
+		alphaScale := newScalarConstant(g, r.x, r.alpha.Value().Scalar().F64()*scale)
+		lower := g.NewOperator(NewProdScalar(g.NewOperator(NewExp(r.x)), alphaScale))
+		df := piecewiseDerivGraph(g, r.x, 0, scale, lower)
+		gxs[0] = g.NewOperator(NewProd(df, gy))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *SELU[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return r.x.Value().ApplyWithAlpha(
+			seluDeriv,
+			r.alpha.Value().Scalar().F64(),
+			r.scale.Value().Scalar().F64(),
+		).ProdInPlace(t)
+	})
+}
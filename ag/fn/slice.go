@@ -54,3 +54,20 @@ func (s *Slice[O]) Backward(gy mat.Matrix) {
 		s.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (s *Slice[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !s.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	rows, cols := s.x.Value().Dims()
+	indices := rangeIndices(cols, s.fromRow, s.fromCol, s.toRow, s.toCol)
+	return []O{g.NewOperator(&scatter[O]{x: gy, rows: rows, cols: cols, indices: indices})}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (s *Slice[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return t.Slice(s.fromRow, s.fromCol, s.toRow, s.toCol)
+	})
+}
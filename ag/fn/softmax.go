@@ -54,3 +54,22 @@ func (r *Softmax[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Softmax[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	y := g.NewOperator(NewSoftmax(r.x))
+	dot := g.NewOperator(NewDot(y, gy))
+	return []O{g.NewOperator(NewProd(y, g.NewOperator(NewSubScalar(gy, dot))))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Softmax[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		prod := r.y.Prod(t)
+		defer mat.ReleaseMatrix(prod)
+		return t.SubScalar(prod.Sum().Scalar().F64()).ProdInPlace(r.y)
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// SoftmaxCrossEntropy is a fused softmax and negative log-likelihood
+// function, computing the cross-entropy loss of the raw scores (logits) of
+// vector x with respect to the gold class c:
+//
+//	y = log(sum(exp(x))) - x[c]
+//
+// The log-sum-exp is computed in a numerically stable way, and the gradient
+// is simply softmax(x) - onehot(c).
+type SoftmaxCrossEntropy[O Operand] struct {
+	x O
+	c int
+	p mat.Matrix // the softmax of x, initialized during the forward pass (required by the backward pass)
+}
+
+// NewSoftmaxCrossEntropy returns a new SoftmaxCrossEntropy Function.
+func NewSoftmaxCrossEntropy[O Operand](x O, c int) *SoftmaxCrossEntropy[O] {
+	return &SoftmaxCrossEntropy[O]{
+		x: x,
+		c: c,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *SoftmaxCrossEntropy[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of this function.
+func (r *SoftmaxCrossEntropy[O]) Forward() mat.Matrix {
+	xv := r.x.Value()
+	if !mat.IsVector(xv) {
+		panic("fn: the input must be a vector")
+	}
+	if r.c < 0 || r.c >= xv.Size() {
+		panic("fn: invalid class index")
+	}
+	lse := logSumExp(xv)
+	shifted := xv.SubScalar(lse)
+	defer mat.ReleaseMatrix(shifted)
+	r.p = shifted.Exp()
+	return xv.NewScalar(lse - xv.ScalarAtVec(r.c).F64())
+}
+
+// Backward computes the backward pass.
+func (r *SoftmaxCrossEntropy[O]) Backward(gy mat.Matrix) {
+	if !mat.IsScalar(gy) {
+		panic("fn: the gradient had to be a scalar")
+	}
+	if r.x.RequiresGrad() {
+		gx := r.softmaxMinusOneHot()
+		defer mat.ReleaseMatrix(gx)
+		gx.ProdScalarInPlace(gy.Scalar().F64())
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *SoftmaxCrossEntropy[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	softmax := g.NewOperator(NewExp(g.NewOperator(NewLogSoftmax(r.x))))
+	oneHot := r.x.Value().ZerosLike()
+	oneHot.SetVecScalar(r.c, float.Interface(1.0))
+	return []O{g.NewOperator(NewProdScalar(g.NewOperator(NewSub(softmax, g.NewConstant(oneHot))), gy))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *SoftmaxCrossEntropy[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		d := r.softmaxMinusOneHot()
+		defer mat.ReleaseMatrix(d)
+		return d.ProdInPlace(t).Sum()
+	})
+}
+
+// softmaxMinusOneHot returns a new matrix with the softmax of x, minus one
+// at the position of the gold class.
+func (r *SoftmaxCrossEntropy[O]) softmaxMinusOneHot() mat.Matrix {
+	d := r.p.Clone()
+	d.SetVecScalar(r.c, float.Interface(d.ScalarAtVec(r.c).F64()-1))
+	return d
+}
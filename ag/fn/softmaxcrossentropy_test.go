@@ -0,0 +1,58 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoftmaxCrossEntropy_Forward(t *testing.T) {
+	t.Run("float32", testSoftmaxCrossEntropyForward[float32])
+	t.Run("float64", testSoftmaxCrossEntropyForward[float64])
+}
+
+func testSoftmaxCrossEntropyForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]T{-0.41, -1.08, 0, 0.87, -0.19, -0.75}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewSoftmaxCrossEntropy(x, 2)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.InDelta(t, 1.738619, y.Scalar().F64(), 1.0e-5)
+
+	f.Backward(mat.NewScalar[T](0.5))
+	assert.InDeltaSlice(t, []T{0.0583225, 0.029844, -0.4121185, 0.209765, 0.0726745, 0.0415125}, x.grad.Data(), 1.0e-5)
+
+	assert.Panics(t, func() { NewSoftmaxCrossEntropy(x, 6).Forward() })
+	assert.Panics(t, func() { NewSoftmaxCrossEntropy(&variable{value: mat.NewEmptyDense[T](2, 2)}, 0).Forward() })
+}
+
+func TestSoftmaxCrossEntropy_LargeLogits(t *testing.T) {
+	t.Run("float32", testSoftmaxCrossEntropyLargeLogits[float32])
+	t.Run("float64", testSoftmaxCrossEntropyLargeLogits[float64])
+}
+
+func testSoftmaxCrossEntropyLargeLogits[T float.DType](t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]T{1000, 0, -1000}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewSoftmaxCrossEntropy(x, 1)
+	y := f.Forward()
+	assert.InDelta(t, 1000, y.Scalar().F64(), 1.0e-3)
+
+	f.Backward(mat.NewScalar[T](1))
+	assert.InDeltaSlice(t, []T{1, -1, 0}, x.grad.Data(), 1.0e-6)
+}
@@ -54,3 +54,26 @@ func (r *SoftPlus[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *SoftPlus[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 3)
+	if r.x.RequiresGrad() {
+		beta := newScalarConstant(g, r.x, r.beta.Value().Scalar().F64())
+		lower := g.NewOperator(NewSigmoid(g.NewOperator(NewProdScalar(r.x, beta))))
+		df := piecewiseDerivGraph(g, r.x, r.threshold.Value().Scalar().F64(), 1, lower)
+		gxs[0] = g.NewOperator(NewProd(df, gy))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *SoftPlus[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return r.x.Value().ApplyWithAlpha(
+			softPlusDeriv,
+			r.beta.Value().Scalar().F64(),
+			r.threshold.Value().Scalar().F64(),
+		).ProdInPlace(t)
+	})
+}
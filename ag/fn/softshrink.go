@@ -44,3 +44,20 @@ func (r *SoftShrink[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *SoftShrink[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x.RequiresGrad() {
+		df := g.NewConstant(r.x.Value().ApplyWithAlpha(softShrinkDeriv, r.lambda.Value().Scalar().F64()))
+		gxs[0] = g.NewOperator(NewProd(df, gy))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *SoftShrink[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return r.x.Value().ApplyWithAlpha(softShrinkDeriv, r.lambda.Value().Scalar().F64()).ProdInPlace(t)
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// Solve is a Function returning the solution y of the linear system
+// x1·y = x2, where x1 is a square matrix, and x2 is a matrix (e.g. a column
+// vector) with the same number of rows of x1 (see mat.Solve).
+//
+//	y = x1⁻¹·x2
+type Solve[O Operand] struct {
+	x1 O
+	x2 O
+	y  mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewSolve returns a new Solve Function.
+func NewSolve[O Operand](x1, x2 O) *Solve[O] {
+	return &Solve[O]{
+		x1: x1,
+		x2: x2,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *Solve[O]) Operands() []O {
+	return []O{r.x1, r.x2}
+}
+
+// Forward computes the output of the function.
+func (r *Solve[O]) Forward() mat.Matrix {
+	r.y = mat.Solve(r.x1.Value(), r.x2.Value())
+	return r.y
+}
+
+// Backward computes the backward pass.
+func (r *Solve[O]) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.y, gy) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if !r.x1.RequiresGrad() && !r.x2.RequiresGrad() {
+		return
+	}
+	// gx2 = x1⁻ᵀ·gy
+	x1T := r.x1.Value().T()
+	defer mat.ReleaseMatrix(x1T)
+	gx2 := mat.Solve(x1T, gy)
+	defer mat.ReleaseMatrix(gx2)
+	if r.x1.RequiresGrad() {
+		// gx1 = -gx2·yᵀ
+		yT := r.y.T()
+		defer mat.ReleaseMatrix(yT)
+		gx1 := gx2.Mul(yT)
+		defer mat.ReleaseMatrix(gx1)
+		gx1.ProdScalarInPlace(-1)
+		r.x1.AccGrad(gx1)
+	}
+	if r.x2.RequiresGrad() {
+		r.x2.AccGrad(gx2)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Solve[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if !r.x1.RequiresGrad() && !r.x2.RequiresGrad() {
+		return gxs
+	}
+	gx2 := g.NewOperator(NewSolve(g.NewOperator(NewTranspose(r.x1)), gy))
+	if r.x1.RequiresGrad() {
+		yT := g.NewOperator(NewTranspose(g.NewOperator(NewSolve(r.x1, r.x2))))
+		gxs[0] = g.NewOperator(NewNeg(g.NewOperator(NewMul(gx2, yT))))
+	}
+	if r.x2.RequiresGrad() {
+		gxs[1] = gx2
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Solve[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	// x1⁻¹·(t2 - t1·y)
+	t := sumTangents(
+		mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+			return t.Mul(r.y).ProdScalarInPlace(-1)
+		}),
+		tangents[1],
+	)
+	if t == nil {
+		return nil
+	}
+	defer mat.ReleaseMatrix(t)
+	return mat.Solve(r.x1.Value(), t)
+}
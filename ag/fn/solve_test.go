@@ -0,0 +1,41 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolve_Forward(t *testing.T) {
+	t.Run("float32", testSolveForward[float32])
+	t.Run("float64", testSolveForward[float64])
+}
+
+func testSolveForward[T float.DType](t *testing.T) {
+	a := &variable{
+		value:        mat.NewDense(2, 2, []T{4, 7, 2, 6}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	b := &variable{
+		value:        mat.NewVecDense([]T{1, 2}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewSolve(a, b)
+	assert.Equal(t, []*variable{a, b}, f.Operands())
+
+	y := f.Forward()
+	assert.InDeltaSlice(t, []T{-0.8, 0.6}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewVecDense([]T{1, 1}))
+	assert.InDeltaSlice(t, []T{0.32, -0.24, -0.24, 0.18}, a.grad.Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{0.4, -0.3}, b.grad.Data(), 1.0e-6)
+}
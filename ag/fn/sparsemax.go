@@ -103,3 +103,41 @@ func sparseMaxCommon(v mat.Matrix) (zs, cumSumInput mat.Matrix, bounds []float64
 
 	return zs, cumSumInput, bounds, tau
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *SparseMax[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	var nzCount float64
+	r.y.DoVecNonZero(func(_ int, _ float64) {
+		nzCount++
+	})
+	mask := g.NewConstant(r.y.Apply(func(_, _ int, v float64) float64 {
+		if v != 0 {
+			return 1
+		}
+		return 0
+	}))
+	nzMean := g.NewOperator(NewDivScalar(g.NewOperator(NewDot(mask, gy)), newScalarConstant(g, gy, nzCount)))
+	return []O{g.NewOperator(NewProd(mask, g.NewOperator(NewSubScalar(gy, nzMean))))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *SparseMax[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		var nzSum float64
+		var nzCount float64
+		r.y.DoVecNonZero(func(i int, _ float64) {
+			nzSum += t.ScalarAtVec(i).F64()
+			nzCount++
+		})
+		nzMean := nzSum / nzCount
+
+		ty := t.ZerosLike()
+		r.y.DoVecNonZero(func(i int, _ float64) {
+			ty.SetVecScalar(i, float.Interface(t.ScalarAtVec(i).F64()-nzMean))
+		})
+		return ty
+	})
+}
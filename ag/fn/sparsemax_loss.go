@@ -72,3 +72,27 @@ func (r *SparseMaxLoss[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *SparseMaxLoss[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	sparseMax := g.NewOperator(NewSparseMax(r.x))
+	gySum := g.NewOperator(NewReduceSum(gy))
+	return []O{g.NewOperator(NewSub(gy, g.NewOperator(NewProdScalar(sparseMax, gySum))))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *SparseMaxLoss[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		tau := r.tau
+		sparseMax := r.x.Value().Apply(func(_, _ int, v float64) float64 {
+			return math.Max(0, v-tau)
+		})
+		defer mat.ReleaseMatrix(sparseMax)
+		prod := sparseMax.Prod(t)
+		defer mat.ReleaseMatrix(prod)
+		return t.SubScalar(prod.Sum().Scalar().F64())
+	})
+}
@@ -41,3 +41,21 @@ func (r *Sqrt[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Sqrt[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	den := g.NewOperator(NewProdScalar(g.NewOperator(NewSqrt(r.x)), newScalarConstant(g, r.x, 2)))
+	return []O{g.NewOperator(NewDiv(gy, den))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Sqrt[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		d := r.x.Value().Sqrt().ProdScalarInPlace(2)
+		defer mat.ReleaseMatrix(d)
+		return t.Div(d)
+	})
+}
@@ -49,3 +49,27 @@ func (r *Stack[O]) Backward(gy mat.Matrix) {
 		mat.ReleaseMatrix(gyRow)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Stack[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, len(r.xs))
+	for i, x := range r.xs {
+		if !x.RequiresGrad() {
+			continue
+		}
+		rows, cols := x.Value().Dims()
+		size := rows * cols
+		indices := rangeIndices(1, i*size, 0, (i+1)*size, 1)
+		gxs[i] = g.NewOperator(&gather[O]{x: gy, rows: rows, cols: cols, indices: indices})
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Stack[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	ts := tangentsOrZeros(tangents, r.xs)
+	if ts == nil {
+		return nil
+	}
+	return ts[0].NewStack(ts...)
+}
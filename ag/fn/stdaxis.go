@@ -0,0 +1,77 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// StdAxis is a Function which computes the (biased) standard deviations of
+// the values of a matrix along the given axis (see SumAxis).
+//
+//	y = sqrt(mean((x - mean(x))²))
+type StdAxis[O Operand] struct {
+	x    O
+	axis int
+	dev  mat.Matrix // initialized during the forward pass (required by the backward pass)
+	y    mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewStdAxis returns a new StdAxis Function.
+func NewStdAxis[O Operand](x O, axis int) *StdAxis[O] {
+	checkDim(axis)
+	return &StdAxis[O]{
+		x:    x,
+		axis: axis,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *StdAxis[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *StdAxis[O]) Forward() mat.Matrix {
+	x := r.x.Value()
+	r.dev = deviations(x, r.axis)
+	r.y = x.StdAxis(r.axis)
+	return r.y
+}
+
+// Backward computes the backward pass.
+func (r *StdAxis[O]) Backward(gy mat.Matrix) {
+	x := r.x.Value()
+	checkAxisGrad(x, gy, r.axis)
+	if r.x.RequiresGrad() {
+		// gx = gy * (x - mean(x)) / (n * y)
+		gyy := gy.Div(r.y)
+		defer mat.ReleaseMatrix(gyy)
+		gx := r.dev.Prod(gyy)
+		defer mat.ReleaseMatrix(gx)
+		gx.ProdScalarInPlace(1 / float64(axisSize(x, r.axis)))
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *StdAxis[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	dev := deviationsGraph(g, r.x, r.axis)
+	gyy := g.NewOperator(NewDiv(gy, g.NewOperator(NewStdAxis(r.x, r.axis))))
+	c := newScalarConstant(g, r.x, 1/float64(axisSize(r.x.Value(), r.axis)))
+	return []O{g.NewOperator(NewProdScalar(g.NewOperator(NewProd(dev, gyy)), c))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *StdAxis[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		dt := r.dev.Prod(t)
+		defer mat.ReleaseMatrix(dt)
+		sum := dt.SumAxis(r.axis)
+		defer mat.ReleaseMatrix(sum)
+		return sum.Div(r.y).ProdScalarInPlace(1 / float64(axisSize(t, r.axis)))
+	})
+}
@@ -9,6 +9,9 @@ import (
 )
 
 // Sub is an element-wise subtraction function over two values.
+//
+// The values are broadcast against each other if their dimensions differ
+// (see mat.BroadcastDims), and the gradients are reduced accordingly.
 type Sub[O Operand] struct {
 	x1 O
 	x2 O
@@ -34,17 +37,31 @@ func (r *Sub[O]) Forward() mat.Matrix {
 
 // Backward computes the backward pass.
 func (r *Sub[O]) Backward(gy mat.Matrix) {
-	x1v := r.x1.Value()
-	x2v := r.x2.Value()
-	if !mat.SameDims(x1v, gy) || !mat.SameDims(x2v, gy) {
-		panic("fn: matrices have incompatible dimensions")
-	}
+	broadcastGradDims(r.x1.Value(), r.x2.Value(), gy)
 	if r.x1.RequiresGrad() {
-		r.x1.AccGrad(gy)
+		accUnbroadcastGrad(r.x1, gy)
 	}
 	if r.x2.RequiresGrad() {
 		gx := gy.ProdScalar(-1.0)
 		defer mat.ReleaseMatrix(gx)
-		r.x2.AccGrad(gx)
+		accUnbroadcastGrad(r.x2, gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Sub[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x1.RequiresGrad() {
+		gxs[0] = unbroadcastGraph(g, gy, r.x1)
 	}
+	if r.x2.RequiresGrad() {
+		gxs[1] = unbroadcastGraph(g, g.NewOperator(NewNeg(gy)), r.x2)
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Sub[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	rows, cols, _ := mat.BroadcastDims(r.x1.Value(), r.x2.Value())
+	return broadcastOutput(sumTangents(tangents[0], scaleTangent(tangents[1], -1)), rows, cols)
 }
@@ -48,3 +48,20 @@ func (r *SubScalar[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *SubScalar[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x1.RequiresGrad() {
+		gxs[0] = gy
+	}
+	if r.x2.RequiresGrad() {
+		gxs[1] = g.NewOperator(NewNeg(g.NewOperator(NewReduceSum(gy))))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *SubScalar[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return sumTangents(tangents[0], scaleTangent(broadcastTangent(tangents[1], r.x1.Value()), -1))
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// SumAxis is a Function which sums the values of a matrix along the given
+// axis: 0 reduces each column, resulting in a row vector, and 1 reduces
+// each row, resulting in a column vector (see mat.Matrix.SumAxis).
+type SumAxis[O Operand] struct {
+	x    O
+	axis int
+}
+
+// NewSumAxis returns a new SumAxis Function.
+func NewSumAxis[O Operand](x O, axis int) *SumAxis[O] {
+	checkDim(axis)
+	return &SumAxis[O]{
+		x:    x,
+		axis: axis,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *SumAxis[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *SumAxis[O]) Forward() mat.Matrix {
+	return r.x.Value().SumAxis(r.axis)
+}
+
+// Backward computes the backward pass.
+func (r *SumAxis[O]) Backward(gy mat.Matrix) {
+	x := r.x.Value()
+	checkAxisGrad(x, gy, r.axis)
+	if r.x.RequiresGrad() {
+		gx := mat.BroadcastTo(gy, x.Rows(), x.Columns())
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *SumAxis[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	rows, cols := r.x.Value().Dims()
+	return []O{g.NewOperator(NewBroadcastTo(gy, rows, cols))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *SumAxis[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return t.SumAxis(r.axis)
+	})
+}
+
+// checkAxisGrad panics if the dimensions of the gradients gy differ from
+// the dimensions of the reduction of x along the given axis.
+func checkAxisGrad(x, gy mat.Matrix, axis int) {
+	rows, cols := x.Dims()
+	if axis == 0 {
+		rows = 1
+	} else {
+		cols = 1
+	}
+	if gy.Rows() != rows || gy.Columns() != cols {
+		panic("fn: matrices have incompatible dimensions")
+	}
+}
+
+// axisSize returns the number of values of x reduced to a single value
+// along the given axis.
+func axisSize(x mat.Matrix, axis int) int {
+	if axis == 0 {
+		return x.Rows()
+	}
+	return x.Columns()
+}
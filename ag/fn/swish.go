@@ -52,3 +52,22 @@ func swishDeriv(_, _ int, v float64) float64 {
 	expPlusOne := exp + 1
 	return exp * (expPlusOne + v) / (expPlusOne * expPlusOne)
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (l *Swish[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !l.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	// d/dx x * sigmoid(x) = sigmoid(x) + x * sigmoid'(x)
+	s := g.NewOperator(NewSigmoid(l.x))
+	ds := sigmoidDerivGraph(g, l.x)
+	df := g.NewOperator(NewAdd(s, g.NewOperator(NewProd(l.x, ds))))
+	return []O{g.NewOperator(NewProd(df, gy))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (l *Swish[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return l.x.Value().Apply(swishDeriv).ProdInPlace(t)
+	})
+}
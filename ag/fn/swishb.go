@@ -59,3 +59,42 @@ func (r *SwishB[O]) Backward(gy mat.Matrix) {
 		r.beta.AccGrad(gb)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *SwishB[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if !r.x.RequiresGrad() && !r.beta.RequiresGrad() {
+		return gxs
+	}
+	// With s = sigmoid(beta * x) and s' = s * (1 - s):
+	//   d/dx    x * s = s + beta * x * s'
+	//   d/dbeta x * s = x² * s'
+	s := g.NewOperator(NewSigmoid(g.NewOperator(NewProdScalar(r.x, r.beta))))
+	ds := g.NewOperator(NewProd(s, g.NewOperator(NewReverseSubScalar(s, newScalarConstant(g, r.x, 1)))))
+	if r.x.RequiresGrad() {
+		b := g.NewOperator(NewProdScalar(g.NewOperator(NewProd(r.x, ds)), r.beta))
+		df := g.NewOperator(NewAdd(s, b))
+		gxs[0] = g.NewOperator(NewProd(df, gy))
+	}
+	if r.beta.RequiresGrad() {
+		db := g.NewOperator(NewProd(g.NewOperator(NewSquare(r.x)), ds))
+		gxs[1] = g.NewOperator(NewDot(db, gy))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *SwishB[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	beta := r.beta.Value().Scalar().F64()
+	return sumTangents(
+		mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+			return r.x.Value().ApplyWithAlpha(swishBDeriv, beta).ProdInPlace(t)
+		}),
+		mapTangent(tangents[1], func(t mat.Matrix) mat.Matrix {
+			tb := t.Scalar().F64()
+			return r.x.Value().Apply(func(_, _ int, v float64) float64 {
+				return swishBBetaDeriv(v, beta) * tb
+			})
+		}),
+	)
+}
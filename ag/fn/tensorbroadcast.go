@@ -0,0 +1,120 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// TensorBroadcastTo is a Function which broadcasts an operand, viewed as a
+// tensor with the given shape, to a new shape (see mat.Tensor.BroadcastTo).
+//
+// The output is the matrix representing the broadcast tensor
+// (see mat.Tensor.Matrix).
+type TensorBroadcastTo[O Operand] struct {
+	x     O
+	shape []int
+	to    []int
+}
+
+// NewTensorBroadcastTo returns a new TensorBroadcastTo Function.
+func NewTensorBroadcastTo[O Operand](x O, shape, to []int) *TensorBroadcastTo[O] {
+	return &TensorBroadcastTo[O]{
+		x:     x,
+		shape: append([]int{}, shape...),
+		to:    append([]int{}, to...),
+	}
+}
+
+// Operands returns the list of operands.
+func (r *TensorBroadcastTo[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *TensorBroadcastTo[O]) Forward() mat.Matrix {
+	return mat.TensorView(r.x.Value(), r.shape...).BroadcastTo(r.to...).Matrix()
+}
+
+// Backward computes the backward pass.
+func (r *TensorBroadcastTo[O]) Backward(gy mat.Matrix) {
+	if !r.x.RequiresGrad() {
+		return
+	}
+	gx := mat.TensorView(gy, r.to...).SumTo(r.shape...).Matrix()
+	defer mat.ReleaseMatrix(gx)
+	r.x.AccGrad(gx.ReshapeInPlace(r.x.Value().Dims()))
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *TensorBroadcastTo[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	gx := g.NewOperator(NewTensorSumTo(gy, r.to, r.shape))
+	return []O{reshapeGraph(g, gx, r.x)}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *TensorBroadcastTo[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return mat.TensorView(t, r.shape...).BroadcastTo(r.to...).Matrix()
+	})
+}
+
+// TensorSumTo is a Function which reduces an operand, viewed as a tensor
+// with the given shape, to a new shape, summing its elements along the
+// reduced dimensions (see mat.Tensor.SumTo).
+//
+// The output is the matrix representing the reduced tensor
+// (see mat.Tensor.Matrix).
+type TensorSumTo[O Operand] struct {
+	x     O
+	shape []int
+	to    []int
+}
+
+// NewTensorSumTo returns a new TensorSumTo Function.
+func NewTensorSumTo[O Operand](x O, shape, to []int) *TensorSumTo[O] {
+	return &TensorSumTo[O]{
+		x:     x,
+		shape: append([]int{}, shape...),
+		to:    append([]int{}, to...),
+	}
+}
+
+// Operands returns the list of operands.
+func (r *TensorSumTo[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *TensorSumTo[O]) Forward() mat.Matrix {
+	return mat.TensorView(r.x.Value(), r.shape...).SumTo(r.to...).Matrix()
+}
+
+// Backward computes the backward pass.
+func (r *TensorSumTo[O]) Backward(gy mat.Matrix) {
+	if !r.x.RequiresGrad() {
+		return
+	}
+	gx := mat.TensorView(gy, r.to...).BroadcastTo(r.shape...).Matrix()
+	defer mat.ReleaseMatrix(gx)
+	r.x.AccGrad(gx.ReshapeInPlace(r.x.Value().Dims()))
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *TensorSumTo[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	gx := g.NewOperator(NewTensorBroadcastTo(gy, r.to, r.shape))
+	return []O{reshapeGraph(g, gx, r.x)}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *TensorSumTo[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return mat.TensorView(t, r.shape...).SumTo(r.to...).Matrix()
+	})
+}
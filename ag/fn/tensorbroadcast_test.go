@@ -0,0 +1,63 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTensorBroadcastTo_Forward(t *testing.T) {
+	t.Run("float32", testTensorBroadcastToForward[float32])
+	t.Run("float64", testTensorBroadcastToForward[float64])
+}
+
+func testTensorBroadcastToForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value:        mat.NewDense(1, 3, []T{1, 2, 3}),
+		requiresGrad: true,
+	}
+
+	f := NewTensorBroadcastTo(x, []int{3}, []int{2, 2, 3})
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.Equal(t, 4, y.Rows())
+	assert.Equal(t, 3, y.Columns())
+	assert.Equal(t, []T{1, 2, 3, 1, 2, 3, 1, 2, 3, 1, 2, 3}, mat.Data[T](y))
+
+	f.Backward(mat.NewDense(4, 3, []T{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}))
+	assert.Equal(t, 1, x.grad.Rows())
+	assert.Equal(t, 3, x.grad.Columns())
+	assert.Equal(t, []T{22, 26, 30}, mat.Data[T](x.grad))
+}
+
+func TestTensorSumTo_Forward(t *testing.T) {
+	t.Run("float32", testTensorSumToForward[float32])
+	t.Run("float64", testTensorSumToForward[float64])
+}
+
+func testTensorSumToForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value:        mat.NewDense(4, 3, []T{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}),
+		requiresGrad: true,
+	}
+
+	f := NewTensorSumTo(x, []int{2, 2, 3}, []int{2, 1, 1})
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.Equal(t, 2, y.Rows())
+	assert.Equal(t, 1, y.Columns())
+	assert.Equal(t, []T{21, 57}, mat.Data[T](y))
+
+	f.Backward(mat.NewDense(2, 1, []T{1, -1}))
+	assert.Equal(t, 4, x.grad.Rows())
+	assert.Equal(t, 3, x.grad.Columns())
+	assert.Equal(t, []T{1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1, -1}, mat.Data[T](x.grad))
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// TensorPermute is a Function which permutes the dimensions of an operand,
+// viewed as a tensor with the given shape (see mat.Tensor.Permute).
+//
+// The output is the matrix representing the permuted tensor
+// (see mat.Tensor.Matrix).
+type TensorPermute[O Operand] struct {
+	x     O
+	shape []int
+	axes  []int
+}
+
+// NewTensorPermute returns a new TensorPermute Function.
+func NewTensorPermute[O Operand](x O, shape []int, axes []int) *TensorPermute[O] {
+	return &TensorPermute[O]{
+		x:     x,
+		shape: append([]int{}, shape...),
+		axes:  append([]int{}, axes...),
+	}
+}
+
+// Operands returns the list of operands.
+func (r *TensorPermute[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *TensorPermute[O]) Forward() mat.Matrix {
+	return mat.TensorView(r.x.Value(), r.shape...).Permute(r.axes...).Matrix()
+}
+
+// Backward computes the backward pass.
+func (r *TensorPermute[O]) Backward(gy mat.Matrix) {
+	if !r.x.RequiresGrad() {
+		return
+	}
+	gx := mat.TensorView(gy, r.permutedShape()...).Permute(r.inverseAxes()...).Matrix()
+	defer mat.ReleaseMatrix(gx)
+	r.x.AccGrad(gx.ReshapeInPlace(r.x.Value().Dims()))
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *TensorPermute[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	gx := g.NewOperator(NewTensorPermute(gy, r.permutedShape(), r.inverseAxes()))
+	return []O{reshapeGraph(g, gx, r.x)}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *TensorPermute[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return mat.TensorView(t, r.shape...).Permute(r.axes...).Matrix()
+	})
+}
+
+func (r *TensorPermute[O]) permutedShape() []int {
+	shape := make([]int, len(r.axes))
+	for i, a := range r.axes {
+		shape[i] = r.shape[a]
+	}
+	return shape
+}
+
+func (r *TensorPermute[O]) inverseAxes() []int {
+	inv := make([]int, len(r.axes))
+	for i, a := range r.axes {
+		inv[a] = i
+	}
+	return inv
+}
+
+// reshapeGraph returns the node gx reshaped to the dimensions of x. If the
+// dimensions are the same, gx itself is returned.
+func reshapeGraph[O Operand](g Graph[O], gx, x O) O {
+	xv := x.Value()
+	if mat.SameDims(gx.Value(), xv) {
+		return gx
+	}
+	return g.NewOperator(NewReshape(gx, xv.Rows(), xv.Columns()))
+}
@@ -0,0 +1,38 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTensorPermute_Forward(t *testing.T) {
+	t.Run("float32", testTensorPermuteForward[float32])
+	t.Run("float64", testTensorPermuteForward[float64])
+}
+
+func testTensorPermuteForward[T float.DType](t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]T{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}),
+		requiresGrad: true,
+	}
+
+	f := NewTensorPermute(x, []int{2, 3, 2}, []int{2, 0, 1})
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.Equal(t, 4, y.Rows())
+	assert.Equal(t, 3, y.Columns())
+	assert.Equal(t, []T{1, 3, 5, 7, 9, 11, 2, 4, 6, 8, 10, 12}, mat.Data[T](y))
+
+	f.Backward(mat.NewDense(4, 3, []T{1, 3, 5, 7, 9, 11, 2, 4, 6, 8, 10, 12}))
+	assert.Equal(t, 12, x.grad.Rows())
+	assert.Equal(t, 1, x.grad.Columns())
+	assert.Equal(t, []T{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, mat.Data[T](x.grad))
+}
@@ -41,3 +41,16 @@ func (r *Transpose[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Transpose[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	return []O{g.NewOperator(NewTranspose(gy))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Transpose[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], mat.Matrix.T)
+}
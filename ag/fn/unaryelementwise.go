@@ -13,6 +13,10 @@ type UnaryElementwise[O Operand] struct {
 	x  O
 	f  func(i, j int, v float64) float64 // function
 	df func(i, j int, v float64) float64 // derivative
+	// dfGraph expresses the derivative as a node of the graph g, for
+	// computing higher-order derivatives. It can be nil when the
+	// derivative is piecewise constant, in which case df is used.
+	dfGraph func(g Graph[O], x O) O
 }
 
 // Operands returns the list of operands.
@@ -37,3 +41,24 @@ func (r *UnaryElementwise[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *UnaryElementwise[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	var df O
+	if r.dfGraph != nil {
+		df = r.dfGraph(g, r.x)
+	} else {
+		df = g.NewConstant(r.x.Value().Apply(r.df))
+	}
+	return []O{g.NewOperator(NewProd(df, gy))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *UnaryElementwise[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return r.x.Value().Apply(r.df).ProdInPlace(t)
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// VarAxis is a Function which computes the (biased) variances of the values
+// of a matrix along the given axis (see SumAxis).
+//
+//	y = mean((x - mean(x))²)
+type VarAxis[O Operand] struct {
+	x    O
+	axis int
+	dev  mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewVarAxis returns a new VarAxis Function.
+func NewVarAxis[O Operand](x O, axis int) *VarAxis[O] {
+	checkDim(axis)
+	return &VarAxis[O]{
+		x:    x,
+		axis: axis,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *VarAxis[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *VarAxis[O]) Forward() mat.Matrix {
+	x := r.x.Value()
+	r.dev = deviations(x, r.axis)
+	return x.VarAxis(r.axis)
+}
+
+// Backward computes the backward pass.
+func (r *VarAxis[O]) Backward(gy mat.Matrix) {
+	x := r.x.Value()
+	checkAxisGrad(x, gy, r.axis)
+	if r.x.RequiresGrad() {
+		// gx = gy * 2 * (x - mean(x)) / n
+		gx := r.dev.Prod(gy)
+		defer mat.ReleaseMatrix(gx)
+		gx.ProdScalarInPlace(2 / float64(axisSize(x, r.axis)))
+		r.x.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *VarAxis[O]) BackwardGraph(g Graph[O], gy O) []O {
+	if !r.x.RequiresGrad() {
+		return make([]O, 1)
+	}
+	dev := deviationsGraph(g, r.x, r.axis)
+	c := newScalarConstant(g, r.x, 2/float64(axisSize(r.x.Value(), r.axis)))
+	return []O{g.NewOperator(NewProdScalar(g.NewOperator(NewProd(dev, gy)), c))}
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *VarAxis[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	return mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		dt := r.dev.Prod(t)
+		defer mat.ReleaseMatrix(dt)
+		return dt.SumAxis(r.axis).ProdScalarInPlace(2 / float64(axisSize(t, r.axis)))
+	})
+}
+
+// deviations returns a new matrix with the deviations of the values of x
+// from their means along the given axis.
+func deviations(x mat.Matrix, axis int) mat.Matrix {
+	mean := x.MeanAxis(axis)
+	defer mat.ReleaseMatrix(mean)
+	return x.Sub(mean)
+}
+
+// deviationsGraph returns a new node of g with the deviations of the values
+// of x from their means along the given axis.
+func deviationsGraph[O Operand](g Graph[O], x O, axis int) O {
+	return g.NewOperator(NewSub(x, g.NewOperator(NewMeanAxis(x, axis))))
+}
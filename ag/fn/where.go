@@ -0,0 +1,133 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// Where is a Function which selects the elements of x1 where the condition
+// is true, and the elements of x2 elsewhere.
+//
+// The condition is a mask matrix, with the same dimensions of the operands,
+// whose non-zero elements are true (see mat.NewMaskDense). The gradients
+// only flow to the selected elements of each operand.
+type Where[O Operand] struct {
+	cond []bool
+	rows int // the rows of the condition
+	cols int // the columns of the condition
+	x1   O
+	x2   O
+}
+
+// NewWhere returns a new Where Function.
+func NewWhere[O Operand](cond mat.Matrix, x1, x2 O) *Where[O] {
+	return &Where[O]{
+		cond: maskValues(cond),
+		rows: cond.Rows(),
+		cols: cond.Columns(),
+		x1:   x1,
+		x2:   x2,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *Where[O]) Operands() []O {
+	return []O{r.x1, r.x2}
+}
+
+// Forward computes the output of the function.
+func (r *Where[O]) Forward() mat.Matrix {
+	x1v, x2v := r.x1.Value(), r.x2.Value()
+	if x1v.Rows() != r.rows || x1v.Columns() != r.cols || !mat.SameDims(x1v, x2v) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	// FIXME: avoid casting to specific type
+	x1Data := x1v.Data().F64()
+	x2Data := x2v.Data().F64()
+	yData := make([]float64, len(r.cond))
+	for i, c := range r.cond {
+		if c {
+			yData[i] = x1Data[i]
+		} else {
+			yData[i] = x2Data[i]
+		}
+	}
+	return x1v.NewMatrix(x1v.Rows(), x1v.Columns(), float.SliceInterface(yData))
+}
+
+// Backward computes the backward pass.
+func (r *Where[O]) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x1.Value(), gy) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if r.x1.RequiresGrad() {
+		gx := maskData(gy, r.cond, true)
+		defer mat.ReleaseMatrix(gx)
+		r.x1.AccGrad(gx)
+	}
+	if r.x2.RequiresGrad() {
+		gx := maskData(gy, r.cond, false)
+		defer mat.ReleaseMatrix(gx)
+		r.x2.AccGrad(gx)
+	}
+}
+
+// BackwardGraph computes the backward pass as new nodes of the graph g.
+func (r *Where[O]) BackwardGraph(g Graph[O], gy O) []O {
+	gxs := make([]O, 2)
+	if r.x1.RequiresGrad() {
+		gxs[0] = g.NewOperator(NewProd(gy, newBoolMaskConstant(g, gy, r.cond, true)))
+	}
+	if r.x2.RequiresGrad() {
+		gxs[1] = g.NewOperator(NewProd(gy, newBoolMaskConstant(g, gy, r.cond, false)))
+	}
+	return gxs
+}
+
+// JVP computes the Jacobian-vector product, given the tangents of the operands.
+func (r *Where[O]) JVP(tangents []mat.Matrix) mat.Matrix {
+	t1 := mapTangent(tangents[0], func(t mat.Matrix) mat.Matrix {
+		return maskData(t, r.cond, true)
+	})
+	t2 := mapTangent(tangents[1], func(t mat.Matrix) mat.Matrix {
+		return maskData(t, r.cond, false)
+	})
+	return sumTangents(t1, t2)
+}
+
+// maskValues returns the elements of the mask matrix m, in row-major order,
+// as booleans which are true for the non-zero elements.
+func maskValues(m mat.Matrix) []bool {
+	// FIXME: avoid casting to specific type
+	data := m.Data().F64()
+	values := make([]bool, len(data))
+	for i, v := range data {
+		values[i] = v != 0
+	}
+	return values
+}
+
+// maskData returns a new matrix with the elements of x where the mask is
+// equal to keep, and zeros elsewhere.
+func maskData(x mat.Matrix, mask []bool, keep bool) mat.Matrix {
+	cols := x.Columns()
+	return x.Apply(func(i, j int, v float64) float64 {
+		if mask[i*cols+j] == keep {
+			return v
+		}
+		return 0
+	})
+}
+
+// newBoolMaskConstant returns a new constant node of g, with the same shape
+// of the value of x, holding 1 where the mask is equal to value, and 0
+// elsewhere.
+func newBoolMaskConstant[O Operand](g Graph[O], x O, mask []bool, value bool) O {
+	ones := x.Value().OnesLike()
+	defer mat.ReleaseMatrix(ones)
+	return g.NewConstant(maskData(ones, mask, value))
+}
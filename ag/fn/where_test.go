@@ -0,0 +1,44 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhere_Forward(t *testing.T) {
+	t.Run("float32", testWhereForward[float32])
+	t.Run("float64", testWhereForward[float64])
+}
+
+func testWhereForward[T float.DType](t *testing.T) {
+	cond := mat.NewMaskDense[T](2, 3, []bool{true, false, true, false, false, true})
+	x1 := &variable{
+		value:        mat.NewDense(2, 3, []T{0.1, 0.2, 0.3, 0.4, 0.5, 0.6}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	x2 := &variable{
+		value:        mat.NewDense(2, 3, []T{-1, -2, -3, -4, -5, -6}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewWhere(cond, x1, x2)
+	assert.Equal(t, []*variable{x1, x2}, f.Operands())
+
+	y := f.Forward()
+	assert.InDeltaSlice(t, []T{0.1, -2, 0.3, -4, -5, 0.6}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(2, 3, []T{1, 2, 3, 4, 5, 6}))
+	assert.InDeltaSlice(t, []T{1, 0, 3, 0, 0, 6}, x1.grad.Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{0, 2, 0, 4, 5, 0}, x2.grad.Data(), 1.0e-6)
+
+	assert.Panics(t, func() { NewWhere(mat.NewEmptyDense[T](3, 2), x1, x2).Forward() })
+}
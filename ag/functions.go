@@ -181,11 +181,6 @@ func PositiveELU(x Node) Node {
 	return AddScalar(ELU(x, one), one)
 }
 
-// LogSoftmax returns a new operator node as a result of Log(Softmax(x)).
-func LogSoftmax(x Node) Node {
-	return Log(Softmax(x))
-}
-
 // LogSumExp "trick" computes the log of the sum of exponentials of input elements.
 // When the input is one, this must be a vector. Alternatively, the calculation
 // is conducted on a list of scalars.
@@ -0,0 +1,228 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Grad computes the gradients of the outputs with respect to the inputs,
+// and returns them as new matrices, one for each input.
+//
+// Unlike Backward and BackwardMany, the gradients are not accumulated into
+// the nodes: the state of Variables, Operators and parameters involved in
+// the computation is left untouched, so no zeroing is required afterwards.
+//
+// The output gradients are optional. If outputGrads is nil, or one of its
+// elements is nil, the gradients of the corresponding output are set to a
+// matrix of ones (dy/dy = 1). Otherwise, it must have the same length of
+// outputs.
+//
+// The inputs can be any node of the graph, including intermediate operators.
+// A nil matrix is returned for an input which does not require gradients or
+// which the outputs do not depend on.
+//
+// The gradients are computed by the same backward step of Backward, so that
+// any GradHook registered on the operators is involved. The gradients of
+// the leaves of the graph (e.g. Variables and parameters) are instead
+// collected for this call only, so that the leaves can take part in other
+// backward steps at the same time, and their hooks are not called. To this
+// end, the operators with leaves requiring gradients among their operands
+// compute the gradients of the operands without accumulating them, either
+// with fn.Custom.OperandGrads or with fn.GraphFunction.BackwardGraph: Grad
+// panics if their functions support neither. Use BackwardGraph for
+// higher-order gradients.
+func Grad(outputs []Node, outputGrads []mat.Matrix, inputs []Node) []mat.Matrix {
+	if outputGrads != nil && len(outputGrads) != len(outputs) {
+		panic("ag: the number of output gradients must match the number of outputs")
+	}
+
+	ops := topologicalOrder(outputs)
+	c := newGradCapture()
+	defer c.release()
+
+	// The gradients accumulated by a previous backward step are put aside,
+	// and restored at the end.
+	prevGrads := make([]mat.Matrix, len(ops))
+	for i, op := range ops {
+		op.Grad() // safety wait for any backward goroutine to finish
+		prevGrads[i], op.grad = op.grad, nil
+		op.gradCapture = c
+	}
+
+	var roots []*Operator
+	var rootGrads []mat.Matrix
+	for i, y := range outputs {
+		if !y.RequiresGrad() {
+			continue
+		}
+		var gy mat.Matrix
+		if outputGrads != nil && outputGrads[i] != nil {
+			gy = outputGrads[i]
+		} else {
+			gy = y.Value().OnesLike()
+			defer mat.ReleaseMatrix(gy)
+		}
+		if op, ok := y.(*Operator); ok {
+			roots = append(roots, op)
+			rootGrads = append(rootGrads, gy)
+			continue
+		}
+		c.accGrad(y, gy)
+	}
+	backwardFrom(nil, -1, roots, rootGrads)
+
+	gxs := make([]mat.Matrix, len(inputs))
+	for i, x := range inputs {
+		if g := c.grad(x); g != nil {
+			gxs[i] = g.Clone()
+		}
+	}
+
+	for i, op := range ops {
+		op.ZeroGrad()
+		op.grad = prevGrads[i]
+		op.gradCapture = nil
+	}
+	return gxs
+}
+
+// gradCapture collects the gradients of the leaves of a graph during a
+// single call to Grad, in place of accumulating them into the leaves.
+//
+// A nil *gradCapture is valid, and simply accumulates the gradients into
+// the nodes.
+type gradCapture struct {
+	mu    sync.Mutex
+	grads map[Node]mat.Matrix
+}
+
+func newGradCapture() *gradCapture {
+	return &gradCapture{grads: make(map[Node]mat.Matrix)}
+}
+
+// accGrad accumulates the gradients of the node x. The operators accumulate
+// them as usual, even if nil, while the ones of the leaves are collected.
+func (c *gradCapture) accGrad(x Node, grad mat.Matrix) {
+	if _, ok := x.(*Operator); ok || c == nil {
+		x.AccGrad(grad)
+		return
+	}
+	if grad == nil || !x.RequiresGrad() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if g, ok := c.grads[x]; ok {
+		g.AddInPlace(grad)
+		return
+	}
+	c.grads[x] = grad.Clone()
+}
+
+// grad returns the gradients of the node x, as accumulated by accGrad.
+func (c *gradCapture) grad(x Node) mat.Matrix {
+	if _, ok := x.(*Operator); ok || c == nil {
+		return x.Grad()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.grads[x]
+}
+
+// release frees the collected gradients.
+func (c *gradCapture) release() {
+	for x, g := range c.grads {
+		mat.ReleaseMatrix(g)
+		delete(c.grads, x)
+	}
+}
+
+// backward performs the backward step of the operator op, given its
+// gradients gy. If some leaves requiring gradients are among the operands,
+// the gradients of all the operands are computed by operandGrads, and
+// accumulated with accGrad.
+func (c *gradCapture) backward(op *Operator, gy mat.Matrix) {
+	if !hasLeafRequiringGrad(op.Operands()) {
+		op.function.Backward(gy)
+		return
+	}
+	gxs, release := operandGrads(op, gy)
+	defer release()
+	for i, x := range op.Operands() {
+		if x.RequiresGrad() {
+			c.accGrad(x, gxs[i])
+		}
+	}
+}
+
+// hasLeafRequiringGrad reports whether any of the nodes, other than the
+// operators, requires gradients.
+func hasLeafRequiringGrad(xs []Node) bool {
+	for _, x := range xs {
+		if _, ok := x.(*Operator); !ok && x.RequiresGrad() {
+			return true
+		}
+	}
+	return false
+}
+
+// operandGradsFunction is implemented by the functions which can compute the
+// gradients of their operands without accumulating them (e.g. fn.Custom).
+type operandGradsFunction interface {
+	OperandGrads(gy mat.Matrix) []mat.Matrix
+}
+
+// operandGrads computes the gradients of the operands of op, given its
+// gradients gy, without accumulating them into the operands.
+// The function of op must implement either operandGradsFunction or
+// fn.GraphFunction, whose backward graph is evaluated eagerly.
+//
+// It also returns a function releasing the computed gradients.
+func operandGrads(op *Operator, gy mat.Matrix) ([]mat.Matrix, func()) {
+	switch f := op.function.(type) {
+	case operandGradsFunction:
+		return f.OperandGrads(gy), func() {}
+	case fn.GraphFunction[Node]:
+		gxNodes := f.BackwardGraph(resultBuilder{}, Var(gy))
+		gxs := make([]mat.Matrix, len(gxNodes))
+		for i, gx := range gxNodes {
+			if gx != nil {
+				gxs[i] = gx.Value()
+			}
+		}
+		return gxs, func() {
+			released := make(map[*Result]struct{}, len(gxNodes))
+			for _, gx := range gxNodes {
+				if r, ok := gx.(*Result); ok {
+					if _, ok := released[r]; !ok {
+						released[r] = struct{}{}
+						r.release()
+					}
+				}
+			}
+		}
+	default:
+		panic(fmt.Sprintf("ag: function %s does not support the computation of the gradients of the operands", op.Name()))
+	}
+}
+
+// resultBuilder satisfies fn.Graph, evaluating the nodes of the backward
+// pass of a function eagerly, as Results.
+type resultBuilder struct{}
+
+// NewOperator computes the value of the function, returning a Result.
+func (resultBuilder) NewOperator(f fn.Function[Node]) Node {
+	return newResult(f, f.Operands())
+}
+
+// NewConstant creates a new Variable which does not require gradients.
+func (resultBuilder) NewConstant(value mat.Matrix) Node {
+	return Var(value)
+}
@@ -0,0 +1,166 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrad(t *testing.T) {
+	t.Run("float32", testGrad[float32])
+	t.Run("float64", testGrad[float64])
+}
+
+func testGrad[T float.DType](t *testing.T) {
+	t.Run("inputs and intermediate nodes", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2, 3})).WithGrad(true)
+		w := Var(mat.NewVecDense([]T{0.5, -1, 2})).WithGrad(true)
+		h := Prod(x, w)
+		y := ReduceSum(Square(h))
+
+		gs := Grad([]Node{y}, nil, []Node{x, h})
+		require.Len(t, gs, 2)
+		// dy/dh = 2h; dy/dx = 2h * w
+		assert.InDeltaSlice(t, []T{0.5, 4, 24}, gs[0].Data(), 1.0e-6)
+		assert.InDeltaSlice(t, []T{1, -4, 12}, gs[1].Data(), 1.0e-6)
+
+		assert.Nil(t, x.Grad())
+		assert.Nil(t, w.Grad())
+		assert.Nil(t, h.Grad())
+		assert.Nil(t, y.Grad())
+	})
+
+	t.Run("multiple outputs with output gradients", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+		y1 := Square(x)
+		y2 := ProdScalar(x, Scalar[T](3))
+
+		gs := Grad(
+			[]Node{y1, y2},
+			[]mat.Matrix{mat.NewVecDense([]T{1, -1}), nil},
+			[]Node{x},
+		)
+		require.Len(t, gs, 1)
+		assert.InDeltaSlice(t, []T{5, -1}, gs[0].Data(), 1.0e-6)
+		assert.Nil(t, x.Grad())
+	})
+
+	t.Run("unreachable inputs", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+		z := Var(mat.NewVecDense([]T{3, 4})).WithGrad(true)
+		c := Var(mat.NewVecDense([]T{5, 6}))
+		y := Dot(x, c)
+
+		gs := Grad([]Node{y}, nil, []Node{z, c, x})
+		require.Len(t, gs, 3)
+		assert.Nil(t, gs[0])
+		assert.Nil(t, gs[1])
+		assert.InDeltaSlice(t, []T{5, 6}, gs[2].Data(), 1.0e-6)
+	})
+
+	t.Run("consistency with Backward", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{0.1, -0.2, 0.3})).WithGrad(true)
+		w := Var(mat.NewDense(2, 3, []T{0.4, 0.5, -0.6, 0.7, -0.8, 0.9})).WithGrad(true)
+		y := ReduceSum(Tanh(Mul(w, x)))
+
+		gs := Grad([]Node{y}, nil, []Node{x, w})
+
+		Backward(y)
+		assert.InDeltaSlice(t, x.Grad().Data(), gs[0].Data(), 1.0e-6)
+		assert.InDeltaSlice(t, w.Grad().Data(), gs[1].Data(), 1.0e-6)
+	})
+
+	t.Run("the previous gradients are left untouched", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+		w := Var(mat.NewVecDense([]T{3, -1})).WithGrad(true)
+		h := Prod(x, w)
+		y := ReduceSum(h)
+		Backward(y)
+
+		gs := Grad([]Node{y}, []mat.Matrix{mat.NewScalar[T](2)}, []Node{x, h})
+		assert.InDeltaSlice(t, []T{6, -2}, gs[0].Data(), 1.0e-6)
+		assert.InDeltaSlice(t, []T{2, 2}, gs[1].Data(), 1.0e-6)
+
+		assert.InDeltaSlice(t, []T{3, -1}, x.Grad().Data(), 1.0e-6)
+		assert.InDeltaSlice(t, []T{1, 2}, w.Grad().Data(), 1.0e-6)
+		assert.InDeltaSlice(t, []T{1, 1}, h.Grad().Data(), 1.0e-6)
+	})
+
+	t.Run("custom functions and gradient hooks", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, -2})).WithGrad(true)
+		double := func(xs []mat.Matrix) mat.Matrix {
+			return xs[0].ProdScalar(2)
+		}
+		doubleGrad := func(_ []mat.Matrix, _, gy mat.Matrix) []mat.Matrix {
+			return []mat.Matrix{gy.ProdScalar(2)}
+		}
+		h := Custom("double", double, doubleGrad, x)
+		RegisterGradHook(h, func(grad mat.Matrix) mat.Matrix {
+			return grad.ProdScalar(-1)
+		})
+		y := ReduceSum(Square(h))
+
+		gs := Grad([]Node{y}, nil, []Node{x})
+		// dy/dx = -2h * 2, with h = 2x
+		assert.InDeltaSlice(t, []T{-8, 16}, gs[0].Data(), 1.0e-6)
+		assert.Nil(t, x.Grad())
+	})
+
+	t.Run("the gradients are never accumulated into the leaves", func(t *testing.T) {
+		x := &dummyNode{value: mat.NewVecDense([]T{1, 2}), requiresGrad: true} // AccGrad panics
+		w := Var(mat.NewVecDense([]T{3, -1})).WithGrad(true)
+		RegisterGradHook(w, func(mat.Matrix) mat.Matrix {
+			panic("unexpected call")
+		})
+		y := ReduceSum(Add(Prod(x, w), Exp(x)))
+
+		gs := Grad([]Node{y}, nil, []Node{x, w})
+		require.Len(t, gs, 2)
+		assert.InDeltaSlice(t, []T{5.718282, 6.389056}, gs[0].Data(), 1.0e-5)
+		assert.InDeltaSlice(t, []T{1, 2}, gs[1].Data(), 1.0e-6)
+		assert.Nil(t, w.Grad())
+	})
+
+	t.Run("concurrent backward steps on the same leaves", func(t *testing.T) {
+		w := Var(mat.NewVecDense([]T{0.5, -1, 2})).WithGrad(true)
+		const n = 50
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				x := Var(mat.NewVecDense([]T{1, 2, 3}))
+				Backward(ReduceSum(Prod(w, x)))
+			}
+		}()
+		gs := make([]mat.Matrix, n)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				gs[i] = Grad([]Node{ReduceSum(Square(w))}, nil, []Node{w})[0]
+			}
+		}()
+		wg.Wait()
+
+		assert.InDeltaSlice(t, []T{n, 2 * n, 3 * n}, w.Grad().Data(), 1.0e-6)
+		for _, g := range gs {
+			assert.InDeltaSlice(t, []T{1, -2, 4}, g.Data(), 1.0e-6)
+		}
+	})
+
+	t.Run("it panics with mismatching output gradients", func(t *testing.T) {
+		x := Var(mat.NewScalar[T](1)).WithGrad(true)
+		assert.Panics(t, func() {
+			Grad([]Node{x}, []mat.Matrix{nil, nil}, []Node{x})
+		})
+	})
+}
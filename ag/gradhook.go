@@ -0,0 +1,139 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// GradHook is a function called with the gradients which are being
+// accumulated into a node (see Node.AccGrad), returning the gradients to
+// accumulate in their place.
+//
+// A hook can return grad unchanged, e.g. for logging purposes, or a new
+// matrix with the same shape, e.g. to scale, clip or mask the gradients.
+// If it returns nil, the gradients are discarded. It must not modify grad
+// in place, since the matrix is owned by the caller.
+type GradHook func(grad mat.Matrix) mat.Matrix
+
+// RemoveGradHookFunc is returned when a GradHook is registered, and removes
+// it from the node.
+type RemoveGradHookFunc func()
+
+// GradHookRegistry is implemented by nodes supporting gradient hooks.
+type GradHookRegistry interface {
+	// RegisterGradHook registers a GradHook on the node.
+	RegisterGradHook(hook GradHook) RemoveGradHookFunc
+}
+
+// RegisterGradHook registers a GradHook on the node x, which is called each
+// time some gradients are accumulated into x during the backward step.
+//
+// The hooks apply to the gradients accumulated with Node.AccGrad, that is to
+// the ones computed by Backward and BackwardT. Grad only calls the hooks of
+// the operators, since it does not accumulate the gradients into the leaves.
+// BackwardGraph calls the hooks of the nodes implementing
+// GradNodeAccumulator, with the values of their gradients (see
+// GradHooks.ApplyNode).
+//
+// It panics if x does not implement GradHookRegistry.
+func RegisterGradHook(x Node, hook GradHook) RemoveGradHookFunc {
+	r, ok := x.(GradHookRegistry)
+	if !ok {
+		panic(fmt.Sprintf("ag: gradient hooks are not supported by %T", x))
+	}
+	return r.RegisterGradHook(hook)
+}
+
+// GradHooks is a list of GradHook, safe for concurrent use.
+// The zero value is an empty list ready to use.
+//
+// It is intended to be embedded into the implementations of
+// GradHookRegistry.
+type GradHooks struct {
+	mu     sync.RWMutex
+	hooks  []gradHookEntry
+	nextID uint64
+}
+
+type gradHookEntry struct {
+	id   uint64
+	hook GradHook
+}
+
+// Register appends a hook to the list.
+// It panics if the hook is nil.
+func (h *GradHooks) Register(hook GradHook) RemoveGradHookFunc {
+	if hook == nil {
+		panic("ag: the gradient hook cannot be nil")
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	id := h.nextID
+	h.nextID++
+	h.hooks = append(h.hooks, gradHookEntry{id: id, hook: hook})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { h.remove(id) })
+	}
+}
+
+func (h *GradHooks) remove(id uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, e := range h.hooks {
+		if e.id == id {
+			h.hooks = append(h.hooks[:i:i], h.hooks[i+1:]...)
+			return
+		}
+	}
+}
+
+// Apply calls each hook, in order of registration, passing the gradients
+// returned by the previous one. It returns the final gradients, or nil if
+// they are discarded by a hook.
+func (h *GradHooks) Apply(grad mat.Matrix) mat.Matrix {
+	h.mu.RLock()
+	hooks := h.hooks
+	h.mu.RUnlock()
+
+	for _, e := range hooks {
+		if grad == nil {
+			return nil
+		}
+		grad = e.hook(grad)
+	}
+	return grad
+}
+
+// ApplyNode calls the hooks with the value of the gradients gx, expressed as
+// a Node (see GradNodeAccumulator).
+//
+// It returns gx itself if the hooks return its value unchanged, or nil if
+// they discard the gradients. If they replace the gradients, it returns a
+// new Variable holding the new gradients, which does not require gradients:
+// the replaced gradients can not be differentiated any further.
+func (h *GradHooks) ApplyNode(gx Node) Node {
+	h.mu.RLock()
+	empty := len(h.hooks) == 0
+	h.mu.RUnlock()
+	if empty {
+		return gx
+	}
+
+	value := gx.Value()
+	switch grad := h.Apply(value); grad {
+	case nil:
+		return nil
+	case value:
+		return gx
+	default:
+		return Var(grad)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGradHooks(t *testing.T) {
+	t.Run("float32", testGradHooks[float32])
+	t.Run("float64", testGradHooks[float64])
+}
+
+func testGradHooks[T float.DType](t *testing.T) {
+	t.Run("Variable", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2, 3})).WithGrad(true)
+
+		var observed []T
+		remove := RegisterGradHook(x, func(grad mat.Matrix) mat.Matrix {
+			observed = append(observed, float.SliceValueOf[T](grad.Data())...)
+			return grad
+		})
+		RegisterGradHook(x, func(grad mat.Matrix) mat.Matrix {
+			return grad.ProdScalar(2)
+		})
+
+		c := Var(mat.NewVecDense([]T{2, 4, 6}))
+		Backward(ReduceSum(Prod(x, c)))
+		assert.InDeltaSlice(t, []T{2, 4, 6}, observed, 1.0e-6)
+		assert.InDeltaSlice(t, []T{4, 8, 12}, x.Grad().Data(), 1.0e-6)
+
+		x.ZeroGrad()
+		remove()
+		remove() // it must be safe to call it again
+		observed = nil
+		Backward(ReduceSum(Prod(x, c)))
+		assert.Nil(t, observed)
+		assert.InDeltaSlice(t, []T{4, 8, 12}, x.Grad().Data(), 1.0e-6)
+	})
+
+	t.Run("gradients discarded by the hook", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+		RegisterGradHook(x, func(mat.Matrix) mat.Matrix { return nil })
+		Backward(ReduceSum(Square(x)))
+		assert.Nil(t, x.Grad())
+	})
+
+	t.Run("BackwardGraph", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+		var observed []T
+		RegisterGradHook(x, func(grad mat.Matrix) mat.Matrix {
+			observed = append(observed, float.SliceValueOf[T](grad.Data())...)
+			return grad
+		})
+		BackwardGraph(ReduceSum(Pow(x, 3)))
+		assert.InDeltaSlice(t, []T{3, 12}, observed, 1.0e-6)
+		gx := GradNode(x)
+		require.NotNil(t, gx)
+		assert.True(t, gx.RequiresGrad(), "the gradients returned unchanged are still differentiable")
+
+		x.ZeroGrad()
+		remove := RegisterGradHook(x, func(grad mat.Matrix) mat.Matrix {
+			return grad.ProdScalar(2)
+		})
+		BackwardGraph(ReduceSum(Pow(x, 3)))
+		assert.InDeltaSlice(t, []T{6, 24}, GradNode(x).Value().Data(), 1.0e-6)
+		assert.InDeltaSlice(t, []T{6, 24}, x.Grad().Data(), 1.0e-6)
+		assert.False(t, GradNode(x).RequiresGrad())
+
+		x.ZeroGrad()
+		remove()
+		RegisterGradHook(x, func(mat.Matrix) mat.Matrix { return nil })
+		BackwardGraph(ReduceSum(Pow(x, 3)))
+		assert.Nil(t, GradNode(x))
+		assert.Nil(t, x.Grad())
+	})
+
+	t.Run("Operator as gradient reversal", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+		h := Square(x)
+		RegisterGradHook(h, func(grad mat.Matrix) mat.Matrix {
+			return grad.ProdScalar(-0.5)
+		})
+		y := ReduceSum(Add(h, x))
+		Backward(y)
+
+		// dy/dx = -0.5 * 2x + 1
+		assert.InDeltaSlice(t, []T{0, -1}, x.Grad().Data(), 1.0e-6)
+		assert.InDeltaSlice(t, []T{-0.5, -0.5}, h.Grad().Data(), 1.0e-6)
+	})
+
+	t.Run("Operator with all gradients discarded", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+		h := Tanh(x)
+		RegisterGradHook(h, func(mat.Matrix) mat.Matrix { return nil })
+		Backward(ReduceSum(Add(Exp(h), h)))
+		assert.Nil(t, h.Grad())
+		assert.Nil(t, x.Grad())
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		y := Exp(Var(mat.NewScalar[T](1)).WithInference(true))
+		require.IsType(t, &Result{}, y)
+		assert.Panics(t, func() {
+			RegisterGradHook(y, func(grad mat.Matrix) mat.Matrix { return grad })
+		})
+	})
+
+	t.Run("nil hook", func(t *testing.T) {
+		x := Var(mat.NewScalar[T](1)).WithGrad(true)
+		assert.Panics(t, func() { RegisterGradHook(x, nil) })
+	})
+}
@@ -0,0 +1,218 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+)
+
+var (
+	_ fn.Operand = &Result{}
+	_ Node       = &Result{}
+)
+
+// inferenceMode is set by SetInferenceMode (1 = enabled, 0 = disabled).
+var inferenceMode int32
+
+// SetInferenceMode enables or disables the inference mode globally.
+//
+// In inference mode, NewOperator computes the value of the function
+// eagerly, on the calling goroutine, and returns a Result, which holds
+// nothing else than the value. Gradients can not be propagated through
+// the resulting nodes, even if some operands require them.
+//
+// It is intended for serving a trained model: with no operators to keep
+// track of, memory and latency are reduced, and the values of the
+// intermediate nodes can be released into the matrices pool as soon as
+// they are no longer needed (see InferenceScope).
+//
+// Since the global mode affects every goroutine, NoGrad should be
+// preferred when the same process is also training.
+func SetInferenceMode(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&inferenceMode, v)
+}
+
+// InferenceMode reports whether the inference mode is enabled globally
+// (see SetInferenceMode).
+func InferenceMode() bool {
+	return atomic.LoadInt32(&inferenceMode) == 1
+}
+
+// NoGrad returns new Variables holding the values of xs, which enable the
+// inference mode (see SetInferenceMode) for the graph depending on them,
+// regardless of the global mode.
+//
+// The inference mode is inherited by every operator having at least one
+// operand in inference mode, that is a Result or a Variable set with
+// Variable.WithInference, so it can be enabled for the computations of a
+// single goroutine, while others are training.
+func NoGrad(xs ...Node) []Node {
+	ys := make([]Node, len(xs))
+	for i, x := range xs {
+		ys[i] = Var(x.Value()).WithInference(true)
+	}
+	return ys
+}
+
+// InferenceScope tracks the Results computed in inference mode from the
+// Variables it creates (see InferenceScope.NoGrad), so that the values of
+// all of them can be released into the matrices pool at once.
+//
+// Since a Result does not retain its operands, ReleaseGraph only releases
+// the values of the Results it is given, leaving the intermediate ones to
+// the garbage collector: the scope allows them to be recycled instead.
+type InferenceScope struct {
+	mu      sync.Mutex
+	results []*Result
+}
+
+// NewInferenceScope returns a new InferenceScope.
+func NewInferenceScope() *InferenceScope {
+	return &InferenceScope{}
+}
+
+// NoGrad works like the function NoGrad, but the Results depending on the
+// returned Variables, directly or through other Results, are tracked by the
+// scope.
+func (s *InferenceScope) NoGrad(xs ...Node) []Node {
+	ys := NoGrad(xs...)
+	for _, y := range ys {
+		y.(*Variable).scope = s
+	}
+	return ys
+}
+
+// Release releases the values of the Results tracked by the scope, except
+// the ones of the nodes to keep (e.g. the outputs), which are no longer
+// tracked. The released Results must not be used afterwards, while the
+// scope can be reused for new computations.
+func (s *InferenceScope) Release(keep ...Node) {
+	kept := make(map[Node]struct{}, len(keep))
+	for _, n := range keep {
+		kept[n] = struct{}{}
+	}
+
+	s.mu.Lock()
+	results := s.results
+	s.results = nil
+	s.mu.Unlock()
+
+	for _, r := range results {
+		if _, ok := kept[r]; !ok {
+			r.release()
+		}
+	}
+}
+
+// track adds the Result r to the scope.
+func (s *InferenceScope) track(r *Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, r)
+}
+
+// inheritedInferenceScope returns the InferenceScope of the first operand
+// having one, or nil.
+func inheritedInferenceScope(operands []Node) *InferenceScope {
+	for _, operand := range operands {
+		switch o := operand.(type) {
+		case *Result:
+			if o.scope != nil {
+				return o.scope
+			}
+		case *Variable:
+			if o.scope != nil {
+				return o.scope
+			}
+		}
+	}
+	return nil
+}
+
+// inheritedInferenceMode reports whether any of the operands is in
+// inference mode (see NoGrad).
+func inheritedInferenceMode(operands []Node) bool {
+	for _, operand := range operands {
+		switch o := operand.(type) {
+		case *Result:
+			return true
+		case *Variable:
+			if o.inference {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Result is a type of Node holding the value of a function, computed
+// eagerly in inference mode (see SetInferenceMode).
+//
+// It retains neither the function nor its operands, and never requires
+// gradients. It is tracked by the InferenceScope of its operands, if any.
+type Result struct {
+	value mat.Matrix
+	scope *InferenceScope
+}
+
+// newResult computes the value of the function and returns a new Result.
+func newResult(f fn.Function[Node], operands []Node) *Result {
+	r := &Result{
+		value: f.Forward(),
+		scope: inheritedInferenceScope(operands),
+	}
+	if r.scope != nil {
+		r.scope.track(r)
+	}
+	return r
+}
+
+// Value returns the value of the function.
+func (r *Result) Value() mat.Matrix {
+	return r.value
+}
+
+// Grad always returns nil on a Result Node.
+func (r *Result) Grad() mat.Matrix {
+	return nil
+}
+
+// HasGrad always returns false on a Result Node.
+func (r *Result) HasGrad() bool {
+	return false
+}
+
+// RequiresGrad always returns false on a Result Node.
+func (r *Result) RequiresGrad() bool {
+	return false
+}
+
+// AccGrad has no effects on a Result Node.
+func (r *Result) AccGrad(mat.Matrix) {}
+
+// ZeroGrad has no effects on a Result Node.
+func (r *Result) ZeroGrad() {}
+
+// Name always returns an empty string on a Result Node.
+func (r *Result) Name() string {
+	return ""
+}
+
+// release frees the value of the Result.
+func (r *Result) release() {
+	if r.value == nil {
+		return
+	}
+	mat.ReleaseMatrix(r.value)
+	r.value = nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferenceMode(t *testing.T) {
+	t.Run("float32", testInferenceMode[float32])
+	t.Run("float64", testInferenceMode[float64])
+}
+
+func testInferenceMode[T float.DType](t *testing.T) {
+	t.Run("SetInferenceMode", func(t *testing.T) {
+		assert.False(t, InferenceMode())
+		SetInferenceMode(true)
+		assert.True(t, InferenceMode())
+
+		x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+		y := Add(x, x)
+		SetInferenceMode(false)
+		assert.False(t, InferenceMode())
+
+		require.IsType(t, &Result{}, y)
+		assert.InDeltaSlice(t, []T{2, 4}, y.Value().Data(), 1.0e-6)
+		assert.False(t, y.RequiresGrad())
+		assert.Nil(t, y.Grad())
+		assert.False(t, y.HasGrad())
+
+		Backward(y)
+		assert.Nil(t, x.Grad())
+
+		z := Add(x, x)
+		assert.IsType(t, &Operator{}, z)
+	})
+
+	t.Run("NoGrad", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+		w := Var(mat.NewVecDense([]T{3, 4})).WithGrad(true)
+		xs := NoGrad(x)
+		require.Len(t, xs, 1)
+		assert.False(t, InferenceMode())
+
+		y := Prod(xs[0], w)
+		z := Square(y)
+		require.IsType(t, &Result{}, y)
+		require.IsType(t, &Result{}, z)
+		assert.InDeltaSlice(t, []T{3, 8}, y.Value().Data(), 1.0e-6)
+		assert.InDeltaSlice(t, []T{9, 64}, z.Value().Data(), 1.0e-6)
+
+		// the graphs of the other nodes are not affected
+		assert.IsType(t, &Operator{}, Prod(x, w))
+	})
+
+	t.Run("concurrent graphs", func(t *testing.T) {
+		w := Var(mat.NewVecDense([]T{3, 4})).WithGrad(true)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100; i++ {
+				y := Prod(NoGrad(Var(mat.NewVecDense([]T{1, 2})))[0], w)
+				assert.IsType(t, &Result{}, y)
+			}
+		}()
+		for i := 0; i < 100; i++ {
+			y := Prod(Var(mat.NewVecDense([]T{1, 2})), w)
+			assert.IsType(t, &Operator{}, y)
+		}
+		<-done
+	})
+
+	t.Run("ReleaseGraph", func(t *testing.T) {
+		y := Tanh(NoGrad(Var(mat.NewVecDense([]T{1, 2})))[0])
+		ReleaseGraph(y)
+		assert.Nil(t, y.Value())
+		ReleaseGraph(y) // it must be safe to release it again
+	})
+
+	t.Run("InferenceScope", func(t *testing.T) {
+		s := NewInferenceScope()
+		w := Var(mat.NewVecDense([]T{3, 4})).WithGrad(true)
+		xs := s.NoGrad(Var(mat.NewVecDense([]T{1, 2})))
+		h1 := Prod(xs[0], w)
+		h2 := Square(h1)
+		y := Add(h2, h1)
+		require.IsType(t, &Result{}, y)
+		other := Tanh(NoGrad(w)[0]) // not tracked by the scope
+
+		s.Release(y)
+		// the intermediate values are returned to the pool
+		assert.Nil(t, h1.Value())
+		assert.Nil(t, h2.Value())
+		assert.InDeltaSlice(t, []T{12, 72}, y.Value().Data(), 1.0e-6)
+		assert.NotNil(t, other.Value())
+		assert.NotNil(t, xs[0].Value())
+		assert.NotNil(t, w.Value())
+
+		// the kept nodes are no longer tracked
+		z := Exp(xs[0])
+		s.Release()
+		assert.Nil(t, z.Value())
+		assert.NotNil(t, y.Value())
+		ReleaseGraph(y)
+		assert.Nil(t, y.Value())
+	})
+}
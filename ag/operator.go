@@ -5,18 +5,21 @@
 package ag
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/nlpodyssey/spago/ag/fn"
 	"github.com/nlpodyssey/spago/mat"
 )
 
 var (
-	_ fn.Operand = &Operator{}
-	_ Node       = &Operator{}
+	_ fn.Operand       = &Operator{}
+	_ Node             = &Operator{}
+	_ GradHookRegistry = &Operator{}
 )
 
 // Operator is a type of node.
@@ -41,31 +44,66 @@ type Operator struct {
 	createdAt uint64
 	// Function's operands are memoized here after the first request.
 	operands []Node
+	// tangent is the result of the forward-mode differentiation, lazily
+	// computed by the first call to Tangent.
+	tangent     mat.Matrix
+	tangentOnce sync.Once
+	// executor is the Executor inherited from the operands, if any.
+	executor Executor
+	// gradHooks are called by AccGrad (see RegisterGradHook).
+	gradHooks GradHooks
+	// gradCapture collects the gradients of the leaf operands, in place of
+	// accumulating them, while the operator is involved in Grad.
+	gradCapture *gradCapture
 }
 
 // NewOperator creates a new operator along with its forward pass.
+//
+// In inference mode, either global (see SetInferenceMode) or inherited from
+// the operands (see NoGrad), it returns a Result instead.
 func NewOperator(f fn.Function[Node]) Node {
+	operands := f.Operands()
+	if InferenceMode() || inheritedInferenceMode(operands) {
+		return newResult(f, operands)
+	}
 	op := &Operator{
 		requiresGrad:  -1, // lazy evaluation
 		backwardState: idle,
 		function:      f,
 		pendingGrads:  0,
 		createdAt:     atomic.LoadUint64(&tsCounter),
+		operands:      operands,
 	}
 
 	op.cond.L = &op.mx
+	op.executor = inheritedExecutor(operands)
 
-	go op.forward()
+	p := activeProfiler()
+	anomalies := detectAnomalies()
+	goProfiled(op.getExecutor(), op, p, func() { op.forward(p, anomalies) })
+	return op
+}
 
-	if debug {
-		op.Value() // wait for the forward goroutine to finish
+// getExecutor returns the Executor of the operator.
+func (o *Operator) getExecutor() Executor {
+	switch {
+	case debug:
+		return Sequential
+	case o.executor != nil:
+		return o.executor
+	default:
+		return executor
 	}
-	return op
 }
 
 // Name returns the Name of the operator.
-// The name is taken from the name of r.function via reflection.
+// The name is provided by the function itself, if it has a Name method
+// (e.g. fn.Custom), otherwise it is taken from the name of r.function via
+// reflection.
 func (o *Operator) Name() string {
+	if f, ok := o.function.(interface{ Name() string }); ok && f.Name() != "" {
+		return f.Name()
+	}
 	name := reflect.ValueOf(o.function).Elem().Type().Name()
 	// Strip trailing generics, if any: "foo[bar]" becomes "foo".
 	if i := strings.IndexByte(name, '['); i != -1 {
@@ -119,6 +157,37 @@ func (o *Operator) HasGrad() bool {
 	return o.Grad() != nil
 }
 
+// Tangent returns the tangent of the operator, that is the directional
+// derivative of its value computed by forward-mode differentiation, given
+// the tangents of the operands (see Variable.WithTangent).
+//
+// The tangent is computed on the first call and memoized, so the tangents
+// of the operands must be set in advance. It returns nil if none of the
+// operands has a tangent.
+//
+// It panics if the function does not implement fn.JVPFunction.
+func (o *Operator) Tangent() mat.Matrix {
+	o.tangentOnce.Do(func() {
+		operands := o.Operands()
+		tangents := make([]mat.Matrix, len(operands))
+		hasTangents := false
+		for i, operand := range operands {
+			tangents[i] = Tangent(operand)
+			hasTangents = hasTangents || tangents[i] != nil
+		}
+		if !hasTangents {
+			return
+		}
+		f, ok := o.function.(fn.JVPFunction[Node])
+		if !ok {
+			panic(fmt.Sprintf("ag: function %s does not support forward-mode differentiation", o.Name()))
+		}
+		o.Value() // wait for the forward goroutine to finish
+		o.tangent = f.JVP(tangents)
+	})
+	return o.tangent
+}
+
 // RequiresGrad returns true if the node requires gradients.
 func (o *Operator) RequiresGrad() bool {
 	if o.requiresGrad == -1 {
@@ -146,13 +215,34 @@ func (o *Operator) ZeroGrad() {
 }
 
 // AccGrad accumulates the gradients to the node itself.
+// The gradients are first passed through the registered hooks, if any.
 func (o *Operator) AccGrad(grad mat.Matrix) {
 	if !o.RequiresGrad() {
 		return
 	}
+	grad = o.gradHooks.Apply(grad)
+
 	o.cond.L.Lock()
 	defer o.cond.L.Unlock()
+	if grad != nil {
+		o.accGrad(grad)
+	}
+
+	if o.backwardState != idle && atomic.AddInt64(&o.pendingGrads, -1) == 0 {
+		o.cond.Broadcast() // notify all goroutines that have been waiting for the gradients
+	}
+}
 
+// RegisterGradHook registers a GradHook, which is called each time some
+// gradients are accumulated into the operator, before they are propagated
+// to the operands.
+func (o *Operator) RegisterGradHook(hook GradHook) RemoveGradHookFunc {
+	return o.gradHooks.Register(hook)
+}
+
+// accGrad adds grad to the accumulated gradients.
+// It must be called while holding the lock.
+func (o *Operator) accGrad(grad mat.Matrix) {
 	// It is possible to observe `o.grad != nil` and at the same time `reflect.ValueOf(o.grad).IsNil() == true`.
 	// That means somewhere a nil pointer is being cast to `mat.Matrix` and stored in `o.grad`.
 	// Since `mat.Matrix` is an interface, the "nil test" will return false but any method call will panic as
@@ -163,10 +253,6 @@ func (o *Operator) AccGrad(grad mat.Matrix) {
 	} else {
 		o.grad.AddInPlace(grad)
 	}
-
-	if o.backwardState != idle && atomic.AddInt64(&o.pendingGrads, -1) == 0 {
-		o.cond.Broadcast() // notify all goroutines that have been waiting for the gradients
-	}
 }
 
 func (o *Operator) initOutputGrad(outputGrad mat.Matrix) {
@@ -190,15 +276,39 @@ func (o *Operator) initOutputGrad(outputGrad mat.Matrix) {
 }
 
 // forward executes the function and inform all goroutines that have been waiting for the result.
-func (o *Operator) forward() {
-	o.value.Store(o.function.Forward())
+// The execution is recorded by the Profiler p, if not nil, and the value is
+// checked for anomalies if checkAnomalies is true.
+func (o *Operator) forward(p *Profiler, checkAnomalies bool) {
+	var value mat.Matrix
+	if p != nil {
+		start := time.Now()
+		value = o.function.Forward()
+		p.recordForward(o.Name(), time.Since(start), value)
+	} else {
+		value = o.function.Forward()
+	}
+	if checkAnomalies {
+		checkForwardAnomaly(o, value)
+	}
+
+	// The operator must not be accessed after the lock is released, since
+	// it can be released as soon as the value is available (see releaseValue).
 	o.cond.L.Lock()
+	o.value.Store(value)
 	o.cond.Broadcast()
 	o.cond.L.Unlock()
 }
 
+// truncatedBackwarder is implemented by the functions whose backward step
+// takes into account the truncation of the backpropagation (e.g. the
+// functions performing a nested backward step, like Checkpoint).
+type truncatedBackwarder interface {
+	backwardT(tsh *TimeStepHandler, stopAtTimeStep int, gy mat.Matrix, c *gradCapture)
+}
+
 // backward executes the backward
-func (o *Operator) backward() {
+// The execution is recorded by the Profiler p, if not nil.
+func (o *Operator) backward(tsh *TimeStepHandler, stopAtTimeStep int, p *Profiler) {
 	if !o.RequiresGrad() {
 		return
 	}
@@ -207,12 +317,46 @@ func (o *Operator) backward() {
 	if grad == nil {
 		return
 	}
+	if p != nil {
+		start := time.Now()
+		o.backwardFunction(tsh, stopAtTimeStep, grad)
+		p.recordBackward(o.Name(), time.Since(start))
+		return
+	}
+	o.backwardFunction(tsh, stopAtTimeStep, grad)
+}
+
+// backwardFunction executes the backward step of the function.
+func (o *Operator) backwardFunction(tsh *TimeStepHandler, stopAtTimeStep int, grad mat.Matrix) {
+	if f, ok := o.function.(truncatedBackwarder); ok {
+		f.backwardT(tsh, stopAtTimeStep, grad, o.gradCapture)
+		return
+	}
+	if o.gradCapture != nil {
+		o.gradCapture.backward(o, grad)
+		return
+	}
 	o.function.Backward(grad)
 }
 
-// releaseValue sets the operator's value to nil releases the memory.
+// partialGrad returns the gradients accumulated so far, without waiting
+// for the pending ones.
+func (o *Operator) partialGrad() mat.Matrix {
+	o.cond.L.Lock()
+	defer o.cond.L.Unlock()
+	return o.grad
+}
+
+// releaseValue sets the operator's value and tangent to nil releases the memory.
 func (o *Operator) releaseValue() {
-	value := o.Value() // also safely waits for any forward goroutine to finish
+	value := o.Value()
+	// wait for the forward goroutine to release the lock
+	o.cond.L.Lock()
+	o.cond.L.Unlock()
 	mat.ReleaseMatrix(value)
 	o.value = atomic.Value{}
+	if o.tangent != nil {
+		mat.ReleaseMatrix(o.tangent)
+		o.tangent = nil
+	}
 }
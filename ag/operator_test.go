@@ -6,6 +6,7 @@ package ag
 
 import (
 	"testing"
+	"time"
 
 	"github.com/nlpodyssey/spago/ag/fn"
 	"github.com/nlpodyssey/spago/mat"
@@ -48,6 +49,40 @@ func TestOperator_Name(t *testing.T) {
 func testOperatorName[T float.DType](t *testing.T) {
 	op := NewOperator(&dummyFunction[T, Node]{})
 	assert.Equal(t, "dummyFunction", op.Name())
+
+	x := Var(mat.NewScalar[T](1))
+	identity := func(xs []mat.Matrix) mat.Matrix { return xs[0].Clone() }
+	backward := func(_ []mat.Matrix, _, gy mat.Matrix) []mat.Matrix { return []mat.Matrix{gy} }
+	assert.Equal(t, "Foo", Custom("Foo", identity, backward, x).Name())
+	assert.Equal(t, "Custom", Custom("", identity, backward, x).Name())
+}
+
+func TestCustom(t *testing.T) {
+	t.Run("float32", testCustom[float32])
+	t.Run("float64", testCustom[float64])
+}
+
+func testCustom[T float.DType](t *testing.T) {
+	t.Run("nil gradients of operators requiring gradients", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+		add := func(xs []mat.Matrix) mat.Matrix { return xs[0].Add(xs[1]) }
+		backward := func(_ []mat.Matrix, _, gy mat.Matrix) []mat.Matrix {
+			return []mat.Matrix{gy, nil}
+		}
+		y := Custom("Foo", add, backward, x, Exp(x))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			Backward(ReduceSum(y))
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the backward step did not complete")
+		}
+		assert.InDeltaSlice(t, []T{1, 1}, x.Grad().Data(), 1.0e-6)
+	})
 }
 
 func TestOperator_Operands(t *testing.T) {
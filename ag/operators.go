@@ -6,6 +6,7 @@ package ag
 
 import (
 	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
 )
 
 // Abs returns a new operator node as a result of the `Abs` function.
@@ -13,6 +14,13 @@ func Abs(x Node) Node {
 	return NewOperator(fn.NewAbs(x))
 }
 
+// AdaptivePooling returns a new operator node as a result of the
+// fn.Pooling function, pooling x into a rows×columns matrix (see
+// fn.NewAdaptivePooling).
+func AdaptivePooling(x Node, mode fn.PoolingMode, rows, columns int) Node {
+	return NewOperator(fn.NewAdaptivePooling(x, mode, rows, columns))
+}
+
 // Add returns a new operator node as a result of the fn.Add function.
 // As special case, the first node may be null.
 // This help to keep the code as concise as possible e.g. during accumulation.
@@ -48,11 +56,33 @@ func AtVec(x Node, i int) Node {
 	return NewOperator(fn.NewAtVec(x, i))
 }
 
+// AvgPooling returns a new operator node as a result of the fn.Pooling
+// function, averaging the values within non-overlapping rows×columns windows,
+// which must cover the whole input.
+func AvgPooling(x Node, rows, columns int) Node {
+	return NewOperator(fn.NewPooling(x, fn.PoolingConfig{Mode: fn.AvgPool, Rows: rows, Columns: columns}))
+}
+
+// BatchMul returns a new operator node as a result of the fn.BatchMul function.
+func BatchMul(x1, x2 Node, batch int, transA, transB bool) Node {
+	return NewOperator(fn.NewBatchMul(x1, x2, batch, transA, transB))
+}
+
+// BroadcastTo returns a new operator node as a result of the fn.BroadcastTo function.
+func BroadcastTo(x Node, rows, columns int) Node {
+	return NewOperator(fn.NewBroadcastTo(x, rows, columns))
+}
+
 // CELU returns a new operator node as a result of the fn.CELU function.
 func CELU(x, alpha Node) Node {
 	return NewOperator(fn.NewCELU(x, alpha))
 }
 
+// Cholesky returns a new operator node as a result of the fn.Cholesky function.
+func Cholesky(x Node) Node {
+	return NewOperator(fn.NewCholesky(x))
+}
+
 // ColView returns a new operator node as a result of the fn.ColView function.
 func ColView(x Node, column int) Node {
 	return NewOperator(fn.NewColView(x, column))
@@ -68,6 +98,22 @@ func Cos(x Node) Node {
 	return NewOperator(fn.NewCos(x))
 }
 
+// Custom returns a new operator node as a result of a fn.Custom function,
+// defined by the given forward and backward functions over the values of
+// the operands xs.
+//
+// It allows the definition of new differentiable operators without writing
+// a dedicated fn.Function. The gradients of a custom operator can be
+// validated with agtest.CheckGrad.
+//
+// The operator does not support BackwardGraph and forward-mode
+// differentiation (see Operator.Tangent). To support them, create the
+// operator with NewOperator, from a fn.Custom function set up with
+// WithBackwardGraph and WithJVP.
+func Custom(name string, forward fn.CustomForwardFunc, backward fn.CustomBackwardFunc, xs ...Node) Node {
+	return NewOperator(fn.NewCustom(name, forward, backward, xs...))
+}
+
 // Div returns a new operator node as a result of the fn.Div function.
 func Div(x1, x2 Node) Node {
 	return NewOperator(fn.NewDiv(x1, x2))
@@ -108,6 +154,16 @@ func ELU(x, alpha Node) Node {
 	return NewOperator(fn.NewELU(x, alpha))
 }
 
+// Entmax returns a new operator node as a result of the fn.Entmax function.
+func Entmax(x Node, alpha float64) Node {
+	return NewOperator(fn.NewEntmax(x, alpha))
+}
+
+// EntmaxLoss returns a new operator node as a result of the fn.EntmaxLoss function.
+func EntmaxLoss(x Node, c int, alpha float64) Node {
+	return NewOperator(fn.NewEntmaxLoss(x, c, alpha))
+}
+
 // Exp returns a new operator node as a result of the `Exp` function.
 func Exp(x Node) Node {
 	return NewOperator(fn.NewExp(x))
@@ -118,6 +174,11 @@ func Flatten(x Node) Node {
 	return NewOperator(fn.NewFlatten(x))
 }
 
+// Gather returns a new operator node as a result of the fn.Gather function.
+func Gather(x Node, dim int, indices []int) Node {
+	return NewOperator(fn.NewGather(x, dim, indices))
+}
+
 // GELU returns a new operator node as a result of the fn.GELU function.
 func GELU(x Node) Node {
 	return NewOperator(fn.NewGELU(x))
@@ -138,6 +199,16 @@ func Identity(x Node) Node {
 	return NewOperator(fn.NewIdentity(x))
 }
 
+// IndexSelect returns a new operator node as a result of the fn.IndexSelect function.
+func IndexSelect(x Node, dim int, indices []int) Node {
+	return NewOperator(fn.NewIndexSelect(x, dim, indices))
+}
+
+// Inverse returns a new operator node as a result of the fn.Inverse function.
+func Inverse(x Node) Node {
+	return NewOperator(fn.NewInverse(x))
+}
+
 // LeakyReLU returns a new operator node as a result of the fn.LeakyReLU function.
 func LeakyReLU(x, alpha Node) Node {
 	return NewOperator(fn.NewLeakyReLU(x, alpha))
@@ -148,16 +219,46 @@ func Log(x Node) Node {
 	return NewOperator(fn.NewLog(x))
 }
 
+// LogDet returns a new operator node as a result of the fn.LogDet function.
+func LogDet(x Node) Node {
+	return NewOperator(fn.NewLogDet(x))
+}
+
+// LogSoftmax returns a new operator node as a result of the fn.LogSoftmax function.
+func LogSoftmax(x Node) Node {
+	return NewOperator(fn.NewLogSoftmax(x))
+}
+
+// LogSumExpAxis returns a new operator node as a result of the fn.LogSumExpAxis function.
+func LogSumExpAxis(x Node, axis int) Node {
+	return NewOperator(fn.NewLogSumExpAxis(x, axis))
+}
+
+// MaskedFill returns a new operator node as a result of the fn.MaskedFill function.
+func MaskedFill(x Node, mask mat.Matrix, value float64) Node {
+	return NewOperator(fn.NewMaskedFill(x, mask, value))
+}
+
 // Max returns a new operator node as a result of the fn.Max function.
 func Max(x1, x2 Node) Node {
 	return NewOperator(fn.NewMax(x1, x2))
 }
 
+// MaxAxis returns a new operator node as a result of the fn.MaxAxis function.
+func MaxAxis(x Node, axis int) Node {
+	return NewOperator(fn.NewMaxAxis(x, axis))
+}
+
 // MaxPooling returns a new operator node as a result of the fn.MaxPooling function.
 func MaxPooling(x Node, rows, columns int) Node {
 	return NewOperator(fn.NewMaxPooling(x, rows, columns))
 }
 
+// MeanAxis returns a new operator node as a result of the fn.MeanAxis function.
+func MeanAxis(x Node, axis int) Node {
+	return NewOperator(fn.NewMeanAxis(x, axis))
+}
+
 // Min returns a new operator node as a result of the fn.Min function.
 func Min(x1, x2 Node) Node {
 	return NewOperator(fn.NewMin(x1, x2))
@@ -182,6 +283,11 @@ func Neg(x Node) Node {
 	return NewOperator(fn.NewNeg(x))
 }
 
+// Pooling returns a new operator node as a result of the fn.Pooling function.
+func Pooling(x Node, config fn.PoolingConfig) Node {
+	return NewOperator(fn.NewPooling(x, config))
+}
+
 // Pow returns a new operator node as a result of the fn.Pow function.
 func Pow(x Node, power float64) Node {
 	return NewOperator(fn.NewPow(x, power))
@@ -243,11 +349,21 @@ func RowView(x Node, row int) Node {
 	return NewOperator(fn.NewRowView(x, row))
 }
 
+// RowwiseSoftmax returns a new operator node as a result of the fn.RowwiseSoftmax function.
+func RowwiseSoftmax(x Node) Node {
+	return NewOperator(fn.NewRowwiseSoftmax(x))
+}
+
 // ScalarMax returns a new operator node as a result of the fn.ScalarMax function.
 func ScalarMax(xs []Node) Node {
 	return NewOperator(fn.NewScalarMax(xs))
 }
 
+// ScatterAdd returns a new operator node as a result of the fn.ScatterAdd function.
+func ScatterAdd(x Node, dim int, indices []int, src Node) Node {
+	return NewOperator(fn.NewScatterAdd(x, dim, indices, src))
+}
+
 // SELU returns a new operator node as a result of the fn.SELU function.
 func SELU(x, alpha Node, scale Node) Node {
 	return NewOperator(fn.NewSELU(x, alpha, scale))
@@ -278,6 +394,16 @@ func Softmax(x Node) Node {
 	return NewOperator(fn.NewSoftmax(x))
 }
 
+// SoftmaxCrossEntropy returns a new operator node as a result of the fn.SoftmaxCrossEntropy function.
+func SoftmaxCrossEntropy(x Node, c int) Node {
+	return NewOperator(fn.NewSoftmaxCrossEntropy(x, c))
+}
+
+// Solve returns a new operator node as a result of the fn.Solve function.
+func Solve(x1, x2 Node) Node {
+	return NewOperator(fn.NewSolve(x1, x2))
+}
+
 // SoftPlus returns a new operator node as a result of the fn.SoftPlus function.
 func SoftPlus(x, beta, threshold Node) Node {
 	return NewOperator(fn.NewSoftPlus(x, beta, threshold))
@@ -299,6 +425,10 @@ func SparseMax(x Node) Node {
 }
 
 // SparseMaxLoss returns a new operator node as a result of the fn.SparseMaxLoss function.
+//
+// The result is a vector, which is the analogue of the log-softmax for the
+// sparsemax: its element c is the opposite of the sparsemax loss of the gold
+// class c (see losses.SparseMaxLoss).
 func SparseMaxLoss(x Node) Node {
 	return NewOperator(fn.NewSparseMaxLoss(x))
 }
@@ -318,6 +448,11 @@ func Stack(xs ...Node) Node {
 	return NewOperator(fn.NewStack(xs))
 }
 
+// StdAxis returns a new operator node as a result of the fn.StdAxis function.
+func StdAxis(x Node, axis int) Node {
+	return NewOperator(fn.NewStdAxis(x, axis))
+}
+
 // Sub returns a new operator node as a result of the fn.Sub function.
 func Sub(x1, x2 Node) Node {
 	return NewOperator(fn.NewSub(x1, x2))
@@ -328,6 +463,16 @@ func SubScalar(x1, x2 Node) Node {
 	return NewOperator(fn.NewSubScalar(x1, x2))
 }
 
+// SumAxis returns a new operator node as a result of the fn.SumAxis function.
+func SumAxis(x Node, axis int) Node {
+	return NewOperator(fn.NewSumAxis(x, axis))
+}
+
+// SumTo returns a new operator node as a result of the fn.SumTo function.
+func SumTo(x Node, rows, columns int) Node {
+	return NewOperator(fn.NewSumTo(x, rows, columns))
+}
+
 // Swish returns a new operator node as a result of the fn.Swish function.
 func Swish(x Node) Node {
 	return NewOperator(fn.NewSwish(x))
@@ -357,3 +502,13 @@ func Tanh(x Node) Node {
 func Threshold(x, threshold, k Node) Node {
 	return NewOperator(fn.NewThreshold(x, threshold, k))
 }
+
+// VarAxis returns a new operator node as a result of the fn.VarAxis function.
+func VarAxis(x Node, axis int) Node {
+	return NewOperator(fn.NewVarAxis(x, axis))
+}
+
+// Where returns a new operator node as a result of the fn.Where function.
+func Where(cond mat.Matrix, x1, x2 Node) Node {
+	return NewOperator(fn.NewWhere(cond, x1, x2))
+}
@@ -0,0 +1,179 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// profiler is the Profiler set with SetProfiler, stored as *Profiler.
+var profiler atomic.Value
+
+func init() {
+	profiler.Store((*Profiler)(nil))
+}
+
+// SetProfiler sets the Profiler recording the statistics of the operators.
+// Profiling is disabled by passing nil, which is the default.
+func SetProfiler(p *Profiler) {
+	profiler.Store(p)
+}
+
+// activeProfiler returns the Profiler set with SetProfiler, or nil.
+func activeProfiler() *Profiler {
+	return profiler.Load().(*Profiler)
+}
+
+// Profiler records the statistics of the operators, grouped by their name
+// (see Operator.Name). The forward step of an operator is recorded if the
+// Profiler is set (see SetProfiler) when the operator is created, and the
+// backward step if it is set when the backward step begins.
+//
+// It is safe for concurrent use.
+type Profiler struct {
+	mu    sync.Mutex
+	start time.Time
+	stats map[string]*OperatorStats
+}
+
+// OperatorStats are the statistics of a type of operator.
+type OperatorStats struct {
+	// Name is the name of the operator.
+	Name string `json:"name"`
+	// ForwardCalls is the number of forward steps.
+	ForwardCalls int64 `json:"forward_calls"`
+	// ForwardTime is the cumulative wall time of the forward steps.
+	ForwardTime time.Duration `json:"forward_time_ns"`
+	// BackwardCalls is the number of backward steps.
+	BackwardCalls int64 `json:"backward_calls"`
+	// BackwardTime is the cumulative wall time of the backward steps.
+	BackwardTime time.Duration `json:"backward_time_ns"`
+	// SchedulingTime is the cumulative time elapsed between the submission
+	// of the forward and backward steps to the Executor, and the beginning
+	// of their execution.
+	SchedulingTime time.Duration `json:"scheduling_time_ns"`
+	// AllocatedBytes is the cumulative size of the matrices produced by the
+	// forward steps.
+	AllocatedBytes int64 `json:"allocated_bytes"`
+}
+
+// TotalTime returns the cumulative wall time of both forward and backward
+// steps.
+func (s OperatorStats) TotalTime() time.Duration {
+	return s.ForwardTime + s.BackwardTime
+}
+
+// NewProfiler returns a new empty Profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{
+		start: time.Now(),
+		stats: make(map[string]*OperatorStats),
+	}
+}
+
+// Reset clears the statistics recorded so far.
+func (p *Profiler) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.start = time.Now()
+	p.stats = make(map[string]*OperatorStats)
+}
+
+// Stats returns a copy of the statistics recorded so far, sorted by total
+// time, in descending order, then by name.
+func (p *Profiler) Stats() []OperatorStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make([]OperatorStats, 0, len(p.stats))
+	for _, s := range p.stats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		ti, tj := stats[i].TotalTime(), stats[j].TotalTime()
+		if ti != tj {
+			return ti > tj
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
+
+// WriteText writes the statistics to w as a human-readable table.
+func (p *Profiler) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "OPERATOR\tFWD CALLS\tFWD TIME\tBWD CALLS\tBWD TIME\tSCHEDULING\tALLOCATED\t")
+	for _, s := range p.Stats() {
+		fmt.Fprintf(tw, "%s\t%d\t%v\t%d\t%v\t%v\t%d\t\n", s.Name,
+			s.ForwardCalls, s.ForwardTime, s.BackwardCalls, s.BackwardTime, s.SchedulingTime, s.AllocatedBytes)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the statistics to w as a JSON array. The durations are
+// expressed in nanoseconds.
+func (p *Profiler) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(p.Stats())
+}
+
+// operatorStats returns the statistics of the named operator, creating
+// them if necessary. It must be called while holding the lock.
+func (p *Profiler) operatorStats(name string) *OperatorStats {
+	s, ok := p.stats[name]
+	if !ok {
+		s = &OperatorStats{Name: name}
+		p.stats[name] = s
+	}
+	return s
+}
+
+func (p *Profiler) recordForward(name string, d time.Duration, value mat.Matrix) {
+	var bytes int64
+	if value != nil {
+		bytes = int64(value.Size() * value.Data().BitSize() / 8)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.operatorStats(name)
+	s.ForwardCalls++
+	s.ForwardTime += d
+	s.AllocatedBytes += bytes
+}
+
+func (p *Profiler) recordBackward(name string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.operatorStats(name)
+	s.BackwardCalls++
+	s.BackwardTime += d
+}
+
+func (p *Profiler) recordScheduling(name string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.operatorStats(name).SchedulingTime += d
+}
+
+// goProfiled executes f with the Executor e, recording the scheduling time
+// of the operator with the Profiler p, if not nil.
+func goProfiled(e Executor, o *Operator, p *Profiler, f func()) {
+	if p == nil {
+		e.Go(f)
+		return
+	}
+	submitted := time.Now()
+	e.Go(func() {
+		p.recordScheduling(o.Name(), time.Since(submitted))
+		f()
+	})
+}
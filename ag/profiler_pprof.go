@@ -0,0 +1,196 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"compress/gzip"
+	"io"
+	"time"
+)
+
+// WritePprof writes the statistics to w as a gzip-compressed protocol
+// buffer in the pprof format, which can be analyzed with "go tool pprof".
+//
+// Each operator is represented by two functions, "<name>.Forward" and
+// "<name>.Backward", and each sample has three values: the number of calls
+// (calls/count), the wall time (time/nanoseconds) and the allocated bytes
+// (alloc_space/bytes).
+func (p *Profiler) WritePprof(w io.Writer) error {
+	p.mu.Lock()
+	start := p.start
+	p.mu.Unlock()
+
+	b := newPprofBuilder()
+	for _, s := range p.Stats() {
+		b.addSample(s.Name+".Forward", s.ForwardCalls, int64(s.ForwardTime), s.AllocatedBytes)
+		b.addSample(s.Name+".Backward", s.BackwardCalls, int64(s.BackwardTime), 0)
+	}
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(b.build(start, time.Since(start))); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Field numbers of the pprof protocol buffer messages.
+// See https://github.com/google/pprof/blob/main/proto/profile.proto
+const (
+	pprofProfileSampleType    = 1
+	pprofProfileSample        = 2
+	pprofProfileLocation      = 4
+	pprofProfileFunction      = 5
+	pprofProfileStringTable   = 6
+	pprofProfileTimeNanos     = 9
+	pprofProfileDurationNanos = 10
+	pprofProfilePeriodType    = 11
+	pprofProfilePeriod        = 12
+
+	pprofValueTypeType = 1
+	pprofValueTypeUnit = 2
+
+	pprofSampleLocationID = 1
+	pprofSampleValue      = 2
+
+	pprofLocationID   = 1
+	pprofLocationLine = 4
+
+	pprofLineFunctionID = 1
+
+	pprofFunctionID         = 1
+	pprofFunctionName       = 2
+	pprofFunctionSystemName = 3
+)
+
+// pprofBuilder builds a pprof profile, where each sample is located in a
+// distinct function.
+type pprofBuilder struct {
+	strings map[string]int64
+	table   []string
+	samples protoBuffer
+	locs    protoBuffer
+	funcs   protoBuffer
+	nextID  uint64
+}
+
+func newPprofBuilder() *pprofBuilder {
+	b := &pprofBuilder{strings: make(map[string]int64)}
+	b.stringIndex("") // the first string of the table must be empty
+	return b
+}
+
+func (b *pprofBuilder) stringIndex(s string) int64 {
+	if i, ok := b.strings[s]; ok {
+		return i
+	}
+	i := int64(len(b.table))
+	b.strings[s] = i
+	b.table = append(b.table, s)
+	return i
+}
+
+func (b *pprofBuilder) addSample(function string, values ...int64) {
+	b.nextID++
+	id := b.nextID
+	name := b.stringIndex(function)
+
+	var f protoBuffer
+	f.uint64(pprofFunctionID, id)
+	f.int64(pprofFunctionName, name)
+	f.int64(pprofFunctionSystemName, name)
+	b.funcs.message(pprofProfileFunction, f)
+
+	var line protoBuffer
+	line.uint64(pprofLineFunctionID, id)
+	var loc protoBuffer
+	loc.uint64(pprofLocationID, id)
+	loc.message(pprofLocationLine, line)
+	b.locs.message(pprofProfileLocation, loc)
+
+	var s protoBuffer
+	s.packedUint64(pprofSampleLocationID, id)
+	vs := make([]uint64, len(values))
+	for i, v := range values {
+		vs[i] = uint64(v)
+	}
+	s.packedUint64(pprofSampleValue, vs...)
+	b.samples.message(pprofProfileSample, s)
+}
+
+func (b *pprofBuilder) valueType(typ, unit string) protoBuffer {
+	var vt protoBuffer
+	vt.int64(pprofValueTypeType, b.stringIndex(typ))
+	vt.int64(pprofValueTypeUnit, b.stringIndex(unit))
+	return vt
+}
+
+func (b *pprofBuilder) build(start time.Time, duration time.Duration) []byte {
+	var p protoBuffer
+	p.message(pprofProfileSampleType, b.valueType("calls", "count"))
+	p.message(pprofProfileSampleType, b.valueType("time", "nanoseconds"))
+	p.message(pprofProfileSampleType, b.valueType("alloc_space", "bytes"))
+	p.message(pprofProfilePeriodType, b.valueType("calls", "count"))
+	p.int64(pprofProfilePeriod, 1)
+	p.int64(pprofProfileTimeNanos, start.UnixNano())
+	p.int64(pprofProfileDurationNanos, int64(duration))
+	p = append(p, b.samples...)
+	p = append(p, b.locs...)
+	p = append(p, b.funcs...)
+	for _, s := range b.table {
+		p.string(pprofProfileStringTable, s)
+	}
+	return p
+}
+
+// protoBuffer is a minimal encoder of protocol buffer messages.
+type protoBuffer []byte
+
+const (
+	protoWireVarint = 0
+	protoWireBytes  = 2
+)
+
+func (b *protoBuffer) varint(x uint64) {
+	for x >= 0x80 {
+		*b = append(*b, byte(x)|0x80)
+		x >>= 7
+	}
+	*b = append(*b, byte(x))
+}
+
+func (b *protoBuffer) key(field, wireType int) {
+	b.varint(uint64(field<<3 | wireType))
+}
+
+func (b *protoBuffer) uint64(field int, x uint64) {
+	b.key(field, protoWireVarint)
+	b.varint(x)
+}
+
+func (b *protoBuffer) int64(field int, x int64) {
+	b.uint64(field, uint64(x))
+}
+
+func (b *protoBuffer) bytes(field int, data []byte) {
+	b.key(field, protoWireBytes)
+	b.varint(uint64(len(data)))
+	*b = append(*b, data...)
+}
+
+func (b *protoBuffer) string(field int, s string) {
+	b.bytes(field, []byte(s))
+}
+
+func (b *protoBuffer) message(field int, m protoBuffer) {
+	b.bytes(field, m)
+}
+
+func (b *protoBuffer) packedUint64(field int, xs ...uint64) {
+	var data protoBuffer
+	for _, x := range xs {
+		data.varint(x)
+	}
+	b.bytes(field, data)
+}
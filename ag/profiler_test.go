@@ -0,0 +1,112 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiler(t *testing.T) {
+	t.Run("float32", testProfiler[float32])
+	t.Run("float64", testProfiler[float64])
+}
+
+func testProfiler[T float.DType](t *testing.T) {
+	p := NewProfiler()
+	SetProfiler(p)
+
+	x := Var(mat.NewVecDense([]T{0.1, 0.2, 0.3})).WithGrad(true)
+	w := Var(mat.NewDense(2, 3, []T{0.4, 0.5, -0.6, 0.7, -0.8, 0.9})).WithGrad(true)
+	y := ReduceSum(Tanh(Mul(w, x)))
+	Backward(y)
+	z := Tanh(x)
+	z.Value()
+
+	SetProfiler(nil)
+	Exp(x).Value() // not recorded
+
+	stats := p.Stats()
+	require.Len(t, stats, 3)
+	byName := make(map[string]OperatorStats)
+	for _, s := range stats {
+		byName[s.Name] = s
+	}
+	require.Contains(t, byName, "Mul")
+	require.Contains(t, byName, "Tanh")
+	require.Contains(t, byName, "ReduceSum")
+
+	size := int64(float.SliceInterface([]T{}).BitSize() / 8)
+
+	mul := byName["Mul"]
+	assert.Equal(t, int64(1), mul.ForwardCalls)
+	assert.Equal(t, int64(1), mul.BackwardCalls)
+	assert.Equal(t, 2*size, mul.AllocatedBytes)
+	assert.Equal(t, mul.ForwardTime+mul.BackwardTime, mul.TotalTime())
+
+	tanh := byName["Tanh"]
+	assert.Equal(t, int64(2), tanh.ForwardCalls)
+	assert.Equal(t, int64(1), tanh.BackwardCalls)
+	assert.Equal(t, 5*size, tanh.AllocatedBytes)
+
+	for i := 1; i < len(stats); i++ {
+		assert.GreaterOrEqual(t, stats[i-1].TotalTime(), stats[i].TotalTime())
+	}
+
+	t.Run("WriteText", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, p.WriteText(&buf))
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		require.Len(t, lines, 4)
+		assert.Contains(t, string(lines[0]), "OPERATOR")
+		assert.Contains(t, buf.String(), "ReduceSum")
+	})
+
+	t.Run("WriteJSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, p.WriteJSON(&buf))
+		var decoded []OperatorStats
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, stats, decoded)
+		assert.Contains(t, buf.String(), `"forward_calls":2`)
+	})
+
+	t.Run("WritePprof", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, p.WritePprof(&buf))
+		r, err := gzip.NewReader(&buf)
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		for _, s := range []string{"Mul.Forward", "Tanh.Backward", "calls", "nanoseconds", "alloc_space"} {
+			assert.Contains(t, string(data), s)
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		p.Reset()
+		assert.Empty(t, p.Stats())
+	})
+}
+
+func TestProtoBuffer(t *testing.T) {
+	var b protoBuffer
+	b.uint64(1, 150)
+	b.string(2, "testing")
+	b.packedUint64(3, 3, 270)
+	assert.Equal(t, []byte{
+		0x08, 0x96, 0x01,
+		0x12, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		0x1a, 0x03, 0x03, 0x8e, 0x02,
+	}, []byte(b))
+}
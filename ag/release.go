@@ -9,7 +9,8 @@ package ag
 // of each operator.
 //
 // Any Node implementation can be passed to the function, however only Operators
-// and their operands will be taken into account, and the rest simply ignored.
+// and their operands, and the Results computed in inference mode, will be taken
+// into account, and the rest simply ignored.
 //
 // This function is not concurrency safe.
 //
@@ -17,12 +18,18 @@ package ag
 // Any freed operator MUST not be used after this operation is performed.
 func ReleaseGraph(nodes ...Node) {
 	for _, node := range nodes {
-		if op, ok := node.(*Operator); ok && op.function != nil {
-			ReleaseGraph(op.Operands()...)
-			op.releaseValue()
-			op.ZeroGrad()
-			op.function = nil
-			op.cond.L = nil
+		switch n := node.(type) {
+		case *Operator:
+			if n.function == nil {
+				continue
+			}
+			ReleaseGraph(n.Operands()...)
+			n.releaseValue()
+			n.ZeroGrad()
+			n.function = nil
+			n.cond.L = nil
+		case *Result:
+			n.release()
 		}
 	}
 }
@@ -0,0 +1,20 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import "github.com/nlpodyssey/spago/mat"
+
+// Tangent returns the tangent of the node, as computed by forward-mode
+// differentiation, or nil if the node has no tangent.
+//
+// The tangents are seeded on the Variables with Variable.WithTangent, and
+// propagated by the Operators along with their values (see Operator.Tangent).
+// Any other type of Node, such as a Wrapper, has no tangent.
+func Tangent(x Node) mat.Matrix {
+	if t, ok := x.(interface{ Tangent() mat.Matrix }); ok {
+		return t.Tangent()
+	}
+	return nil
+}
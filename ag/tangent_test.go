@@ -0,0 +1,71 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTangent(t *testing.T) {
+	t.Run("float32", testTangent[float32])
+	t.Run("float64", testTangent[float64])
+}
+
+func testTangent[T float.DType](t *testing.T) {
+	t.Run("single input", func(t *testing.T) {
+		xs := []float64{0.5, -1, 2}
+		vs := []float64{1, 0.5, -2}
+		x := Var(mat.NewVecDense([]T{0.5, -1, 2})).WithTangent(mat.NewVecDense([]T{1, 0.5, -2}))
+		y := Sin(Square(x))
+
+		ty := Tangent(y)
+		require.NotNil(t, ty)
+		expected := make([]T, len(xs))
+		for i, xi := range xs {
+			expected[i] = T(math.Cos(xi*xi) * 2 * xi * vs[i])
+		}
+		assert.InDeltaSlice(t, expected, ty.Data(), 1.0e-5)
+	})
+
+	t.Run("consistency with reverse mode", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{0.1, -0.2, 0.3})).WithGrad(true).
+			WithTangent(mat.NewVecDense([]T{0.5, 1, -1}))
+		w := Var(mat.NewDense(2, 3, []T{0.4, 0.5, -0.6, 0.7, -0.8, 0.9})).WithGrad(true).
+			WithTangent(mat.NewDense(2, 3, []T{1, 0, -1, 0.5, 0.5, 2}))
+		y := ReduceSum(Tanh(Mul(w, x)))
+
+		ty := Tangent(y)
+		require.NotNil(t, ty)
+
+		Backward(y)
+		gx := x.Grad().Prod(x.Tangent()).Sum().Scalar().F64()
+		gw := w.Grad().Prod(w.Tangent()).Sum().Scalar().F64()
+		assert.InDelta(t, gx+gw, ty.Scalar().F64(), 1.0e-5)
+	})
+
+	t.Run("no tangents", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2}))
+		assert.Nil(t, Tangent(x))
+		assert.Nil(t, Tangent(Exp(x)))
+	})
+
+	t.Run("StopGrad stops the tangents", func(t *testing.T) {
+		x := Var(mat.NewVecDense([]T{1, 2})).WithTangent(mat.NewVecDense([]T{1, 1}))
+		assert.Nil(t, Tangent(StopGrad(x)))
+		assert.Nil(t, Tangent(Exp(StopGrad(x))))
+	})
+
+	t.Run("WithTangent panics with incompatible shape", func(t *testing.T) {
+		assert.Panics(t, func() {
+			Var(mat.NewVecDense([]T{1, 2})).WithTangent(mat.NewVecDense([]T{1}))
+		})
+	})
+}
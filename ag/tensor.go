@@ -0,0 +1,261 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Tensor is an N-dimensional view of a node of the graph: the elements of
+// its value, in row-major order, are the elements of a tensor with the given
+// shape (see mat.TensorView), and the same applies to its gradients.
+//
+// Tensors allow batched and multi-channel computations to be expressed as
+// single nodes of the graph. The functions operating on tensors return nodes
+// whose values are the matrices representing the resulting tensors (see
+// mat.Tensor.Matrix), so that they can be used with any other function.
+type Tensor struct {
+	node  Node
+	shape []int
+}
+
+// NewTensor returns a new Tensor viewing the value of the node x with the
+// given shape. One of the dimensions can be -1, in which case it is inferred
+// from the size of the value.
+func NewTensor(x Node, shape ...int) Tensor {
+	return Tensor{
+		node:  x,
+		shape: mat.ResolveShape(shape, x.Value().Size()),
+	}
+}
+
+// Node returns the node of the graph viewed as a tensor.
+func (t Tensor) Node() Node {
+	return t.node
+}
+
+// Shape returns the size of each dimension of the tensor.
+func (t Tensor) Shape() []int {
+	return append([]int{}, t.shape...)
+}
+
+// Rank returns the number of dimensions of the tensor.
+func (t Tensor) Rank() int {
+	return len(t.shape)
+}
+
+// Value returns the value of the node, viewed as a tensor.
+func (t Tensor) Value() *mat.Tensor {
+	return mat.TensorView(t.node.Value(), t.shape...)
+}
+
+// Grad returns the gradients of the node, viewed as a tensor, or nil if the
+// node has no gradients.
+func (t Tensor) Grad() *mat.Tensor {
+	g := t.node.Grad()
+	if g == nil {
+		return nil
+	}
+	return mat.TensorView(g, t.shape...)
+}
+
+// TensorReshape returns the tensor x viewed with a different shape. One of
+// the dimensions can be -1, in which case it is inferred from the size of
+// the tensor. No new node is added to the graph.
+func TensorReshape(x Tensor, shape ...int) Tensor {
+	return Tensor{
+		node:  x.node,
+		shape: mat.ResolveShape(shape, mat.ShapeSize(x.shape)),
+	}
+}
+
+// TensorPermute returns a new tensor with the dimensions of x permuted:
+// the dimension i of the new tensor is the dimension axes[i] of x.
+func TensorPermute(x Tensor, axes ...int) Tensor {
+	if len(axes) != len(x.shape) {
+		panic(fmt.Sprintf("ag: invalid permutation %v for a tensor of rank %d", axes, len(x.shape)))
+	}
+	seen := make([]bool, len(axes))
+	shape := make([]int, len(axes))
+	for i, a := range axes {
+		if a < 0 || a >= len(axes) || seen[a] {
+			panic(fmt.Sprintf("ag: invalid permutation %v for a tensor of rank %d", axes, len(x.shape)))
+		}
+		seen[a] = true
+		shape[i] = x.shape[a]
+	}
+	return Tensor{
+		node:  NewOperator(fn.NewTensorPermute(x.node, x.shape, axes)),
+		shape: shape,
+	}
+}
+
+// TensorBroadcastTo returns a new tensor with the given shape, repeating
+// the elements of x along its dimensions of size 1, and along the missing
+// leading dimensions (see mat.BroadcastShapes).
+func TensorBroadcastTo(x Tensor, shape ...int) Tensor {
+	if s, ok := mat.BroadcastShapes(x.shape, shape); !ok || !equalShapes(s, shape) {
+		panic(fmt.Sprintf("ag: cannot broadcast a tensor with shape %v to shape %v", x.shape, shape))
+	}
+	return Tensor{
+		node:  NewOperator(fn.NewTensorBroadcastTo(x.node, x.shape, shape)),
+		shape: append([]int{}, shape...),
+	}
+}
+
+// TensorSumTo returns a new tensor with the given shape, summing the
+// elements of x along the dimensions reduced to size 1, and along the
+// leading dimensions which are removed. It is the inverse of
+// TensorBroadcastTo.
+func TensorSumTo(x Tensor, shape ...int) Tensor {
+	if s, ok := mat.BroadcastShapes(x.shape, shape); !ok || !equalShapes(s, x.shape) {
+		panic(fmt.Sprintf("ag: cannot sum a tensor with shape %v to shape %v", x.shape, shape))
+	}
+	return Tensor{
+		node:  NewOperator(fn.NewTensorSumTo(x.node, x.shape, shape)),
+		shape: append([]int{}, shape...),
+	}
+}
+
+// TensorAdd returns a new tensor with the element-wise sum of the tensors
+// a and b, broadcast against each other (see mat.BroadcastShapes).
+func TensorAdd(a, b Tensor) Tensor {
+	return tensorElementWise(a, b, Add)
+}
+
+// TensorSub returns a new tensor with the element-wise subtraction of the
+// tensor b from a, broadcast against each other (see mat.BroadcastShapes).
+func TensorSub(a, b Tensor) Tensor {
+	return tensorElementWise(a, b, Sub)
+}
+
+// TensorProd returns a new tensor with the element-wise product of the
+// tensors a and b, broadcast against each other (see mat.BroadcastShapes).
+func TensorProd(a, b Tensor) Tensor {
+	return tensorElementWise(a, b, Prod)
+}
+
+// TensorDiv returns a new tensor with the element-wise division of the
+// tensor a by b, broadcast against each other (see mat.BroadcastShapes).
+func TensorDiv(a, b Tensor) Tensor {
+	return tensorElementWise(a, b, Div)
+}
+
+// TensorMatMul returns a new tensor with the matrix multiplication of each
+// pair of matrices from the tensors a and b, with shapes [..., n, k] and
+// [..., k, m], where the leading dimensions must be equal. The resulting
+// tensor has shape [..., n, m]. The products are performed in a single
+// operator (see BatchMul).
+func TensorMatMul(a, b Tensor) Tensor {
+	ra, rb := len(a.shape), len(b.shape)
+	if ra < 2 || ra != rb || !equalShapes(a.shape[:ra-2], b.shape[:rb-2]) || a.shape[ra-1] != b.shape[rb-2] {
+		panic(fmt.Sprintf("ag: tensors have incompatible shapes %v and %v", a.shape, b.shape))
+	}
+	shape := append(append([]int{}, a.shape[:ra-1]...), b.shape[rb-1])
+	batch := mat.ShapeSize(a.shape[:ra-2])
+	return Tensor{
+		node:  BatchMul(a.matrixNode(a.shape), b.matrixNode(b.shape), batch, false, false),
+		shape: shape,
+	}
+}
+
+// TensorConv1D returns a new tensor with the 1D convolution of the tensor x,
+// with shape [inChannels, length], by the kernels w, with shape
+// [outChannels, inChannels/groups, kernelSize]. The resulting tensor has
+// shape [outChannels, outLength] (see fn.NewConv1D).
+func TensorConv1D(x, w Tensor, config fn.ConvConfig) Tensor {
+	f := fn.NewConv1D(x.node, w.node, x.shape, w.shape, config)
+	return Tensor{
+		node:  NewOperator(f),
+		shape: f.OutputShape(),
+	}
+}
+
+// TensorConv2D returns a new tensor with the 2D convolution of the tensor x,
+// with shape [inChannels, height, width], by the kernels w, with shape
+// [outChannels, inChannels/groups, kernelHeight, kernelWidth]. The resulting
+// tensor has shape [outChannels, outHeight, outWidth] (see fn.NewConv2D).
+func TensorConv2D(x, w Tensor, config fn.ConvConfig) Tensor {
+	f := fn.NewConv2D(x.node, w.node, x.shape, w.shape, config)
+	return Tensor{
+		node:  NewOperator(f),
+		shape: f.OutputShape(),
+	}
+}
+
+// TensorConvTranspose1D returns a new tensor with the 1D transposed
+// convolution of the tensor x, with shape [inChannels, length], by the
+// kernels w, with shape [inChannels, outChannels/groups, kernelSize]. The
+// resulting tensor has shape [outChannels, outLength] (see
+// fn.NewConvTranspose1D).
+func TensorConvTranspose1D(x, w Tensor, config fn.ConvTransposeConfig) Tensor {
+	f := fn.NewConvTranspose1D(x.node, w.node, x.shape, w.shape, config)
+	return Tensor{
+		node:  NewOperator(f),
+		shape: f.OutputShape(),
+	}
+}
+
+// TensorConvTranspose2D returns a new tensor with the 2D transposed
+// convolution of the tensor x, with shape [inChannels, height, width], by
+// the kernels w, with shape [inChannels, outChannels/groups, kernelHeight,
+// kernelWidth]. The resulting tensor has shape [outChannels, outHeight,
+// outWidth] (see fn.NewConvTranspose2D).
+func TensorConvTranspose2D(x, w Tensor, config fn.ConvTransposeConfig) Tensor {
+	f := fn.NewConvTranspose2D(x.node, w.node, x.shape, w.shape, config)
+	return Tensor{
+		node:  NewOperator(f),
+		shape: f.OutputShape(),
+	}
+}
+
+// TensorMap returns a new tensor, with the same shape of x, whose node is
+// the result of the function f applied to the node of x. The function must
+// preserve the number of elements, as element-wise functions do (e.g. Tanh).
+func TensorMap(x Tensor, f func(Node) Node) Tensor {
+	return Tensor{
+		node:  f(x.node),
+		shape: x.Shape(),
+	}
+}
+
+func tensorElementWise(a, b Tensor, f func(x1, x2 Node) Node) Tensor {
+	shape, ok := mat.BroadcastShapes(a.shape, b.shape)
+	if !ok {
+		panic(fmt.Sprintf("ag: tensors have incompatible shapes %v and %v", a.shape, b.shape))
+	}
+	return Tensor{
+		node:  f(a.matrixNode(shape), b.matrixNode(shape)),
+		shape: shape,
+	}
+}
+
+// matrixNode returns a node whose value is the matrix representing the
+// tensor broadcast to the given shape (see mat.Tensor.Matrix).
+func (t Tensor) matrixNode(shape []int) Node {
+	if !equalShapes(t.shape, shape) {
+		return TensorBroadcastTo(t, shape...).node
+	}
+	rows, cols := mat.TensorMatrixDims(shape)
+	if v := t.node.Value(); v.Rows() != rows || v.Columns() != cols {
+		return Reshape(t.node, rows, cols)
+	}
+	return t.node
+}
+
+func equalShapes(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, d := range a {
+		if d != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,185 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTensor(t *testing.T) {
+	t.Run("float32", testNewTensor[float32])
+	t.Run("float64", testNewTensor[float64])
+}
+
+func testNewTensor[T float.DType](t *testing.T) {
+	x := Var(mat.NewVecDense([]T{1, 2, 3, 4, 5, 6})).WithGrad(true)
+
+	tx := NewTensor(x, 2, -1)
+	assert.Same(t, x, tx.Node())
+	assert.Equal(t, []int{2, 3}, tx.Shape())
+	assert.Equal(t, 2, tx.Rank())
+	assert.Equal(t, T(6), float.ValueOf[T](tx.Value().At(1, 2)))
+	assert.Nil(t, tx.Grad())
+
+	x.AccGrad(mat.NewVecDense([]T{1, 1, 1, 1, 1, 1}))
+	require.NotNil(t, tx.Grad())
+	assert.Equal(t, []int{2, 3}, tx.Grad().Shape())
+
+	r := TensorReshape(tx, 3, 1, -1)
+	assert.Same(t, x, r.Node())
+	assert.Equal(t, []int{3, 1, 2}, r.Shape())
+
+	assert.Panics(t, func() { NewTensor(x, 4, -1) })
+	assert.Panics(t, func() { TensorReshape(tx, 5) })
+}
+
+func TestTensorFunctions(t *testing.T) {
+	t.Run("float32", testTensorFunctions[float32])
+	t.Run("float64", testTensorFunctions[float64])
+}
+
+func testTensorFunctions[T float.DType](t *testing.T) {
+	// a batch of 2 images with 2 channels of 1×3 pixels
+	x := Var(mat.NewVecDense([]T{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12})).WithGrad(true)
+	// a bias for each channel
+	b := Var(mat.NewVecDense([]T{10, 20})).WithGrad(true)
+
+	tx := NewTensor(x, 2, 2, 1, 3)
+	tb := NewTensor(b, 2, 1, 1)
+
+	y := TensorMap(TensorAdd(tx, tb), Neg)
+	assert.Equal(t, []int{2, 2, 1, 3}, y.Shape())
+	assert.Equal(t, []T{-11, -12, -13, -24, -25, -26, -17, -18, -19, -30, -31, -32}, mat.Data[T](y.Value().Matrix()))
+
+	// channels last
+	z := TensorPermute(y, 0, 2, 3, 1)
+	assert.Equal(t, []int{2, 1, 3, 2}, z.Shape())
+	zv := z.Node().Value()
+	assert.Equal(t, 6, zv.Rows())
+	assert.Equal(t, 2, zv.Columns())
+	assert.Equal(t, []T{-11, -24, -12, -25, -13, -26, -17, -30, -18, -31, -19, -32}, mat.Data[T](zv))
+
+	gz := mat.NewDense(6, 2, []T{1, 2, 1, 2, 1, 2, 1, 2, 1, 2, 1, 2})
+	Backward(z.Node(), gz)
+
+	assert.Equal(t, []T{-1, -1, -1, -2, -2, -2, -1, -1, -1, -2, -2, -2}, mat.Data[T](x.Grad()))
+	assert.Equal(t, []T{-6, -12}, mat.Data[T](b.Grad()))
+
+	// sum over batch and pixels
+	s := TensorSumTo(tx, 2, 1, 1)
+	assert.Equal(t, []T{30, 48}, mat.Data[T](s.Value().Matrix()))
+
+	assert.Panics(t, func() { TensorPermute(tx, 0, 1, 2) })
+	assert.Panics(t, func() { TensorPermute(tx, 0, 1, 2, 2) })
+	assert.Panics(t, func() { TensorAdd(tx, NewTensor(b, 2)) })
+	assert.Panics(t, func() { TensorBroadcastTo(tx, 2, 2, 3) })
+	assert.Panics(t, func() { TensorSumTo(tb, 2, 2) })
+}
+
+func TestTensorElementWise(t *testing.T) {
+	t.Run("float32", testTensorElementWise[float32])
+	t.Run("float64", testTensorElementWise[float64])
+}
+
+func testTensorElementWise[T float.DType](t *testing.T) {
+	a := NewTensor(Var(mat.NewDense(2, 3, []T{1, 2, 3, 4, 5, 6})), 2, 3)
+	b := NewTensor(Var(mat.NewVecDense([]T{2, 4, 8})), 3)
+
+	testCases := []struct {
+		name     string
+		f        func(a, b Tensor) Tensor
+		expected []T
+	}{
+		{"TensorAdd", TensorAdd, []T{3, 6, 11, 6, 9, 14}},
+		{"TensorSub", TensorSub, []T{-1, -2, -5, 2, 1, -2}},
+		{"TensorProd", TensorProd, []T{2, 8, 24, 8, 20, 48}},
+		{"TensorDiv", TensorDiv, []T{0.5, 0.5, 0.375, 2, 1.25, 0.75}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			y := tc.f(a, b)
+			assert.Equal(t, []int{2, 3}, y.Shape())
+			assert.InDeltaSlice(t, tc.expected, mat.Data[T](y.Node().Value()), 1.0e-6)
+		})
+	}
+}
+
+func TestTensorMatMul(t *testing.T) {
+	t.Run("float32", testTensorMatMul[float32])
+	t.Run("float64", testTensorMatMul[float64])
+}
+
+func testTensorMatMul[T float.DType](t *testing.T) {
+	a := Var(mat.NewVecDense([]T{1, 2, 3, 4, 5, 6, 7, 8})).WithGrad(true)
+	b := Var(mat.NewVecDense([]T{1, 0, 0, 1, 2, 1, 1, 2})).WithGrad(true)
+
+	ta := NewTensor(a, 2, 2, 2)
+	tb := NewTensor(b, 2, 2, 2)
+
+	y := TensorMatMul(ta, tb)
+	assert.Equal(t, []int{2, 2, 2}, y.Shape())
+	assert.Equal(t, []T{1, 2, 3, 4, 16, 17, 22, 23}, mat.Data[T](y.Node().Value()))
+
+	Backward(y.Node(), mat.NewDense(4, 2, []T{1, 1, 1, 1, 1, 1, 1, 1}))
+	assert.Equal(t, []T{1, 1, 1, 1, 3, 3, 3, 3}, mat.Data[T](a.Grad()))
+	assert.Equal(t, []T{4, 4, 6, 6, 12, 12, 14, 14}, mat.Data[T](b.Grad()))
+
+	assert.Panics(t, func() { TensorMatMul(ta, NewTensor(b, 2, 4)) })
+	assert.Panics(t, func() { TensorMatMul(ta, NewTensor(b, 2, 4, 1)) })
+}
+
+func TestTensorConv(t *testing.T) {
+	t.Run("float32", testTensorConv[float32])
+	t.Run("float64", testTensorConv[float64])
+}
+
+func testTensorConv[T float.DType](t *testing.T) {
+	x := Var(mat.NewVecDense([]T{1, 2, 3, 4})).WithGrad(true)
+	w := Var(mat.NewVecDense([]T{1, 1, 1, -1})).WithGrad(true)
+
+	y := TensorConv1D(NewTensor(x, 1, 4), NewTensor(w, 2, 1, 2), fn.ConvConfig{Padding: []int{1}})
+	assert.Equal(t, []int{2, 5}, y.Shape())
+	assert.Equal(t, []T{1, 3, 5, 7, 4, -1, -1, -1, -1, 4}, mat.Data[T](y.Node().Value()))
+
+	y = TensorConv2D(NewTensor(x, 1, 2, 2), NewTensor(w, 1, 1, 2, 2), fn.ConvConfig{})
+	assert.Equal(t, []int{1, 1, 1}, y.Shape())
+	assert.Equal(t, []T{2}, mat.Data[T](y.Node().Value()))
+
+	Backward(y.Node())
+	assert.Equal(t, []T{1, 1, 1, -1}, mat.Data[T](x.Grad()))
+	assert.Equal(t, []T{1, 2, 3, 4}, mat.Data[T](w.Grad()))
+
+	assert.Panics(t, func() { TensorConv2D(NewTensor(x, 1, 4), NewTensor(w, 2, 1, 2), fn.ConvConfig{}) })
+}
+
+func TestTensorConvTranspose(t *testing.T) {
+	t.Run("float32", testTensorConvTranspose[float32])
+	t.Run("float64", testTensorConvTranspose[float64])
+}
+
+func testTensorConvTranspose[T float.DType](t *testing.T) {
+	x := Var(mat.NewVecDense([]T{1, 2})).WithGrad(true)
+	w := Var(mat.NewVecDense([]T{1, 1, 1, -1})).WithGrad(true)
+
+	y := TensorConvTranspose1D(NewTensor(x, 1, 2), NewTensor(w, 1, 2, 2), fn.ConvTransposeConfig{Stride: []int{2}})
+	assert.Equal(t, []int{2, 4}, y.Shape())
+	assert.Equal(t, []T{1, 1, 2, 2, 1, -1, 2, -2}, mat.Data[T](y.Node().Value()))
+
+	y = TensorConvTranspose2D(NewTensor(x, 2, 1, 1), NewTensor(w, 2, 1, 1, 2), fn.ConvTransposeConfig{})
+	assert.Equal(t, []int{1, 1, 2}, y.Shape())
+	assert.Equal(t, []T{3, -1}, mat.Data[T](y.Node().Value()))
+
+	Backward(y.Node())
+	assert.Equal(t, []T{2, 0}, mat.Data[T](x.Grad()))
+	assert.Equal(t, []T{1, 1, 2, 2}, mat.Data[T](w.Grad()))
+
+	assert.Panics(t, func() { TensorConvTranspose2D(NewTensor(x, 1, 2), NewTensor(w, 1, 2, 2), fn.ConvTransposeConfig{}) })
+}
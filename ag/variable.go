@@ -15,8 +15,10 @@ import (
 )
 
 var (
-	_ fn.Operand = &Variable{}
-	_ Node       = &Variable{}
+	_ fn.Operand          = &Variable{}
+	_ Node                = &Variable{}
+	_ GradNodeAccumulator = &Variable{}
+	_ GradHookRegistry    = &Variable{}
 )
 
 // Variable is a simple type of Node, primarily consisting of a value and
@@ -24,8 +26,14 @@ var (
 type Variable struct {
 	value        mat.Matrix
 	grad         mat.Matrix
+	gradNode     Node
 	gradMu       sync.RWMutex
+	gradHooks    GradHooks
+	tangent      mat.Matrix
+	executor     Executor
 	requiresGrad bool
+	inference    bool
+	scope        *InferenceScope
 	name         string
 	// It's primarily useful for later associating a correct time-step
 	// to this variable, if needed for truncated backpropagation.
@@ -60,6 +68,37 @@ func (r *Variable) WithGrad(value bool) *Variable {
 	return r
 }
 
+// WithTangent sets the tangent of the variable, that is the direction along
+// which the derivatives of the dependent operators are computed by
+// forward-mode differentiation (see Operator.Tangent).
+func (r *Variable) WithTangent(value mat.Matrix) *Variable {
+	if value != nil && !mat.SameDims(value, r.value) {
+		panic("ag: the tangent must have the same shape of the variable's value")
+	}
+	r.tangent = value
+	return r
+}
+
+// Tangent returns the tangent of the variable, or nil if it is not set.
+func (r *Variable) Tangent() mat.Matrix {
+	return r.tangent
+}
+
+// WithExecutor sets the Executor of the operators which depend on the
+// variable, overriding the global executor (see SetExecutor).
+func (r *Variable) WithExecutor(e Executor) *Variable {
+	r.executor = e
+	return r
+}
+
+// WithInference sets whether the operators which depend on the variable
+// are computed in inference mode, regardless of the global mode (see
+// NoGrad).
+func (r *Variable) WithInference(value bool) *Variable {
+	r.inference = value
+	return r
+}
+
 // WithName sets the variable's name.
 func (r *Variable) WithName(value string) *Variable {
 	r.name = value
@@ -94,10 +133,14 @@ func (r *Variable) Grad() mat.Matrix {
 }
 
 // AccGrad accumulates the gradients into the Variable.
+// The gradients are first passed through the registered hooks, if any.
 func (r *Variable) AccGrad(grad mat.Matrix) {
 	if !r.requiresGrad {
 		return
 	}
+	if grad = r.gradHooks.Apply(grad); grad == nil {
+		return
+	}
 	r.gradMu.Lock()
 	defer r.gradMu.Unlock()
 	if r.grad == nil {
@@ -107,6 +150,44 @@ func (r *Variable) AccGrad(grad mat.Matrix) {
 	r.grad.AddInPlace(grad)
 }
 
+// RegisterGradHook registers a GradHook, which is called each time some
+// gradients are accumulated into the Variable.
+func (r *Variable) RegisterGradHook(hook GradHook) RemoveGradHookFunc {
+	return r.gradHooks.Register(hook)
+}
+
+// AccGradNode accumulates the gradients, expressed as a Node, into the
+// Variable. The value of gx is also accumulated as with AccGrad.
+// The gradients are first passed through the registered hooks, if any (see
+// GradHooks.ApplyNode).
+func (r *Variable) AccGradNode(gx Node) {
+	if !r.requiresGrad {
+		return
+	}
+	if gx = r.gradHooks.ApplyNode(gx); gx == nil {
+		return
+	}
+	r.gradMu.Lock()
+	defer r.gradMu.Unlock()
+	if r.gradNode == nil {
+		r.gradNode = gx
+	} else {
+		r.gradNode = Add(r.gradNode, gx)
+	}
+	if r.grad == nil {
+		r.grad = gx.Value().Clone()
+		return
+	}
+	r.grad.AddInPlace(gx.Value())
+}
+
+// GradNode returns the gradients accumulated with AccGradNode, or nil.
+func (r *Variable) GradNode() Node {
+	r.gradMu.RLock()
+	defer r.gradMu.RUnlock()
+	return r.gradNode
+}
+
 // HasGrad reports whether there are accumulated gradients.
 func (r *Variable) HasGrad() bool {
 	return r.Grad() != nil
@@ -117,7 +198,7 @@ func (r *Variable) RequiresGrad() bool {
 	return r.requiresGrad
 }
 
-// ZeroGrad zeroes the gradients, setting the value of Grad to nil.
+// ZeroGrad zeroes the gradients, setting the value of Grad and GradNode to nil.
 func (r *Variable) ZeroGrad() {
 	if r.Grad() == nil {
 		return
@@ -126,4 +207,5 @@ func (r *Variable) ZeroGrad() {
 	defer r.gradMu.Unlock()
 	mat.ReleaseMatrix(r.grad)
 	r.grad = nil
+	r.gradNode = nil
 }
@@ -137,6 +137,13 @@ func testVariableGradients[T float.DType](t *testing.T) {
 		assert.False(t, v.HasGrad())
 	})
 
+	t.Run("with gradients of different dimensions", func(t *testing.T) {
+		v := Var(mat.NewVecDense([]T{1, 2, 3})).WithGrad(true)
+		v.AccGrad(mat.NewVecDense([]T{1, 1, 1}))
+		assert.Panics(t, func() { v.AccGrad(mat.NewDense[T](1, 3, []T{1, 1, 1})) })
+		assert.Panics(t, func() { v.AccGrad(mat.NewScalar[T](1)) })
+	})
+
 	t.Run("with requires gradient false", func(t *testing.T) {
 		v := Var(mat.NewScalar[T](42)).WithGrad(false)
 		require.Nil(t, v.Grad())
@@ -7,11 +7,17 @@ package embeddings
 import (
 	"fmt"
 
+	"github.com/nlpodyssey/spago/ag"
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/mat/float"
 	"github.com/nlpodyssey/spago/nn"
 )
 
+var (
+	_ ag.GradHookRegistry    = &Embedding[string]{}
+	_ ag.GradNodeAccumulator = &Embedding[string]{}
+)
+
 // Embedding is an implementation of nn.Param representing embedding values.
 type Embedding[K Key] struct {
 	model *Model[K]
@@ -63,10 +69,31 @@ func (e *Embedding[_]) RequiresGrad() bool {
 }
 
 // AccGrad satisfies the interfaces nn.Param and ag.Node.
+// The gradients are first passed through the registered hooks, if any.
 func (e *Embedding[_]) AccGrad(gx mat.Matrix) {
 	e.model.accGrad(e, gx)
 }
 
+// AccGradNode satisfies the interface ag.GradNodeAccumulator.
+// The value of gx is also accumulated as with AccGrad. The gradients are
+// first passed through the registered hooks, if any.
+func (e *Embedding[_]) AccGradNode(gx ag.Node) {
+	e.model.accGradNode(e, gx)
+}
+
+// GradNode satisfies the interface ag.GradNodeAccumulator.
+func (e *Embedding[_]) GradNode() ag.Node {
+	return e.model.getGradNode(e.key)
+}
+
+// RegisterGradHook satisfies the interface ag.GradHookRegistry.
+// The hooks are kept by the Model tied to this Embedding, so they are shared
+// by all the Embedding parameters with the same key (e.g. to mask the
+// gradients of a frozen embedding).
+func (e *Embedding[_]) RegisterGradHook(hook ag.GradHook) ag.RemoveGradHookFunc {
+	return e.model.registerGradHook(e.key, hook)
+}
+
 // ZeroGrad satisfies the interfaces nn.Param and ag.Node.
 func (e *Embedding[_]) ZeroGrad() {
 	e.model.zeroGrad(e.key)
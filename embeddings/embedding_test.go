@@ -7,6 +7,7 @@ package embeddings_test
 import (
 	"testing"
 
+	"github.com/nlpodyssey/spago/ag"
 	"github.com/nlpodyssey/spago/embeddings"
 	"github.com/nlpodyssey/spago/embeddings/store/memstore"
 	"github.com/nlpodyssey/spago/mat"
@@ -185,6 +186,87 @@ func TestEmbedding_Grad(t *testing.T) {
 	assert.Nil(t, e2.Grad())
 }
 
+func TestEmbedding_RegisterGradHook(t *testing.T) {
+	type T = float32
+
+	repo := memstore.NewRepository()
+
+	conf := embeddings.Config{
+		Size:      2,
+		StoreName: "test-store",
+		Trainable: true,
+	}
+	m := embeddings.New[T, string](conf, repo)
+
+	frozen, _ := m.Embedding("frozen")
+	other, _ := m.Embedding("other")
+	frozen.ReplaceValue(mat.NewVecDense([]T{1, 2}))
+	other.ReplaceValue(mat.NewVecDense([]T{3, 4}))
+
+	remove := frozen.RegisterGradHook(func(grad mat.Matrix) mat.Matrix {
+		return nil // the gradients of the frozen embedding are discarded
+	})
+
+	x := ag.Var(mat.NewVecDense([]T{10, 100})).WithGrad(true)
+	es := m.Encode([]string{"frozen", "other"})
+	y := ag.ReduceSum(ag.Add(ag.Prod(es[0], x), ag.Prod(es[1], x)))
+	ag.Backward(y)
+
+	assert.False(t, frozen.HasGrad())
+	assert.Nil(t, frozen.Grad())
+	mattest.AssertMatrixEquals(t, mat.NewVecDense([]T{10, 100}), other.Grad())
+	mattest.AssertMatrixEquals(t, mat.NewVecDense([]T{4, 6}), x.Grad())
+	assert.Equal(t, 1, m.CountEmbeddingsWithGrad())
+
+	// The hooks are shared by all the parameters with the same key.
+	sameKey, _ := m.Embedding("frozen")
+	sameKey.AccGrad(mat.NewVecDense([]T{1, 1}))
+	assert.False(t, frozen.HasGrad())
+
+	remove()
+	sameKey.AccGrad(mat.NewVecDense([]T{1, 1}))
+	mattest.AssertMatrixEquals(t, mat.NewVecDense([]T{1, 1}), frozen.Grad())
+}
+
+func TestEmbedding_GradNode(t *testing.T) {
+	type T = float32
+
+	repo := memstore.NewRepository()
+
+	conf := embeddings.Config{
+		Size:      2,
+		StoreName: "test-store",
+		Trainable: true,
+	}
+	m := embeddings.New[T, string](conf, repo)
+
+	e, _ := m.Embedding("e")
+	frozen, _ := m.Embedding("frozen")
+	e.ReplaceValue(mat.NewVecDense([]T{1, 2}))
+	frozen.ReplaceValue(mat.NewVecDense([]T{3, 4}))
+	frozen.RegisterGradHook(func(grad mat.Matrix) mat.Matrix {
+		return nil // the gradients of the frozen embedding are discarded
+	})
+
+	x := ag.Var(mat.NewVecDense([]T{3, 4}))
+	es := m.Encode([]string{"e", "frozen"})
+	ag.BackwardGraph(ag.ReduceSum(ag.Add(ag.Square(ag.Prod(es[0], x)), es[1])))
+
+	// dy/de = 2 e x^2
+	ge := ag.GradNode(e)
+	require.NotNil(t, ge)
+	mattest.AssertMatrixEquals(t, mat.NewVecDense([]T{18, 64}), ge.Value())
+	mattest.AssertMatrixEquals(t, mat.NewVecDense([]T{18, 64}), e.Grad())
+	assert.Nil(t, ag.GradNode(frozen))
+	assert.False(t, frozen.HasGrad())
+
+	// The gradients can be differentiated again: d(sum(2 e x^2))/de = 2 x^2
+	e.ZeroGrad()
+	assert.Nil(t, ag.GradNode(e))
+	ag.Backward(ag.ReduceSum(ge))
+	mattest.AssertMatrixEquals(t, mat.NewVecDense([]T{18, 32}), e.Grad())
+}
+
 func TestEmbedding_RequiresGrad(t *testing.T) {
 	t.Run("with Trainable model", func(t *testing.T) {
 		repo := memstore.NewRepository()
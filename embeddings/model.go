@@ -69,7 +69,14 @@ type Model[K Key] struct {
 	// gradient value; instead the Model provides private methods allowing
 	// reading and writing gradients by key, which are stored here.
 	grads map[string]mat.Matrix
-	mu    sync.RWMutex
+	// gradNodes are the gradients expressed as nodes, accumulated by
+	// ag.BackwardGraph. They are kept and cleared along with grads.
+	gradNodes map[string]ag.Node
+	// gradHooks are the gradient hooks registered on the Embedding
+	// parameters, by key. Unlike the gradients, they are not cleared by
+	// ZeroGrad or ClearEmbeddingsWithGrad.
+	gradHooks map[string]*ag.GradHooks
+	mu        sync.RWMutex
 }
 
 func init() {
@@ -204,6 +211,7 @@ func (m *Model[_]) ClearEmbeddingsWithGrad() {
 	defer m.mu.Unlock()
 
 	m.grads = nil
+	m.gradNodes = nil
 	m.embeddingsWithGrad = nil
 }
 
@@ -249,14 +257,67 @@ func (m *Model[K]) getGrad(key K) (grad mat.Matrix, exists bool) {
 }
 
 func (m *Model[K]) accGrad(e *Embedding[K], gx mat.Matrix) {
-	if !m.Trainable {
+	if !m.Trainable || gx == nil {
 		return
 	}
 	key := stringifyKey(e.key)
 
+	if hooks := m.getGradHooks(key); hooks != nil {
+		if gx = hooks.Apply(gx); gx == nil {
+			return
+		}
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.addGrad(e, key, gx)
+}
+
+func (m *Model[K]) accGradNode(e *Embedding[K], gx ag.Node) {
+	if !m.Trainable || gx == nil {
+		return
+	}
+	key := stringifyKey(e.key)
+
+	if hooks := m.getGradHooks(key); hooks != nil {
+		if gx = hooks.ApplyNode(gx); gx == nil {
+			return
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if prev, exists := m.gradNodes[key]; exists {
+		m.gradNodes[key] = ag.Add(prev, gx)
+	} else {
+		if m.gradNodes == nil {
+			m.gradNodes = make(map[string]ag.Node)
+		}
+		m.gradNodes[key] = gx
+	}
+	m.addGrad(e, key, gx.Value())
+}
 
+func (m *Model[K]) getGradNode(key K) ag.Node {
+	if !m.Trainable {
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.gradNodes[stringifyKey(key)]
+}
+
+func (m *Model[K]) getGradHooks(key string) *ag.GradHooks {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.gradHooks[key]
+}
+
+// addGrad adds gx to the gradients of the Embedding e with the given key.
+// It must be called while holding the lock.
+func (m *Model[K]) addGrad(e *Embedding[K], key string, gx mat.Matrix) {
 	grad, exists := m.grads[key]
 	if exists {
 		grad.AddInPlace(gx)
@@ -288,5 +349,23 @@ func (m *Model[K]) zeroGrad(k K) {
 
 	mat.ReleaseMatrix(grad)
 	delete(m.grads, key)
+	delete(m.gradNodes, key)
 	delete(m.embeddingsWithGrad, key)
 }
+
+func (m *Model[K]) registerGradHook(k K, hook ag.GradHook) ag.RemoveGradHookFunc {
+	key := stringifyKey(k)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hooks, exists := m.gradHooks[key]
+	if !exists {
+		if m.gradHooks == nil {
+			m.gradHooks = make(map[string]*ag.GradHooks)
+		}
+		hooks = new(ag.GradHooks)
+		m.gradHooks[key] = hooks
+	}
+	return hooks.Register(hook)
+}
@@ -36,8 +36,9 @@ func NLL(x ag.Node, y ag.Node) ag.Node {
 // CrossEntropy implements a cross-entropy loss function.
 // x is the raw scores for each class (logits).
 // c is the index of the gold class.
+// It is computed by a single fused softmax and negative log-likelihood operator (see ag.SoftmaxCrossEntropy).
 func CrossEntropy(x ag.Node, c int) ag.Node {
-	return ag.Add(ag.Neg(ag.AtVec(x, c)), ag.LogSumExp(x))
+	return ag.SoftmaxCrossEntropy(x, c)
 }
 
 // WeightedCrossEntropy implements a weighted cross-entropy loss function.
@@ -87,6 +88,29 @@ func Perplexity(x ag.Node, c int) ag.Node {
 	return ag.Exp(CrossEntropy(x, c))
 }
 
+// EntmaxLoss implements the Fenchel-Young loss of the alpha-entmax
+// transformation (see ag.Entmax), which is exactly zero when the gold class
+// is separated from the others by a large enough margin.
+// x is the raw scores for each class (logits).
+// c is the index of the gold class.
+// alpha is the sparsity parameter (alpha > 1): 1.5 is a common choice, 2 is
+// the sparsemax loss, and values approaching 1 approach the CrossEntropy loss.
+func EntmaxLoss(x ag.Node, c int, alpha float64) ag.Node {
+	return ag.EntmaxLoss(x, c, alpha)
+}
+
+// SparseMaxLoss implements the Fenchel-Young loss of the sparsemax
+// transformation, that is the EntmaxLoss with alpha = 2.
+// x is the raw scores for each class (logits).
+// c is the index of the gold class.
+//
+// Unlike ag.SparseMaxLoss, which returns a vector with the opposite of the
+// loss for each class, like a log-softmax, it returns the loss of the gold
+// class as a scalar: SparseMaxLoss(x, c) = -ag.SparseMaxLoss(x)[c].
+func SparseMaxLoss(x ag.Node, c int) ag.Node {
+	return EntmaxLoss(x, c, 2)
+}
+
 // ZeroOneQuantization is a loss function that is minimized when each component
 // of x satisfies x(i) ≡ [x]i ∈ {0, 1}.
 func ZeroOneQuantization(x ag.Node) ag.Node {
@@ -65,6 +65,60 @@ func testCrossEntropyLoss[T float.DType](t *testing.T) {
 	assert.InDeltaSlice(t, []T{0.0, 0.1, -0.8, 0.7}, x.Grad().Data(), 1.0e-6)
 }
 
+func TestCrossEntropyLoss_LargeLogits(t *testing.T) {
+	t.Run("float32", testCrossEntropyLossLargeLogits[float32])
+	t.Run("float64", testCrossEntropyLossLargeLogits[float64])
+}
+
+func testCrossEntropyLossLargeLogits[T float.DType](t *testing.T) {
+	x := ag.Var(mat.NewVecDense([]T{800, 0, -800})).WithGrad(true)
+	loss := CrossEntropy(x, 2)
+
+	assert.InDelta(t, 1600, loss.Value().Scalar().F64(), 1.0e-3)
+
+	ag.Backward(loss)
+
+	assert.InDeltaSlice(t, []T{1, 0, -1}, x.Grad().Data(), 1.0e-6)
+}
+
+func TestEntmaxLoss(t *testing.T) {
+	t.Run("float32", testEntmaxLoss[float32])
+	t.Run("float64", testEntmaxLoss[float64])
+}
+
+func testEntmaxLoss[T float.DType](t *testing.T) {
+	x := ag.Var(mat.NewVecDense([]T{-0.5, 0, 0.6, 0.9})).WithGrad(true)
+	loss := EntmaxLoss(x, 2, 1.5)
+
+	assertScalarEqualApprox(t, 0.5766, loss.Value())
+
+	ag.Backward(loss)
+
+	assert.InDeltaSlice(t, []T{0.002076, 0.087359, -0.645302, 0.555867}, x.Grad().Data(), 1.0e-6)
+}
+
+func TestSparseMaxLoss(t *testing.T) {
+	t.Run("float32", testSparseMaxLoss[float32])
+	t.Run("float64", testSparseMaxLoss[float64])
+}
+
+func testSparseMaxLoss[T float.DType](t *testing.T) {
+	x := ag.Var(mat.NewVecDense([]T{-0.5, 0, 0.6, 0.9})).WithGrad(true)
+	loss := SparseMaxLoss(x, 2)
+
+	assertScalarEqualApprox(t, 0.4225, loss.Value())
+
+	ag.Backward(loss)
+
+	assert.InDeltaSlice(t, []T{0, 0, -0.65, 0.65}, x.Grad().Data(), 1.0e-6)
+
+	// the opposite of the element of the gold class of ag.SparseMaxLoss
+	for c := 0; c < 4; c++ {
+		expected := ag.Neg(ag.AtVec(ag.SparseMaxLoss(x), c))
+		assert.InDelta(t, expected.Value().Scalar().F64(), SparseMaxLoss(x, c).Value().Scalar().F64(), 1.0e-6)
+	}
+}
+
 func TestWeightedCrossEntropyLoss(t *testing.T) {
 	t.Run("float32", testWeightedCrossEntropyLoss[float32])
 	t.Run("float64", testWeightedCrossEntropyLoss[float64])
@@ -0,0 +1,114 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/internal/f32"
+	"github.com/nlpodyssey/spago/mat/internal/f64"
+)
+
+// BatchMul performs the matrix multiplication of each pair of matrices
+// from the stacks a and b, in a single call.
+//
+// A stack contains batch matrices of the same size, one on top of the
+// other: the stack a, with dimensions (batch·n)×k, is the representation of
+// a tensor with shape [batch, n, k] (see Tensor.Matrix). If transA is true,
+// each matrix of a is transposed before the multiplication, and the same
+// applies to b with transB.
+//
+// The result is the stack of the batch products, with dimensions
+// (batch·n)×m, of the same type of a. It panics if the dimensions of the
+// matrices are incompatible.
+func BatchMul(a, b Matrix, batch int, transA, transB bool) Matrix {
+	if batch <= 0 {
+		panic(fmt.Sprintf("mat: invalid batch size %d", batch))
+	}
+	if a.Rows()%batch != 0 || b.Rows()%batch != 0 {
+		panic(fmt.Sprintf("mat: the rows of the matrices are not divisible by the batch size %d", batch))
+	}
+	s := batchMulSizes{aRows: a.Rows() / batch, aCols: a.Columns(), bRows: b.Rows() / batch, bCols: b.Columns()}
+	n, k := s.aRows, s.aCols
+	if transA {
+		n, k = k, n
+	}
+	k2, m := s.bRows, s.bCols
+	if transB {
+		k2, m = m, k2
+	}
+	if k != k2 {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	if d, ok := a.(*Dense[float32]); ok {
+		return batchMul(d.data, Data[float32](b), batch, s, transA, transB)
+	}
+	return batchMul(Data[float64](a), Data[float64](b), batch, s, transA, transB)
+}
+
+// batchMulSizes are the dimensions of each matrix of the stacks
+// multiplied by BatchMul.
+type batchMulSizes struct {
+	aRows, aCols int
+	bRows, bCols int
+}
+
+func batchMul[T float.DType](a, b []T, batch int, s batchMulSizes, transA, transB bool) *Dense[T] {
+	aSize, bSize := s.aRows*s.aCols, s.bRows*s.bCols
+	n, k := s.aRows, s.aCols
+	if transA {
+		n, k = k, n
+	}
+	m := s.bCols
+	if transB {
+		m = s.bRows
+	}
+
+	var aBuf, bBuf []T
+	if transA {
+		aBuf = make([]T, aSize)
+	}
+	if transB {
+		bBuf = make([]T, bSize)
+	}
+
+	out := densePool[T]().GetEmpty(batch*n, m)
+	for i := 0; i < batch; i++ {
+		ai := a[i*aSize : (i+1)*aSize]
+		if transA {
+			transposeInto(aBuf, ai, s.aRows, s.aCols)
+			ai = aBuf
+		}
+		bi := b[i*bSize : (i+1)*bSize]
+		if transB {
+			transposeInto(bBuf, bi, s.bRows, s.bCols)
+			bi = bBuf
+		}
+		matrixMul(n, k, m, ai, bi, out.data[i*n*m:(i+1)*n*m])
+	}
+	return out
+}
+
+// transposeInto stores into dst the transpose of the rows×cols matrix src.
+func transposeInto[T float.DType](dst, src []T, rows, cols int) {
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			dst[j*rows+i] = src[i*cols+j]
+		}
+	}
+}
+
+// matrixMul computes the matrix-matrix multiplication c += a * b.
+func matrixMul[T float.DType](aRows, aCols, bCols int, a, b, c []T) {
+	switch any(T(0)).(type) {
+	case float32:
+		f32.MatrixMul(aRows, aCols, bCols, any(a).([]float32), any(b).([]float32), any(c).([]float32))
+	case float64:
+		f64.MatrixMul(aRows, aCols, bCols, any(a).([]float64), any(b).([]float64), any(c).([]float64))
+	default:
+		panic(fmt.Sprintf("mat: unexpected type %T", T(0)))
+	}
+}
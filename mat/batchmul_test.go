@@ -0,0 +1,101 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchMul(t *testing.T) {
+	t.Run("float32", testBatchMul[float32])
+	t.Run("float64", testBatchMul[float64])
+}
+
+func testBatchMul[T float.DType](t *testing.T) {
+	// two 2×3 matrices
+	a := NewDense(4, 3, []T{
+		1, 2, 3,
+		4, 5, 6,
+
+		-1, 0, 2,
+		0.5, 1, -2,
+	})
+	// two 3×2 matrices
+	b := NewDense(6, 2, []T{
+		1, 0,
+		0, 1,
+		1, 1,
+
+		2, -1,
+		1, 3,
+		0, 0.5,
+	})
+
+	expected := []T{
+		4, 5,
+		10, 11,
+
+		-2, 2,
+		2, 1.5,
+	}
+
+	y := BatchMul(a, b, 2, false, false)
+	assert.Equal(t, 4, y.Rows())
+	assert.Equal(t, 2, y.Columns())
+	assert.InDeltaSlice(t, expected, Data[T](y), 1.0e-6)
+
+	// the same products, with transposed operands
+	at := stackTransposed[T](a, 2)
+	bt := stackTransposed[T](b, 2)
+	testCases := []struct {
+		a, b           Matrix
+		transA, transB bool
+	}{
+		{at, b, true, false},
+		{a, bt, false, true},
+		{at, bt, true, true},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("transA %v, transB %v", tc.transA, tc.transB), func(t *testing.T) {
+			y := BatchMul(tc.a, tc.b, 2, tc.transA, tc.transB)
+			require.Equal(t, 4, y.Rows())
+			require.Equal(t, 2, y.Columns())
+			assert.InDeltaSlice(t, expected, Data[T](y), 1.0e-6)
+		})
+	}
+
+	assert.Panics(t, func() { BatchMul(a, b, 0, false, false) })
+	assert.Panics(t, func() { BatchMul(a, b, 3, false, false) })
+	assert.Panics(t, func() { BatchMul(a, b, 2, true, false) })
+}
+
+// stackTransposed returns the stack of the transposed matrices of the
+// stack m.
+func stackTransposed[T float.DType](m Matrix, batch int) Matrix {
+	rows := m.Rows() / batch
+	ms := make([]Matrix, batch)
+	for i := range ms {
+		ms[i] = m.Slice(i*rows, 0, (i+1)*rows, m.Columns()).T()
+	}
+	out := ms[0]
+	for _, mi := range ms[1:] {
+		out = out.AppendRows(rowViews(mi)...)
+	}
+	return out
+}
+
+// rowViews returns the rows of m as separate matrices.
+func rowViews(m Matrix) []Matrix {
+	rows := make([]Matrix, m.Rows())
+	for i := range rows {
+		rows[i] = m.ExtractRow(i)
+	}
+	return rows
+}
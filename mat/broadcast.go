@@ -0,0 +1,124 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// BroadcastDims returns the dimensions resulting from broadcasting the
+// matrices a and b against each other, following the NumPy semantics.
+//
+// Along each dimension, the sizes of the two matrices must be either equal,
+// or one of them must be 1, in which case the matrix is virtually repeated
+// along that dimension. For example, a scalar (1×1) can be broadcast to any
+// matrix, a row vector (1×c) to a matrix with c columns, and a column vector
+// (r×1) to a matrix with r rows.
+//
+// The last returned value reports whether the matrices are compatible.
+func BroadcastDims(a, b Matrix) (rows, cols int, ok bool) {
+	rows, ok = broadcastDim(a.Rows(), b.Rows())
+	if !ok {
+		return 0, 0, false
+	}
+	cols, ok = broadcastDim(a.Columns(), b.Columns())
+	if !ok {
+		return 0, 0, false
+	}
+	return rows, cols, true
+}
+
+func broadcastDim(a, b int) (int, bool) {
+	switch {
+	case a == b:
+		return a, true
+	case a == 1:
+		return b, true
+	case b == 1:
+		return a, true
+	default:
+		return 0, false
+	}
+}
+
+// BroadcastTo returns a new matrix, of the same type of m, with the given
+// dimensions, repeating the values of m along the dimensions of size 1.
+// It panics if m cannot be broadcast to the given dimensions.
+func BroadcastTo(m Matrix, rows, cols int) Matrix {
+	if !canBroadcastTo(m, rows, cols) {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	mRows, mCols := m.Rows(), m.Columns()
+	src := m.Data().F64()
+	dst := make([]float64, rows*cols)
+	for i := 0; i < rows; i++ {
+		si := i % mRows * mCols
+		for j := 0; j < cols; j++ {
+			dst[i*cols+j] = src[si+j%mCols]
+		}
+	}
+	return m.NewMatrix(rows, cols, float.SliceInterface(dst))
+}
+
+// SumTo returns a new matrix, of the same type of m, with the given
+// dimensions, summing the values of m along the dimensions which are
+// reduced to size 1.
+//
+// It is the inverse operation of BroadcastTo, allowing to reduce the
+// gradients of a broadcast matrix. It panics if the dimensions of m cannot
+// be reduced to the given dimensions.
+func SumTo(m Matrix, rows, cols int) Matrix {
+	if !canSumTo(m, rows, cols) {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	mCols := m.Columns()
+	src := m.Data().F64()
+	dst := make([]float64, rows*cols)
+	for i := 0; i < m.Rows(); i++ {
+		r := i % rows
+		for j := 0; j < mCols; j++ {
+			dst[r*cols+j%cols] += src[i*mCols+j]
+		}
+	}
+	return m.NewMatrix(rows, cols, float.SliceInterface(dst))
+}
+
+// canBroadcastTo reports whether m can be broadcast to the given dimensions.
+func canBroadcastTo(m Matrix, rows, cols int) bool {
+	return (m.Rows() == rows || m.Rows() == 1) && (m.Columns() == cols || m.Columns() == 1)
+}
+
+// canSumTo reports whether the dimensions of m can be reduced to the given
+// dimensions.
+func canSumTo(m Matrix, rows, cols int) bool {
+	return (rows == m.Rows() || rows == 1) && (cols == m.Columns() || cols == 1)
+}
+
+// broadcast returns a new matrix, whose elements are the result of the
+// function f applied to the elements of the receiver and of the other
+// matrix, broadcasting both of them to the resulting dimensions.
+func (d *Dense[T]) broadcast(other Matrix, f func(a, b T) T) *Dense[T] {
+	rows, cols, ok := BroadcastDims(d, other)
+	if !ok {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	out := NewEmptyDense[T](rows, cols)
+	broadcastInto(out, d, other, f)
+	return out
+}
+
+// broadcastInto sets each element of out to f(a, b), broadcasting a and b
+// to the dimensions of out.
+func broadcastInto[T float.DType](out, a *Dense[T], b Matrix, f func(a, b T) T) {
+	bData := Data[T](b)
+	aCols, bCols := a.cols, b.Columns()
+	aRows, bRows := a.rows, b.Rows()
+	for i := 0; i < out.rows; i++ {
+		ai, bi := i%aRows*aCols, i%bRows*bCols
+		for j := 0; j < out.cols; j++ {
+			out.data[i*out.cols+j] = f(a.data[ai+j%aCols], bData[bi+j%bCols])
+		}
+	}
+}
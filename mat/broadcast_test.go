@@ -0,0 +1,134 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastDims(t *testing.T) {
+	testCases := []struct {
+		a, b       [2]int
+		rows, cols int
+		ok         bool
+	}{
+		{[2]int{2, 3}, [2]int{2, 3}, 2, 3, true},
+		{[2]int{2, 3}, [2]int{1, 3}, 2, 3, true},
+		{[2]int{2, 1}, [2]int{2, 3}, 2, 3, true},
+		{[2]int{1, 1}, [2]int{2, 3}, 2, 3, true},
+		{[2]int{3, 1}, [2]int{1, 4}, 3, 4, true},
+		{[2]int{2, 3}, [2]int{3, 2}, 0, 0, false},
+		{[2]int{2, 3}, [2]int{2, 2}, 0, 0, false},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v, %v", tc.a, tc.b), func(t *testing.T) {
+			a := NewEmptyDense[float64](tc.a[0], tc.a[1])
+			b := NewEmptyDense[float64](tc.b[0], tc.b[1])
+			rows, cols, ok := BroadcastDims(a, b)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.rows, rows)
+			assert.Equal(t, tc.cols, cols)
+		})
+	}
+}
+
+func TestBroadcastTo(t *testing.T) {
+	t.Run("float32", testBroadcastTo[float32])
+	t.Run("float64", testBroadcastTo[float64])
+}
+
+func testBroadcastTo[T float.DType](t *testing.T) {
+	row := NewDense[T](1, 3, []T{1, 2, 3})
+	y := BroadcastTo(row, 2, 3)
+	assertDenseDims(t, 2, 3, y.(*Dense[T]))
+	assert.Equal(t, []T{1, 2, 3, 1, 2, 3}, Data[T](y))
+
+	col := NewVecDense[T]([]T{1, 2})
+	y = BroadcastTo(col, 2, 3)
+	assert.Equal(t, []T{1, 1, 1, 2, 2, 2}, Data[T](y))
+
+	y = BroadcastTo(NewScalar[T](5), 2, 2)
+	assert.Equal(t, []T{5, 5, 5, 5}, Data[T](y))
+
+	require.Panics(t, func() { BroadcastTo(row, 2, 4) })
+}
+
+func TestSumTo(t *testing.T) {
+	t.Run("float32", testSumTo[float32])
+	t.Run("float64", testSumTo[float64])
+}
+
+func testSumTo[T float.DType](t *testing.T) {
+	m := NewDense[T](2, 3, []T{
+		1, 2, 3,
+		4, 5, 6,
+	})
+
+	y := SumTo(m, 1, 3)
+	assertDenseDims(t, 1, 3, y.(*Dense[T]))
+	assert.Equal(t, []T{5, 7, 9}, Data[T](y))
+
+	y = SumTo(m, 2, 1)
+	assertDenseDims(t, 2, 1, y.(*Dense[T]))
+	assert.Equal(t, []T{6, 15}, Data[T](y))
+
+	y = SumTo(m, 1, 1)
+	assert.Equal(t, []T{21}, Data[T](y))
+
+	y = SumTo(m, 2, 3)
+	assert.Equal(t, Data[T](m), Data[T](y))
+	assert.NotSame(t, m, y)
+
+	require.Panics(t, func() { SumTo(m, 3, 1) })
+}
+
+func TestDense_Broadcast(t *testing.T) {
+	t.Run("float32", testDenseBroadcast[float32])
+	t.Run("float64", testDenseBroadcast[float64])
+}
+
+func testDenseBroadcast[T float.DType](t *testing.T) {
+	m := NewDense[T](2, 3, []T{
+		1, 2, 3,
+		4, 5, 6,
+	})
+	row := NewDense[T](1, 3, []T{10, 20, 30})
+	col := NewVecDense[T]([]T{2, 4})
+	scalar := NewScalar[T](2)
+
+	testCases := []struct {
+		name string
+		f    func() Matrix
+		y    []T
+	}{
+		{"matrix + row", func() Matrix { return m.Add(row) }, []T{11, 22, 33, 14, 25, 36}},
+		{"row + matrix", func() Matrix { return row.Add(m) }, []T{11, 22, 33, 14, 25, 36}},
+		{"matrix - column", func() Matrix { return m.Sub(col) }, []T{-1, 0, 1, 0, 1, 2}},
+		{"column - matrix", func() Matrix { return col.Sub(m) }, []T{1, 0, -1, 0, -1, -2}},
+		{"matrix * scalar", func() Matrix { return m.Prod(scalar) }, []T{2, 4, 6, 8, 10, 12}},
+		{"matrix / column", func() Matrix { return m.Div(col) }, []T{0.5, 1, 1.5, 1, 1.25, 1.5}},
+		{"column * row", func() Matrix { return col.Prod(row) }, []T{20, 40, 60, 40, 80, 120}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			y := tc.f()
+			assertDenseDims(t, 2, 3, y.(*Dense[T]))
+			assert.InDeltaSlice(t, tc.y, Data[T](y), 1.0e-6)
+		})
+	}
+
+	t.Run("in place operations do not broadcast", func(t *testing.T) {
+		require.Panics(t, func() { m.Clone().AddInPlace(row) })
+		require.Panics(t, func() { m.Clone().SubInPlace(col) })
+		require.Panics(t, func() { m.Clone().ProdInPlace(scalar) })
+		require.Panics(t, func() { m.Clone().DivInPlace(row) })
+		require.Panics(t, func() { NewDense[T](3, 1, []T{1, 2, 3}).AddInPlace(row) })
+	})
+}
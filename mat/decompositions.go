@@ -0,0 +1,338 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"sort"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// maxJacobiSweeps is the maximum number of sweeps performed by the Jacobi
+// methods of SVD and EigenSym, which usually converge in less than ten.
+const maxJacobiSweeps = 100
+
+// qr performs the thin QR decomposition of the rows×cols matrix a, by
+// Householder reflections. The diagonal of r is non-negative.
+func qr[T float.DType](a []T, rows, cols int) (q, r *Dense[T]) {
+	k := minInt(rows, cols)
+	ra := toFloat64s(a)
+	qa := make([]float64, rows*rows)
+	for i := 0; i < rows; i++ {
+		qa[i*rows+i] = 1
+	}
+
+	v := make([]float64, rows)
+	for j := 0; j < k; j++ {
+		var norm float64
+		for i := j; i < rows; i++ {
+			norm += ra[i*cols+j] * ra[i*cols+j]
+		}
+		norm = math.Sqrt(norm)
+		if norm == 0 {
+			continue
+		}
+		alpha := -norm
+		if ra[j*cols+j] < 0 {
+			alpha = norm
+		}
+		var vNorm2 float64
+		for i := j; i < rows; i++ {
+			v[i] = ra[i*cols+j]
+			if i == j {
+				v[i] -= alpha
+			}
+			vNorm2 += v[i] * v[i]
+		}
+		if vNorm2 == 0 {
+			continue
+		}
+
+		// r = h·r, with h = I - 2·v·vᵀ / (vᵀ·v)
+		for c := j; c < cols; c++ {
+			var dot float64
+			for i := j; i < rows; i++ {
+				dot += v[i] * ra[i*cols+c]
+			}
+			f := 2 * dot / vNorm2
+			for i := j; i < rows; i++ {
+				ra[i*cols+c] -= f * v[i]
+			}
+		}
+		// q = q·h
+		for row := 0; row < rows; row++ {
+			qRow := qa[row*rows : (row+1)*rows]
+			var dot float64
+			for i := j; i < rows; i++ {
+				dot += qRow[i] * v[i]
+			}
+			f := 2 * dot / vNorm2
+			for i := j; i < rows; i++ {
+				qRow[i] -= f * v[i]
+			}
+		}
+	}
+
+	q = NewEmptyDense[T](rows, k)
+	r = NewEmptyDense[T](k, cols)
+	for i := 0; i < k; i++ {
+		sign := 1.0
+		if ra[i*cols+i] < 0 {
+			sign = -1
+		}
+		for c := i; c < cols; c++ {
+			r.data[i*cols+c] = T(sign * ra[i*cols+c])
+		}
+		for row := 0; row < rows; row++ {
+			q.data[row*k+i] = T(sign * qa[row*rows+i])
+		}
+	}
+	return q, r
+}
+
+// svd performs the thin singular value decomposition of the rows×cols
+// matrix a, by the one-sided Jacobi method. The singular values are sorted
+// in descending order.
+func svd[T float.DType](a []T, rows, cols int) (u, s, v *Dense[T]) {
+	if rows < cols {
+		// a = u·s·vᵀ, where aᵀ = v·s·uᵀ
+		at := make([]T, len(a))
+		transposeInto(at, a, rows, cols)
+		v, s, u = svd(at, cols, rows)
+		return u, s, v
+	}
+
+	ua := toFloat64s(a)
+	va := make([]float64, cols*cols)
+	for i := 0; i < cols; i++ {
+		va[i*cols+i] = 1
+	}
+
+	for sweep := 0; sweep < maxJacobiSweeps; sweep++ {
+		converged := true
+		for p := 0; p < cols-1; p++ {
+			for q := p + 1; q < cols; q++ {
+				var alpha, beta, gamma float64
+				for i := 0; i < rows; i++ {
+					up, uq := ua[i*cols+p], ua[i*cols+q]
+					alpha += up * up
+					beta += uq * uq
+					gamma += up * uq
+				}
+				if math.Abs(gamma) <= 1e-15*math.Sqrt(alpha*beta) {
+					continue
+				}
+				converged = false
+				c, s := jacobiRotation(alpha, beta, gamma)
+				rotateColumns(ua, rows, cols, p, q, c, s)
+				rotateColumns(va, cols, cols, p, q, c, s)
+			}
+		}
+		if converged {
+			break
+		}
+	}
+
+	values := make([]float64, cols)
+	for j := range values {
+		var norm float64
+		for i := 0; i < rows; i++ {
+			norm += ua[i*cols+j] * ua[i*cols+j]
+		}
+		values[j] = math.Sqrt(norm)
+	}
+	order := sortedIndices(values, true)
+
+	u = NewEmptyDense[T](rows, cols)
+	s = NewEmptyDense[T](cols, 1)
+	v = NewEmptyDense[T](cols, cols)
+	uCols := make([][]float64, 0, cols)
+	var nullColumns []int
+	for k, j := range order {
+		s.data[k] = T(values[j])
+		for i := 0; i < cols; i++ {
+			v.data[i*cols+k] = T(va[i*cols+j])
+		}
+		if values[j] <= 1e-12*values[order[0]] {
+			nullColumns = append(nullColumns, k)
+			continue
+		}
+		col := make([]float64, rows)
+		for i := range col {
+			col[i] = ua[i*cols+j] / values[j]
+		}
+		uCols = append(uCols, col)
+		setColumn(u.data, cols, k, col)
+	}
+	// the left singular vectors of the null singular values are any
+	// orthonormal completion of the others
+	for _, k := range nullColumns {
+		col := orthonormalComplement(uCols, rows)
+		uCols = append(uCols, col)
+		setColumn(u.data, cols, k, col)
+	}
+	return u, s, v
+}
+
+// eigenSym performs the eigendecomposition of the symmetric n×n matrix a,
+// by the cyclic Jacobi method. The eigenvalues are sorted in ascending
+// order, and the eigenvectors are the columns of vectors.
+func eigenSym[T float.DType](a []T, n int) (values, vectors *Dense[T]) {
+	m := toFloat64s(a)
+	va := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		va[i*n+i] = 1
+	}
+
+	for sweep := 0; sweep < maxJacobiSweeps; sweep++ {
+		var off, total float64
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				v := m[i*n+j] * m[i*n+j]
+				total += v
+				if i != j {
+					off += v
+				}
+			}
+		}
+		if off <= 1e-30*total {
+			break
+		}
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				apq := m[p*n+q]
+				if apq == 0 {
+					continue
+				}
+				c, s := jacobiRotation(m[p*n+p], m[q*n+q], apq)
+				rotateColumns(m, n, n, p, q, c, s)
+				rotateRows(m, n, p, q, c, s)
+				rotateColumns(va, n, n, p, q, c, s)
+			}
+		}
+	}
+
+	diag := make([]float64, n)
+	for i := range diag {
+		diag[i] = m[i*n+i]
+	}
+	order := sortedIndices(diag, false)
+
+	values = NewEmptyDense[T](n, 1)
+	vectors = NewEmptyDense[T](n, n)
+	for k, j := range order {
+		values.data[k] = T(diag[j])
+		for i := 0; i < n; i++ {
+			vectors.data[i*n+k] = T(va[i*n+j])
+		}
+	}
+	return values, vectors
+}
+
+// jacobiRotation returns the cosine and the sine of the rotation which
+// annihilates the off-diagonal element gamma of the symmetric 2×2 matrix
+// [alpha, gamma; gamma, beta].
+func jacobiRotation(alpha, beta, gamma float64) (c, s float64) {
+	zeta := (beta - alpha) / (2 * gamma)
+	t := 1 / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+	if zeta < 0 {
+		t = -t
+	}
+	c = 1 / math.Sqrt(1+t*t)
+	return c, c * t
+}
+
+// rotateColumns applies the rotation (c, s) to the columns p and q of the
+// rows×cols matrix m.
+func rotateColumns(m []float64, rows, cols, p, q int, c, s float64) {
+	for i := 0; i < rows; i++ {
+		mp, mq := m[i*cols+p], m[i*cols+q]
+		m[i*cols+p] = c*mp - s*mq
+		m[i*cols+q] = s*mp + c*mq
+	}
+}
+
+// rotateRows applies the rotation (c, s) to the rows p and q of the n×n
+// matrix m.
+func rotateRows(m []float64, n, p, q int, c, s float64) {
+	rowP, rowQ := m[p*n:(p+1)*n], m[q*n:(q+1)*n]
+	for k := range rowP {
+		mp, mq := rowP[k], rowQ[k]
+		rowP[k] = c*mp - s*mq
+		rowQ[k] = s*mp + c*mq
+	}
+}
+
+// orthonormalComplement returns a unit vector of the given size, which is
+// orthogonal to all the given orthonormal vectors, obtained by the
+// Gram-Schmidt process from a vector of the standard basis.
+func orthonormalComplement(vs [][]float64, size int) []float64 {
+	col := make([]float64, size)
+	for e := 0; e < size; e++ {
+		for i := range col {
+			col[i] = 0
+		}
+		col[e] = 1
+		for _, v := range vs {
+			dot := v[e]
+			for i := range col {
+				col[i] -= dot * v[i]
+			}
+		}
+		var norm float64
+		for _, x := range col {
+			norm += x * x
+		}
+		if norm = math.Sqrt(norm); norm > 0.5 {
+			for i := range col {
+				col[i] /= norm
+			}
+			return col
+		}
+	}
+	return col
+}
+
+// setColumn sets the column k of the matrix, with the given number of
+// columns, to the values of col.
+func setColumn[T float.DType](data []T, cols, k int, col []float64) {
+	for i, x := range col {
+		data[i*cols+k] = T(x)
+	}
+}
+
+// sortedIndices returns the indices of the values, sorted by value in
+// ascending or descending order.
+func sortedIndices(values []float64, descending bool) []int {
+	indices := make([]int, len(values))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		if descending {
+			return values[indices[i]] > values[indices[j]]
+		}
+		return values[indices[i]] < values[indices[j]]
+	})
+	return indices
+}
+
+// toFloat64s returns a copy of the data converted to float64.
+func toFloat64s[T float.DType](data []T) []float64 {
+	out := make([]float64, len(data))
+	for i, v := range data {
+		out[i] = float64(v)
+	}
+	return out
+}
+
+// minInt returns the minimum of a and b.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
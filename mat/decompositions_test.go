@@ -0,0 +1,192 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDense_QR(t *testing.T) {
+	t.Run("float32", testDenseQR[float32])
+	t.Run("float64", testDenseQR[float64])
+}
+
+func testDenseQR[T float.DType](t *testing.T) {
+	testCases := []struct {
+		name string
+		a    *Dense[T]
+	}{
+		{"square", NewDense(3, 3, []T{
+			12, -51, 4,
+			6, 167, -68,
+			-4, 24, -41,
+		})},
+		{"tall", NewDense(4, 2, []T{
+			1, 2,
+			-3, 4,
+			5, 6,
+			7, -8,
+		})},
+		{"wide", NewDense(2, 3, []T{
+			1, 2, 3,
+			-4, 5, 6,
+		})},
+		{"negative pivot", NewDense(2, 2, []T{
+			-1, 2,
+			0, 3,
+		})},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, cols := tc.a.Dims()
+			k := minInt(rows, cols)
+			q, r := tc.a.QR()
+			assertDenseDims(t, rows, k, q.(*Dense[T]))
+			assertDenseDims(t, k, cols, r.(*Dense[T]))
+
+			assert.InDeltaSlice(t, tc.a.data, Data[T](q.Mul(r)), 1.0e-4)
+			assertOrthonormalColumns[T](t, q)
+			for i := 0; i < k; i++ {
+				assert.GreaterOrEqual(t, r.ScalarAt(i, i).F64(), 0.0)
+				for j := 0; j < i; j++ {
+					assert.Equal(t, 0.0, r.ScalarAt(i, j).F64())
+				}
+			}
+		})
+	}
+}
+
+func TestDense_SVD(t *testing.T) {
+	t.Run("float32", testDenseSVD[float32])
+	t.Run("float64", testDenseSVD[float64])
+}
+
+func testDenseSVD[T float.DType](t *testing.T) {
+	testCases := []struct {
+		name string
+		a    *Dense[T]
+		s    []T
+	}{
+		{"diagonal", NewDense(3, 3, []T{
+			2, 0, 0,
+			0, -5, 0,
+			0, 0, 3,
+		}), []T{5, 3, 2}},
+		{"tall", NewDense(3, 2, []T{
+			3, 0,
+			4, 0,
+			0, 1,
+		}), []T{5, 1}},
+		{"wide", NewDense(2, 3, []T{
+			3, 2, 2,
+			2, 3, -2,
+		}), []T{5, 3}},
+		{"rank-deficient", NewDense(3, 3, []T{
+			1, 2, 3,
+			2, 4, 6,
+			1, 1, 1,
+		}), nil},
+		{"zero", NewEmptyDense[T](2, 2), []T{0, 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, cols := tc.a.Dims()
+			k := minInt(rows, cols)
+			u, s, v := tc.a.SVD()
+			assertDenseDims(t, rows, k, u.(*Dense[T]))
+			assertDenseDims(t, k, 1, s.(*Dense[T]))
+			assertDenseDims(t, cols, k, v.(*Dense[T]))
+
+			sv := Data[T](s)
+			if tc.s != nil {
+				assert.InDeltaSlice(t, tc.s, sv, 1.0e-5)
+			}
+			for i := 1; i < k; i++ {
+				assert.GreaterOrEqual(t, sv[i-1], sv[i])
+			}
+			assert.InDeltaSlice(t, tc.a.data, Data[T](u.Mul(diagDense[T](sv)).Mul(v.T())), 1.0e-4)
+			assertOrthonormalColumns[T](t, u)
+			assertOrthonormalColumns[T](t, v)
+		})
+	}
+
+	t.Run("rank-deficient values", func(t *testing.T) {
+		_, s, _ := testCases[3].a.SVD()
+		assert.InDelta(t, 0, s.ScalarAtVec(2).F64(), 1.0e-5)
+	})
+}
+
+func TestDense_EigenSym(t *testing.T) {
+	t.Run("float32", testDenseEigenSym[float32])
+	t.Run("float64", testDenseEigenSym[float64])
+}
+
+func testDenseEigenSym[T float.DType](t *testing.T) {
+	testCases := []struct {
+		name   string
+		a      *Dense[T]
+		values []T
+	}{
+		{"2x2", NewDense(2, 2, []T{
+			2, 1,
+			1, 2,
+		}), []T{1, 3}},
+		{"3x3", NewDense(3, 3, []T{
+			2, -1, 0,
+			-1, 2, -1,
+			0, -1, 2,
+		}), []T{0.585786, 2, 3.414214}},
+		{"indefinite", NewDense(3, 3, []T{
+			1, 2, 3,
+			2, -4, 5,
+			3, 5, 0,
+		}), nil},
+		{"diagonal", NewDense(2, 2, []T{
+			3, 0,
+			0, -1,
+		}), []T{-1, 3}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := tc.a.Rows()
+			values, vectors := tc.a.EigenSym()
+			assertDenseDims(t, n, 1, values.(*Dense[T]))
+			assertDenseDims(t, n, n, vectors.(*Dense[T]))
+
+			ev := Data[T](values)
+			if tc.values != nil {
+				assert.InDeltaSlice(t, tc.values, ev, 1.0e-5)
+			}
+			for i := 1; i < n; i++ {
+				assert.LessOrEqual(t, ev[i-1], ev[i])
+			}
+			assert.InDeltaSlice(t, Data[T](vectors.Mul(diagDense[T](ev))), Data[T](tc.a.Mul(vectors)), 1.0e-4)
+			assertOrthonormalColumns[T](t, vectors)
+		})
+	}
+
+	assert.Panics(t, func() { NewEmptyDense[T](2, 3).EigenSym() })
+}
+
+func assertOrthonormalColumns[T float.DType](t *testing.T, m Matrix) {
+	t.Helper()
+	cols := m.Columns()
+	assert.InDeltaSlice(t, NewIdentityDense[T](cols).data, Data[T](m.T().Mul(m)), 1.0e-4)
+}
+
+func diagDense[T float.DType](values []T) *Dense[T] {
+	n := len(values)
+	d := NewEmptyDense[T](n, n)
+	for i, v := range values {
+		d.data[i*n+i] = v
+	}
+	return d
+}
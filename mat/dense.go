@@ -415,9 +415,11 @@ mainLoop:
 }
 
 // Add returns the addition between the receiver and another matrix.
+// The matrices are broadcast against each other if their dimensions differ
+// (see BroadcastDims).
 func (d *Dense[T]) Add(other Matrix) Matrix {
 	if !SameDims(d, other) {
-		panic("mat: matrices have incompatible dimensions")
+		return d.broadcast(other, func(a, b T) T { return a + b })
 	}
 	out := NewEmptyDense[T](d.rows, d.cols)
 	switch any(T(0)).(type) {
@@ -483,9 +485,11 @@ func (d *Dense[T]) AddScalarInPlace(n float64) Matrix {
 }
 
 // Sub returns the subtraction of the other matrix from the receiver.
+// The matrices are broadcast against each other if their dimensions differ
+// (see BroadcastDims).
 func (d *Dense[T]) Sub(other Matrix) Matrix {
 	if !SameDims(d, other) {
-		panic("mat: matrices have incompatible dimensions")
+		return d.broadcast(other, func(a, b T) T { return a - b })
 	}
 	out := NewEmptyDense[T](d.rows, d.cols)
 	switch any(T(0)).(type) {
@@ -549,9 +553,11 @@ func (d *Dense[T]) SubScalarInPlace(n float64) Matrix {
 }
 
 // Prod performs the element-wise product between the receiver and the other matrix.
+// The matrices are broadcast against each other if their dimensions differ
+// (see BroadcastDims).
 func (d *Dense[T]) Prod(other Matrix) Matrix {
 	if !SameDims(d, other) {
-		panic("mat: matrices have incompatible dimensions")
+		return d.broadcast(other, func(a, b T) T { return a * b })
 	}
 
 	out := densePool[T]().Get(d.rows, d.cols)
@@ -639,9 +645,11 @@ func (d *Dense[T]) ProdMatrixScalarInPlace(m Matrix, n float64) Matrix {
 }
 
 // Div returns the result of the element-wise division of the receiver by the other matrix.
+// The matrices are broadcast against each other if their dimensions differ
+// (see BroadcastDims).
 func (d *Dense[T]) Div(other Matrix) Matrix {
 	if !SameDims(d, other) {
-		panic("mat: matrices have incompatible dimensions")
+		return d.broadcast(other, func(a, b T) T { return a / b })
 	}
 	out := NewEmptyDense[T](d.rows, d.cols)
 	switch any(T(0)).(type) {
@@ -1084,6 +1092,143 @@ func (d *Dense[T]) ArgMax() int {
 	return maxIndex
 }
 
+// SumAxis returns the sums of the values of the matrix along the given axis:
+// 0 reduces each column, returning a row vector (1×cols), and 1 reduces
+// each row, returning a column vector (rows×1).
+func (d *Dense[T]) SumAxis(axis int) Matrix {
+	return d.reduceAxis(axis, func(lane []T) T {
+		var sum T
+		for _, v := range lane {
+			sum += v
+		}
+		return sum
+	})
+}
+
+// MeanAxis returns the means of the values of the matrix along the given
+// axis (see SumAxis).
+func (d *Dense[T]) MeanAxis(axis int) Matrix {
+	return d.reduceAxis(axis, laneMean[T])
+}
+
+// MaxAxis returns the maximum values of the matrix along the given axis
+// (see SumAxis).
+func (d *Dense[T]) MaxAxis(axis int) Matrix {
+	return d.reduceAxis(axis, func(lane []T) T {
+		return lane[laneArgMax(lane)]
+	})
+}
+
+// ArgMaxAxis returns the indices of the maximum values of the matrix along
+// the given axis: 0 returns the row index of the maximum of each column, and
+// 1 returns the column index of the maximum of each row.
+func (d *Dense[T]) ArgMaxAxis(axis int) []int {
+	var indices []int
+	d.forEachLane(axis, func(_ int, lane []T) {
+		indices = append(indices, laneArgMax(lane))
+	})
+	return indices
+}
+
+// VarAxis returns the (biased) variances of the values of the matrix along
+// the given axis (see SumAxis), that is the means of the squared deviations
+// from the means.
+func (d *Dense[T]) VarAxis(axis int) Matrix {
+	return d.reduceAxis(axis, laneVar[T])
+}
+
+// StdAxis returns the (biased) standard deviations of the values of the
+// matrix along the given axis (see SumAxis), that is the square roots of
+// the variances.
+func (d *Dense[T]) StdAxis(axis int) Matrix {
+	return d.reduceAxis(axis, func(lane []T) T {
+		return Sqrt(laneVar(lane))
+	})
+}
+
+// LogSumExpAxis returns the logarithms of the sums of the exponentials of
+// the values of the matrix along the given axis (see SumAxis). The maximum
+// values are subtracted before the exponentiation, for numerical stability.
+func (d *Dense[T]) LogSumExpAxis(axis int) Matrix {
+	return d.reduceAxis(axis, func(lane []T) T {
+		max := lane[laneArgMax(lane)]
+		if math.IsInf(float64(max), 0) {
+			return max
+		}
+		var sum float64
+		for _, v := range lane {
+			sum += math.Exp(float64(v - max))
+		}
+		return max + T(math.Log(sum))
+	})
+}
+
+// reduceAxis returns a new vector with the results of f applied to each
+// column (axis 0) or to each row (axis 1) of the matrix.
+func (d *Dense[T]) reduceAxis(axis int, f func(lane []T) T) *Dense[T] {
+	var out *Dense[T]
+	if axis == 0 {
+		out = NewEmptyDense[T](1, d.cols)
+	} else {
+		out = NewEmptyDense[T](d.rows, 1)
+	}
+	d.forEachLane(axis, func(i int, lane []T) {
+		out.data[i] = f(lane)
+	})
+	return out
+}
+
+// forEachLane calls fn for each column (axis 0) or row (axis 1) of the
+// matrix, with its index and its values. The values must not be retained.
+func (d *Dense[T]) forEachLane(axis int, fn func(i int, lane []T)) {
+	switch axis {
+	case 0:
+		lane := make([]T, d.rows)
+		for j := 0; j < d.cols; j++ {
+			for i := range lane {
+				lane[i] = d.data[i*d.cols+j]
+			}
+			fn(j, lane)
+		}
+	case 1:
+		for i := 0; i < d.rows; i++ {
+			fn(i, d.data[i*d.cols:(i+1)*d.cols])
+		}
+	default:
+		panic(fmt.Sprintf("mat: invalid axis %d", axis))
+	}
+}
+
+func laneMean[T float.DType](lane []T) T {
+	var sum T
+	for _, v := range lane {
+		sum += v
+	}
+	return sum / T(len(lane))
+}
+
+func laneVar[T float.DType](lane []T) T {
+	mean := laneMean(lane)
+	var sum T
+	for _, v := range lane {
+		sum += (v - mean) * (v - mean)
+	}
+	return sum / T(len(lane))
+}
+
+func laneArgMax[T float.DType](lane []T) int {
+	if len(lane) == 0 {
+		panic("mat: cannot find arg-max from an empty vector")
+	}
+	maxIndex := 0
+	for i, v := range lane {
+		if v > lane[maxIndex] {
+			maxIndex = i
+		}
+	}
+	return maxIndex
+}
+
 // Softmax applies the softmax function to the vector, returning the
 // result as a new column vector.
 func (d *Dense[T]) Softmax() Matrix {
@@ -1417,6 +1562,38 @@ func (d *Dense[T]) Inverse() Matrix {
 	return out
 }
 
+// QR performs the thin QR decomposition of the matrix D (rows×cols), such
+// that QR = D, where Q (rows×k) has orthonormal columns, and R (k×cols)
+// is upper triangular with a non-negative diagonal, being k = min(rows, cols).
+//
+// The decomposition is computed by Householder reflections.
+func (d *Dense[T]) QR() (q, r Matrix) {
+	return qr(d.data, d.rows, d.cols)
+}
+
+// SVD performs the thin singular value decomposition of the matrix D
+// (rows×cols), such that U·diag(S)·Vᵀ = D, where U (rows×k) and V (cols×k)
+// have orthonormal columns, and S is the column vector (k×1) of the singular
+// values, in descending order, being k = min(rows, cols).
+//
+// The decomposition is computed by the one-sided Jacobi method.
+func (d *Dense[T]) SVD() (u, s, v Matrix) {
+	return svd(d.data, d.rows, d.cols)
+}
+
+// EigenSym performs the eigendecomposition of a symmetric matrix, returning
+// the column vector of the eigenvalues, in ascending order, and the matrix
+// whose columns are the corresponding orthonormal eigenvectors.
+//
+// The decomposition is computed by the cyclic Jacobi method. The matrix is
+// assumed to be symmetric; it panics if it is not square.
+func (d *Dense[T]) EigenSym() (values, vectors Matrix) {
+	if d.rows != d.cols {
+		panic("mat: matrix must be square")
+	}
+	return eigenSym(d.data, d.rows)
+}
+
 // VecForEach calls fn for each element of the vector.
 // It panics if the receiver is not a vector.
 func (d *Dense[T]) VecForEach(fn func(i int, v float64)) {
@@ -138,3 +138,23 @@ func NewIdentityDense[T float.DType](size int) *Dense[T] {
 	}
 	return out
 }
+
+// NewMaskDense returns a new rows×cols dense matrix from the given boolean
+// values, in row-major order, with ones where the value is true and zeros
+// elsewhere. The resulting 0/1 matrix can be used as a mask.
+func NewMaskDense[T float.DType](rows, cols int, mask []bool) *Dense[T] {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative values for rows and cols are not allowed")
+	}
+	if len(mask) != rows*cols {
+		panic(fmt.Sprintf("mat: wrong matrix dimensions. Elements size must be: %d", rows*cols))
+	}
+	out := densePool[T]().GetEmpty(rows, cols)
+	data := out.data
+	for i, v := range mask {
+		if v {
+			data[i] = 1
+		}
+	}
+	return out
+}
@@ -381,3 +381,26 @@ func testNewIdentityDense[T float.DType](t *testing.T) {
 		})
 	}
 }
+
+func TestNewMaskDense(t *testing.T) {
+	t.Run("float32", testNewMaskDense[float32])
+	t.Run("float64", testNewMaskDense[float64])
+}
+
+func testNewMaskDense[T float.DType](t *testing.T) {
+	t.Run("negative rows", func(t *testing.T) {
+		require.Panics(t, func() {
+			NewMaskDense[T](-1, 1, nil)
+		})
+	})
+
+	t.Run("wrong mask size", func(t *testing.T) {
+		require.Panics(t, func() {
+			NewMaskDense[T](2, 2, []bool{true})
+		})
+	})
+
+	d := NewMaskDense[T](2, 3, []bool{true, false, false, true, true, false})
+	assertDenseDims(t, 2, 3, d)
+	assert.Equal(t, []T{1, 0, 0, 1, 1, 0}, Data[T](d))
+}
@@ -6,6 +6,7 @@ package mat
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/nlpodyssey/spago/mat/float"
@@ -2654,6 +2655,68 @@ func testDenseArgMax[T float.DType](t *testing.T) {
 	}
 }
 
+func TestDense_AxisReductions(t *testing.T) {
+	t.Run("float32", testDenseAxisReductions[float32])
+	t.Run("float64", testDenseAxisReductions[float64])
+}
+
+func testDenseAxisReductions[T float.DType](t *testing.T) {
+	d := NewDense[T](2, 3, []T{
+		1, 2, 3,
+		4, 0, -2,
+	})
+
+	testCases := []struct {
+		name  string
+		f     func(axis int) Matrix
+		axis0 []T
+		axis1 []T
+	}{
+		{"SumAxis", d.SumAxis, []T{5, 2, 1}, []T{6, 2}},
+		{"MeanAxis", d.MeanAxis, []T{2.5, 1, 0.5}, []T{2, 0.666667}},
+		{"MaxAxis", d.MaxAxis, []T{4, 2, 3}, []T{3, 4}},
+		{"VarAxis", d.VarAxis, []T{2.25, 1, 6.25}, []T{0.666667, 6.222222}},
+		{"StdAxis", d.StdAxis, []T{1.5, 1, 2.5}, []T{0.816497, 2.494438}},
+		{"LogSumExpAxis", d.LogSumExpAxis, []T{4.048587, 2.126928, 3.006715}, []T{3.407606, 4.020581}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			y := tc.f(0)
+			assertDenseDims(t, 1, 3, y.(*Dense[T]))
+			assert.InDeltaSlice(t, tc.axis0, y.Data(), 1.0e-6)
+
+			y = tc.f(1)
+			assertDenseDims(t, 2, 1, y.(*Dense[T]))
+			assert.InDeltaSlice(t, tc.axis1, y.Data(), 1.0e-6)
+
+			require.Panics(t, func() { tc.f(2) })
+		})
+	}
+
+	t.Run("ArgMaxAxis", func(t *testing.T) {
+		assert.Equal(t, []int{1, 0, 0}, d.ArgMaxAxis(0))
+		assert.Equal(t, []int{2, 0}, d.ArgMaxAxis(1))
+		require.Panics(t, func() { d.ArgMaxAxis(-1) })
+		require.Panics(t, func() { NewEmptyDense[T](0, 2).ArgMaxAxis(0) })
+	})
+
+	t.Run("ArgMaxAxis with ties", func(t *testing.T) {
+		m := NewDense[T](2, 3, []T{
+			3, 1, 3,
+			0, 2, 2,
+		})
+		assert.Equal(t, []int{0, 1, 0}, m.ArgMaxAxis(0))
+		assert.Equal(t, []int{0, 1}, m.ArgMaxAxis(1))
+	})
+
+	t.Run("LogSumExpAxis with infinite values", func(t *testing.T) {
+		inf := T(math.Inf(-1))
+		y := NewDense[T](2, 2, []T{inf, inf, 1, inf}).LogSumExpAxis(1)
+		assert.Equal(t, []T{inf, 1}, Data[T](y))
+	})
+}
+
 func TestDense_Softmax(t *testing.T) {
 	t.Run("float32", testDenseSoftmax[float32])
 	t.Run("float64", testDenseSoftmax[float64])
@@ -0,0 +1,191 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// Solve returns the solution x of the linear system a·x = b, where a is a
+// square matrix, and b is a matrix (e.g. a column vector) with the same
+// number of rows of a. The result has the same dimensions of b, and the same
+// type of a.
+//
+// The system is solved by LU decomposition with partial pivoting. It panics
+// if a is singular.
+func Solve(a, b Matrix) Matrix {
+	if a.Rows() != a.Columns() {
+		panic("mat: matrix must be square")
+	}
+	if a.Rows() != b.Rows() {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	if _, ok := a.(*Dense[float32]); ok {
+		return solve[float32](a, b)
+	}
+	return solve[float64](a, b)
+}
+
+// LogAbsDet returns the natural logarithm of the absolute value of the
+// determinant of the square matrix a, and the sign of the determinant,
+// which is either -1, 0 or +1. The logarithm of a singular matrix is -Inf.
+func LogAbsDet(a Matrix) (logAbsDet, sign float64) {
+	if a.Rows() != a.Columns() {
+		panic("mat: matrix must be square")
+	}
+	n := a.Rows()
+	lu, _, sign, ok := luDecompose(n, Data[float64](a))
+	if !ok {
+		return math.Inf(-1), 0
+	}
+	for i := 0; i < n; i++ {
+		u := lu[i*n+i]
+		if u < 0 {
+			sign = -sign
+		}
+		logAbsDet += math.Log(math.Abs(u))
+	}
+	return logAbsDet, sign
+}
+
+// Cholesky returns the lower triangular matrix l of the Cholesky
+// decomposition of the symmetric positive-definite matrix a, such that
+// a = l·lᵀ. Only the lower triangular part of a is read.
+//
+// It panics if a is not positive-definite.
+func Cholesky(a Matrix) Matrix {
+	if a.Rows() != a.Columns() {
+		panic("mat: matrix must be square")
+	}
+	if _, ok := a.(*Dense[float32]); ok {
+		return cholesky[float32](a)
+	}
+	return cholesky[float64](a)
+}
+
+func solve[T float.DType](a, b Matrix) *Dense[T] {
+	n, k := a.Rows(), b.Columns()
+	lu, perm, _, ok := luDecompose(n, Data[T](a))
+	if !ok {
+		panic("mat: matrix is singular")
+	}
+
+	x := NewEmptyDense[T](n, k)
+	xData := x.data
+	bData := Data[T](b)
+	for i, p := range perm {
+		copy(xData[i*k:(i+1)*k], bData[p*k:(p+1)*k])
+	}
+
+	// forward substitution, with the unit lower triangular matrix
+	for i := 0; i < n; i++ {
+		xi := xData[i*k : (i+1)*k]
+		for j := 0; j < i; j++ {
+			l := lu[i*n+j]
+			xj := xData[j*k : (j+1)*k]
+			for c := range xi {
+				xi[c] -= l * xj[c]
+			}
+		}
+	}
+	// backward substitution, with the upper triangular matrix
+	for i := n - 1; i >= 0; i-- {
+		xi := xData[i*k : (i+1)*k]
+		for j := i + 1; j < n; j++ {
+			u := lu[i*n+j]
+			xj := xData[j*k : (j+1)*k]
+			for c := range xi {
+				xi[c] -= u * xj[c]
+			}
+		}
+		d := lu[i*n+i]
+		for c := range xi {
+			xi[c] /= d
+		}
+	}
+	return x
+}
+
+// luDecompose performs the LU decomposition with partial pivoting of the
+// n×n matrix a, which is not modified.
+//
+// It returns the L and U factors packed in a single matrix (the unit
+// diagonal of L is omitted), the permutation of the rows, such that the
+// row i of L·U is the row perm[i] of a, and the sign of the permutation.
+// It reports false if the matrix is singular.
+func luDecompose[T float.DType](n int, a []T) (lu []T, perm []int, sign float64, ok bool) {
+	lu = append(make([]T, 0, len(a)), a...)
+	perm = make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	sign = 1
+
+	for j := 0; j < n; j++ {
+		p := j
+		max := Abs(lu[j*n+j])
+		for i := j + 1; i < n; i++ {
+			if v := Abs(lu[i*n+j]); v > max {
+				max = v
+				p = i
+			}
+		}
+		if max == 0 {
+			return lu, perm, 0, false
+		}
+		if p != j {
+			rowP, rowJ := lu[p*n:(p+1)*n], lu[j*n:(j+1)*n]
+			for c := range rowJ {
+				rowP[c], rowJ[c] = rowJ[c], rowP[c]
+			}
+			perm[p], perm[j] = perm[j], perm[p]
+			sign = -sign
+		}
+
+		pivot := lu[j*n+j]
+		rowJ := lu[j*n : (j+1)*n]
+		for i := j + 1; i < n; i++ {
+			rowI := lu[i*n : (i+1)*n]
+			f := rowI[j] / pivot
+			rowI[j] = f
+			for c := j + 1; c < n; c++ {
+				rowI[c] -= f * rowJ[c]
+			}
+		}
+	}
+	return lu, perm, sign, true
+}
+
+func cholesky[T float.DType](a Matrix) *Dense[T] {
+	n := a.Rows()
+	aData := Data[T](a)
+	l := NewEmptyDense[T](n, n)
+	lData := l.data
+
+	for j := 0; j < n; j++ {
+		rowJ := lData[j*n : j*n+j]
+		sum := aData[j*n+j]
+		for _, v := range rowJ {
+			sum -= v * v
+		}
+		if !(sum > 0) {
+			panic("mat: matrix is not positive-definite")
+		}
+		d := Sqrt(sum)
+		lData[j*n+j] = d
+
+		for i := j + 1; i < n; i++ {
+			rowI := lData[i*n : i*n+j]
+			sum := aData[i*n+j]
+			for k, v := range rowI {
+				sum -= v * rowJ[k]
+			}
+			lData[i*n+j] = sum / d
+		}
+	}
+	return l
+}
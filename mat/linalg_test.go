@@ -0,0 +1,98 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolve(t *testing.T) {
+	t.Run("float32", testSolve[float32])
+	t.Run("float64", testSolve[float64])
+}
+
+func testSolve[T float.DType](t *testing.T) {
+	// the first pivot is zero
+	a := NewDense(3, 3, []T{
+		0, 2, 1,
+		1, 1, 1,
+		2, 1, 3,
+	})
+
+	x := Solve(a, NewVecDense([]T{5, 4, 7}))
+	assertDenseDims(t, 3, 1, x.(*Dense[T]))
+	assert.InDeltaSlice(t, []T{1, 2, 1}, Data[T](x), 1.0e-5)
+
+	b := NewDense(3, 2, []T{
+		5, 1,
+		6, 0,
+		13, 0,
+	})
+	x = Solve(a, b)
+	assertDenseDims(t, 3, 2, x.(*Dense[T]))
+	assert.InDeltaSlice(t, Data[T](b), Data[T](a.Mul(x)), 1.0e-5)
+
+	assert.Panics(t, func() { Solve(NewEmptyDense[T](2, 3), NewEmptyVecDense[T](2)) })
+	assert.Panics(t, func() { Solve(a, NewEmptyVecDense[T](2)) })
+	assert.Panics(t, func() { Solve(NewDense(2, 2, []T{1, 2, 2, 4}), NewEmptyVecDense[T](2)) })
+}
+
+func TestLogAbsDet(t *testing.T) {
+	t.Run("float32", testLogAbsDet[float32])
+	t.Run("float64", testLogAbsDet[float64])
+}
+
+func testLogAbsDet[T float.DType](t *testing.T) {
+	testCases := []struct {
+		name string
+		a    *Dense[T]
+		det  float64
+	}{
+		{"positive", NewDense(2, 2, []T{4, 1, 2, 3}), 10},
+		{"negative", NewDense(3, 3, []T{0, 2, 1, 1, 1, 1, 2, 1, 3}), -3},
+		{"scalar", NewScalar[T](0.5), 0.5},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logAbsDet, sign := LogAbsDet(tc.a)
+			assert.InDelta(t, math.Log(math.Abs(tc.det)), logAbsDet, 1.0e-6)
+			assert.Equal(t, math.Copysign(1, tc.det), sign)
+		})
+	}
+
+	logAbsDet, sign := LogAbsDet(NewDense(2, 2, []T{1, 2, 2, 4}))
+	assert.True(t, math.IsInf(logAbsDet, -1))
+	assert.Equal(t, 0.0, sign)
+
+	assert.Panics(t, func() { LogAbsDet(NewEmptyDense[T](2, 3)) })
+}
+
+func TestCholesky(t *testing.T) {
+	t.Run("float32", testCholesky[float32])
+	t.Run("float64", testCholesky[float64])
+}
+
+func testCholesky[T float.DType](t *testing.T) {
+	a := NewDense(3, 3, []T{
+		4, 12, -16,
+		12, 37, -43,
+		-16, -43, 98,
+	})
+
+	l := Cholesky(a)
+	assertDenseDims(t, 3, 3, l.(*Dense[T]))
+	assert.InDeltaSlice(t, []T{
+		2, 0, 0,
+		6, 1, 0,
+		-8, 5, 3,
+	}, Data[T](l), 1.0e-5)
+
+	assert.Panics(t, func() { Cholesky(NewEmptyDense[T](2, 3)) })
+	assert.Panics(t, func() { Cholesky(NewDense(2, 2, []T{1, 2, 2, 1})) })
+}
@@ -100,6 +100,8 @@ type Matrix interface {
 	// matrix itself.
 	TransposeInPlace() Matrix
 	// Add returns the addition between the receiver and another matrix.
+	// The matrices are broadcast against each other if their dimensions differ
+	// (see BroadcastDims).
 	Add(other Matrix) Matrix
 	// AddInPlace performs the in-place addition with the other matrix.
 	AddInPlace(other Matrix) Matrix
@@ -108,6 +110,8 @@ type Matrix interface {
 	// AddScalarInPlace adds the scalar to all values of the matrix.
 	AddScalarInPlace(n float64) Matrix
 	// Sub returns the subtraction of the other matrix from the receiver.
+	// The matrices are broadcast against each other if their dimensions differ
+	// (see BroadcastDims).
 	Sub(other Matrix) Matrix
 	// SubInPlace performs the in-place subtraction with the other matrix.
 	SubInPlace(other Matrix) Matrix
@@ -116,6 +120,8 @@ type Matrix interface {
 	// SubScalarInPlace subtracts the scalar from the receiver's values.
 	SubScalarInPlace(n float64) Matrix
 	// Prod performs the element-wise product between the receiver and the other matrix.
+	// The matrices are broadcast against each other if their dimensions differ
+	// (see BroadcastDims).
 	Prod(other Matrix) Matrix
 	// ProdInPlace performs the in-place element-wise product with the other matrix.
 	ProdInPlace(other Matrix) Matrix
@@ -128,6 +134,8 @@ type Matrix interface {
 	// storing the result in the receiver.
 	ProdMatrixScalarInPlace(m Matrix, n float64) Matrix
 	// Div returns the result of the element-wise division of the receiver by the other matrix.
+	// The matrices are broadcast against each other if their dimensions differ
+	// (see BroadcastDims).
 	Div(other Matrix) Matrix
 	// DivInPlace performs the in-place element-wise division of the receiver by the other matrix.
 	DivInPlace(other Matrix) Matrix
@@ -169,6 +177,32 @@ type Matrix interface {
 	Min() Matrix
 	// ArgMax returns the index of the vector's element with the maximum value.
 	ArgMax() int
+	// SumAxis returns the sums of the values of the matrix along the given axis:
+	// 0 reduces each column, returning a row vector (1×cols), and 1 reduces
+	// each row, returning a column vector (rows×1).
+	SumAxis(axis int) Matrix
+	// MeanAxis returns the means of the values of the matrix along the given
+	// axis (see SumAxis).
+	MeanAxis(axis int) Matrix
+	// MaxAxis returns the maximum values of the matrix along the given axis
+	// (see SumAxis).
+	MaxAxis(axis int) Matrix
+	// ArgMaxAxis returns the indices of the maximum values of the matrix along
+	// the given axis: 0 returns the row index of the maximum of each column, and
+	// 1 returns the column index of the maximum of each row.
+	ArgMaxAxis(axis int) []int
+	// VarAxis returns the (biased) variances of the values of the matrix along
+	// the given axis (see SumAxis), that is the means of the squared deviations
+	// from the means.
+	VarAxis(axis int) Matrix
+	// StdAxis returns the (biased) standard deviations of the values of the
+	// matrix along the given axis (see SumAxis), that is the square roots of
+	// the variances.
+	StdAxis(axis int) Matrix
+	// LogSumExpAxis returns the logarithms of the sums of the exponentials of
+	// the values of the matrix along the given axis (see SumAxis). The maximum
+	// values are subtracted before the exponentiation, for numerical stability.
+	LogSumExpAxis(axis int) Matrix
 	// Softmax applies the softmax function to the vector, returning the
 	// result as a new column vector.
 	Softmax() Matrix
@@ -210,6 +244,19 @@ type Matrix interface {
 	LU() (l, u, p Matrix)
 	// Inverse returns the inverse of the Matrix.
 	Inverse() Matrix
+	// QR performs the thin QR decomposition of the matrix D (rows×cols), such
+	// that QR = D, where Q (rows×k) has orthonormal columns, and R (k×cols)
+	// is upper triangular with a non-negative diagonal, being k = min(rows, cols).
+	QR() (q, r Matrix)
+	// SVD performs the thin singular value decomposition of the matrix D
+	// (rows×cols), such that U·diag(S)·Vᵀ = D, where U (rows×k) and V (cols×k)
+	// have orthonormal columns, and S is the column vector (k×1) of the singular
+	// values, in descending order, being k = min(rows, cols).
+	SVD() (u, s, v Matrix)
+	// EigenSym performs the eigendecomposition of a symmetric matrix, returning
+	// the column vector of the eigenvalues, in ascending order, and the matrix
+	// whose columns are the corresponding orthonormal eigenvectors.
+	EigenSym() (values, vectors Matrix)
 	// VecForEach calls fn for each element of the vector.
 	// It panics if the receiver is not a vector.
 	VecForEach(fn func(i int, v float64))
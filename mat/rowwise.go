@@ -0,0 +1,36 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// RowwiseSoftmax returns a new matrix, of the same type and dimensions of
+// m, where the softmax function is applied to each row independently.
+func RowwiseSoftmax(m Matrix) Matrix {
+	rows, cols := m.Dims()
+	data := m.Data().F64()
+	out := make([]float64, len(data))
+	for i := 0; i < rows; i++ {
+		row := data[i*cols : (i+1)*cols]
+		outRow := out[i*cols : (i+1)*cols]
+		max := math.Inf(-1)
+		for _, v := range row {
+			max = math.Max(max, v)
+		}
+		sum := 0.0
+		for j, v := range row {
+			outRow[j] = math.Exp(v - max)
+			sum += outRow[j]
+		}
+		for j := range outRow {
+			outRow[j] /= sum
+		}
+	}
+	return m.NewMatrix(rows, cols, float.SliceInterface(out))
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowwiseSoftmax(t *testing.T) {
+	t.Run("float32", testRowwiseSoftmax[float32])
+	t.Run("float64", testRowwiseSoftmax[float64])
+}
+
+func testRowwiseSoftmax[T float.DType](t *testing.T) {
+	m := NewDense(3, 3, []T{
+		-0.41, -1.08, 0,
+		1000, 1000, 1000,
+		0.5, T(math.Inf(-1)), 0.5,
+	})
+	y := RowwiseSoftmax(m)
+	assert.Equal(t, 3, y.Rows())
+	assert.Equal(t, 3, y.Columns())
+	assert.InDeltaSlice(t, []T{
+		0.331287, 0.169523, 0.49919,
+		1.0 / 3, 1.0 / 3, 1.0 / 3,
+		0.5, 0, 0.5,
+	}, Data[T](y), 1.0e-6)
+
+	// the input is not modified
+	assert.Equal(t, T(1000), Data[T](m)[3])
+
+	for i := 0; i < 3; i++ {
+		assert.InDeltaSlice(t, Data[T](m.ExtractRow(i).Softmax()), Data[T](y.ExtractRow(i)), 1.0e-6)
+	}
+}
@@ -0,0 +1,509 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// Tensor is an N-dimensional array of values, with arbitrary rank, shape
+// and strides.
+//
+// The values are stored in a Matrix: the element at the index (i₀, i₁, …)
+// is located at the position offset + i₀·strides[0] + i₁·strides[1] + … of
+// the data of the storage, in row-major order. Different tensors can share
+// the same storage, such as a tensor and its permutations, or the views
+// created by BroadcastTo, whose strides along the repeated dimensions are
+// zero.
+type Tensor struct {
+	storage Matrix
+	shape   []int
+	strides []int
+	offset  int
+}
+
+// NewTensor returns a new tensor with the given shape, initialized with a
+// copy of raw data, in row-major order.
+//
+// The length of data MUST be equal to the product of the dimensions,
+// otherwise the function panics.
+func NewTensor[T float.DType](shape []int, data []T) *Tensor {
+	checkShape(shape)
+	if size := ShapeSize(shape); len(data) != size {
+		panic(fmt.Sprintf("mat: wrong tensor shape. Elements size must be: %d", size))
+	}
+	return newTensor(NewVecDense(data), shape)
+}
+
+// NewEmptyTensor returns a new tensor with the given shape, initialized
+// with zeros.
+func NewEmptyTensor[T float.DType](shape ...int) *Tensor {
+	checkShape(shape)
+	return newTensor(NewEmptyVecDense[T](ShapeSize(shape)), shape)
+}
+
+// TensorView returns a new tensor with the given shape, whose elements are
+// the data of the matrix m, in row-major order. If the shape is omitted, it
+// is [rows, columns].
+//
+// The data is shared, hence the tensor MUST not be used after m has been
+// released. One of the dimensions can be -1, in which case it is inferred
+// from the size of m.
+func TensorView(m Matrix, shape ...int) *Tensor {
+	if len(shape) == 0 {
+		shape = []int{m.Rows(), m.Columns()}
+	}
+	return newTensor(m, ResolveShape(shape, m.Size()))
+}
+
+// TensorMatrixDims returns the dimensions of the matrix representing a
+// tensor with the given shape (see Tensor.Matrix).
+func TensorMatrixDims(shape []int) (rows, cols int) {
+	switch n := len(shape); n {
+	case 0:
+		return 1, 1
+	case 1:
+		return shape[0], 1
+	default:
+		return ShapeSize(shape[:n-1]), shape[n-1]
+	}
+}
+
+// BroadcastShapes returns the shape resulting from broadcasting two tensors
+// with shapes a and b against each other, following the NumPy semantics:
+// the shapes are aligned to the last dimension, the missing leading
+// dimensions are considered of size 1, and each pair of dimensions must be
+// either equal, or one of them must be 1.
+//
+// The last returned value reports whether the shapes are compatible.
+func BroadcastShapes(a, b []int) ([]int, bool) {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	n := len(a) - len(b)
+	shape := make([]int, len(a))
+	copy(shape, a[:n])
+	for i, d := range b {
+		s, ok := broadcastDim(a[n+i], d)
+		if !ok {
+			return nil, false
+		}
+		shape[n+i] = s
+	}
+	return shape, true
+}
+
+func newTensor(storage Matrix, shape []int) *Tensor {
+	shape = append([]int{}, shape...)
+	return &Tensor{
+		storage: storage,
+		shape:   shape,
+		strides: contiguousStrides(shape),
+	}
+}
+
+// Shape returns the size of each dimension of the tensor.
+func (t *Tensor) Shape() []int {
+	return append([]int{}, t.shape...)
+}
+
+// Strides returns the distance, in the storage, between two consecutive
+// elements along each dimension of the tensor.
+func (t *Tensor) Strides() []int {
+	return append([]int{}, t.strides...)
+}
+
+// Rank returns the number of dimensions of the tensor.
+func (t *Tensor) Rank() int {
+	return len(t.shape)
+}
+
+// Size returns the number of elements of the tensor.
+func (t *Tensor) Size() int {
+	return ShapeSize(t.shape)
+}
+
+// Dim returns the size of the given dimension.
+func (t *Tensor) Dim(axis int) int {
+	t.checkAxis(axis)
+	return t.shape[axis]
+}
+
+// IsContiguous reports whether the elements of the tensor are stored
+// contiguously, in row-major order.
+func (t *Tensor) IsContiguous() bool {
+	stride := 1
+	for i := len(t.shape) - 1; i >= 0; i-- {
+		if t.shape[i] != 1 && t.strides[i] != stride {
+			return false
+		}
+		stride *= t.shape[i]
+	}
+	return true
+}
+
+// At returns the value at the given index.
+// It panics if the index is out of range.
+func (t *Tensor) At(index ...int) float.Float {
+	r, c := t.storagePosition(index)
+	return t.storage.ScalarAt(r, c)
+}
+
+// SetAt sets the value v at the given index. Since the storage can be
+// shared, the change is visible from all the tensors sharing it.
+// It panics if the index is out of range.
+func (t *Tensor) SetAt(v float.Float, index ...int) {
+	r, c := t.storagePosition(index)
+	t.storage.SetScalar(r, c, v)
+}
+
+// Data returns a copy of the elements of the tensor, in row-major order.
+func (t *Tensor) Data() float.Slice {
+	return t.gather().Data()
+}
+
+// Matrix returns a new matrix with a copy of the elements of the tensor, in
+// row-major order. A tensor of rank 0 is a 1×1 matrix, a tensor of rank 1
+// is a column vector, and tensors of higher rank are matrices whose columns
+// correspond to the last dimension (e.g. a tensor with shape [2, 3, 4] is a
+// 6×4 matrix).
+func (t *Tensor) Matrix() Matrix {
+	return t.gather().ReshapeInPlace(TensorMatrixDims(t.shape))
+}
+
+// Clone returns a new contiguous tensor, with a copy of the elements.
+func (t *Tensor) Clone() *Tensor {
+	return newTensor(t.gather(), t.shape)
+}
+
+// Contiguous returns the tensor itself if its elements are stored
+// contiguously, otherwise a contiguous copy (see Clone).
+func (t *Tensor) Contiguous() *Tensor {
+	if t.IsContiguous() {
+		return t
+	}
+	return t.Clone()
+}
+
+// Reshape returns a tensor with the same elements, in row-major order, and
+// the given shape. One of the dimensions can be -1, in which case it is
+// inferred from the size of the tensor.
+//
+// The storage is shared if the tensor is contiguous, otherwise the
+// elements are copied.
+func (t *Tensor) Reshape(shape ...int) *Tensor {
+	shape = ResolveShape(shape, t.Size())
+	c := t.Contiguous()
+	return &Tensor{
+		storage: c.storage,
+		shape:   shape,
+		strides: contiguousStrides(shape),
+		offset:  c.offset,
+	}
+}
+
+// Permute returns a view of the tensor with its dimensions permuted:
+// the dimension i of the new tensor is the dimension axes[i] of the
+// receiver. The storage is shared.
+func (t *Tensor) Permute(axes ...int) *Tensor {
+	if len(axes) != len(t.shape) {
+		panic(fmt.Sprintf("mat: invalid permutation %v for a tensor of rank %d", axes, len(t.shape)))
+	}
+	seen := make([]bool, len(axes))
+	shape := make([]int, len(axes))
+	strides := make([]int, len(axes))
+	for i, a := range axes {
+		if a < 0 || a >= len(axes) || seen[a] {
+			panic(fmt.Sprintf("mat: invalid permutation %v for a tensor of rank %d", axes, len(t.shape)))
+		}
+		seen[a] = true
+		shape[i] = t.shape[a]
+		strides[i] = t.strides[a]
+	}
+	return &Tensor{
+		storage: t.storage,
+		shape:   shape,
+		strides: strides,
+		offset:  t.offset,
+	}
+}
+
+// Transpose returns a view of the tensor with the dimensions a and b
+// swapped. The storage is shared.
+func (t *Tensor) Transpose(a, b int) *Tensor {
+	t.checkAxis(a)
+	t.checkAxis(b)
+	axes := make([]int, len(t.shape))
+	for i := range axes {
+		axes[i] = i
+	}
+	axes[a], axes[b] = b, a
+	return t.Permute(axes...)
+}
+
+// Squeeze returns a view of the tensor without the given dimension, which
+// must be of size 1. The storage is shared.
+func (t *Tensor) Squeeze(axis int) *Tensor {
+	t.checkAxis(axis)
+	if t.shape[axis] != 1 {
+		panic(fmt.Sprintf("mat: cannot squeeze dimension %d of size %d", axis, t.shape[axis]))
+	}
+	return &Tensor{
+		storage: t.storage,
+		shape:   append(append([]int{}, t.shape[:axis]...), t.shape[axis+1:]...),
+		strides: append(append([]int{}, t.strides[:axis]...), t.strides[axis+1:]...),
+		offset:  t.offset,
+	}
+}
+
+// Unsqueeze returns a view of the tensor with a new dimension of size 1,
+// inserted at the given position. The storage is shared.
+func (t *Tensor) Unsqueeze(axis int) *Tensor {
+	if axis < 0 || axis > len(t.shape) {
+		panic(fmt.Sprintf("mat: axis %d out of range for a tensor of rank %d", axis, len(t.shape)))
+	}
+	shape := append(append(append([]int{}, t.shape[:axis]...), 1), t.shape[axis:]...)
+	strides := append(append(append([]int{}, t.strides[:axis]...), 0), t.strides[axis:]...)
+	return &Tensor{
+		storage: t.storage,
+		shape:   shape,
+		strides: strides,
+		offset:  t.offset,
+	}
+}
+
+// BroadcastTo returns a view of the tensor with the given shape, repeating
+// the elements along the dimensions of size 1, and along the missing
+// leading dimensions (see BroadcastShapes). The storage is shared.
+func (t *Tensor) BroadcastTo(shape ...int) *Tensor {
+	if !canBroadcastShape(t.shape, shape) {
+		panic(fmt.Sprintf("mat: cannot broadcast a tensor with shape %v to shape %v", t.shape, shape))
+	}
+	n := len(shape) - len(t.shape)
+	strides := make([]int, len(shape))
+	for i := n; i < len(shape); i++ {
+		if t.shape[i-n] == shape[i] {
+			strides[i] = t.strides[i-n]
+		}
+	}
+	return &Tensor{
+		storage: t.storage,
+		shape:   append([]int{}, shape...),
+		strides: strides,
+		offset:  t.offset,
+	}
+}
+
+// SumTo returns a new tensor with the given shape, summing the elements of
+// the receiver along the dimensions reduced to size 1, and along the
+// leading dimensions which are removed.
+//
+// It is the inverse operation of BroadcastTo, allowing to reduce the
+// gradients of a broadcast tensor.
+func (t *Tensor) SumTo(shape ...int) *Tensor {
+	if !canBroadcastShape(shape, t.shape) {
+		panic(fmt.Sprintf("mat: cannot sum a tensor with shape %v to shape %v", t.shape, shape))
+	}
+	out := t.storage.NewEmptyMatrix(ShapeSize(shape), 1)
+	y := newTensor(out, shape)
+	scatterAdd(out, t.storage, y.BroadcastTo(t.shape...).offsets(), t.offsets())
+	return y
+}
+
+// Add returns a new tensor with the element-wise sum of the receiver and
+// the other tensor, broadcast against each other (see BroadcastShapes).
+func (t *Tensor) Add(other *Tensor) *Tensor {
+	return t.elementWise(other, Matrix.Add)
+}
+
+// Sub returns a new tensor with the element-wise subtraction of the other
+// tensor from the receiver, broadcast against each other (see
+// BroadcastShapes).
+func (t *Tensor) Sub(other *Tensor) *Tensor {
+	return t.elementWise(other, Matrix.Sub)
+}
+
+// Prod returns a new tensor with the element-wise product of the receiver
+// and the other tensor, broadcast against each other (see BroadcastShapes).
+func (t *Tensor) Prod(other *Tensor) *Tensor {
+	return t.elementWise(other, Matrix.Prod)
+}
+
+// Div returns a new tensor with the element-wise division of the receiver
+// by the other tensor, broadcast against each other (see BroadcastShapes).
+func (t *Tensor) Div(other *Tensor) *Tensor {
+	return t.elementWise(other, Matrix.Div)
+}
+
+func (t *Tensor) elementWise(other *Tensor, f func(a, b Matrix) Matrix) *Tensor {
+	shape, ok := BroadcastShapes(t.shape, other.shape)
+	if !ok {
+		panic(fmt.Sprintf("mat: tensors have incompatible shapes %v and %v", t.shape, other.shape))
+	}
+	a := t.BroadcastTo(shape...).gather()
+	defer ReleaseMatrix(a)
+	b := other.BroadcastTo(shape...).gather()
+	defer ReleaseMatrix(b)
+	return newTensor(f(a, b), shape)
+}
+
+// String returns a string representation of the tensor, with its shape and
+// its elements in row-major order.
+func (t *Tensor) String() string {
+	return fmt.Sprintf("Tensor%v%v", t.shape, t.Data())
+}
+
+func (t *Tensor) checkAxis(axis int) {
+	if axis < 0 || axis >= len(t.shape) {
+		panic(fmt.Sprintf("mat: axis %d out of range for a tensor of rank %d", axis, len(t.shape)))
+	}
+}
+
+// storagePosition returns the row and column of the storage matrix where
+// the element at the given index is located.
+func (t *Tensor) storagePosition(index []int) (r, c int) {
+	if len(index) != len(t.shape) {
+		panic(fmt.Sprintf("mat: wrong number of indices: expected %d, actual %d", len(t.shape), len(index)))
+	}
+	offset := t.offset
+	for i, v := range index {
+		if v < 0 || v >= t.shape[i] {
+			panic(fmt.Sprintf("mat: index %d out of range for dimension %d of size %d", v, i, t.shape[i]))
+		}
+		offset += v * t.strides[i]
+	}
+	cols := t.storage.Columns()
+	return offset / cols, offset % cols
+}
+
+// offsets returns the positions in the data of the storage of all the
+// elements of the tensor, in row-major order.
+func (t *Tensor) offsets() []int {
+	offsets := make([]int, t.Size())
+	index := make([]int, len(t.shape))
+	offset := t.offset
+	for k := range offsets {
+		offsets[k] = offset
+		for i := len(index) - 1; i >= 0; i-- {
+			index[i]++
+			offset += t.strides[i]
+			if index[i] < t.shape[i] {
+				break
+			}
+			offset -= index[i] * t.strides[i]
+			index[i] = 0
+		}
+	}
+	return offsets
+}
+
+// gather returns a new column vector, of the same type of the storage,
+// with a copy of the elements of the tensor, in row-major order.
+func (t *Tensor) gather() Matrix {
+	offsets := t.offsets()
+	if s, ok := t.storage.(*Dense[float32]); ok {
+		return gatherDense(s.data, offsets)
+	}
+	return gatherDense(Data[float64](t.storage), offsets)
+}
+
+func gatherDense[T float.DType](data []T, offsets []int) *Dense[T] {
+	out := densePool[T]().Get(len(offsets), 1)
+	for i, o := range offsets {
+		out.data[i] = data[o]
+	}
+	return out
+}
+
+// scatterAdd adds each element of the data of src, at the position
+// srcOffsets[i], to the element of the data of dst at the position
+// dstOffsets[i].
+func scatterAdd(dst, src Matrix, dstOffsets, srcOffsets []int) {
+	if d, ok := dst.(*Dense[float32]); ok {
+		scatterAddDense(d.data, Data[float32](src), dstOffsets, srcOffsets)
+		return
+	}
+	dstData := Data[float64](dst)
+	scatterAddDense(dstData, Data[float64](src), dstOffsets, srcOffsets)
+	if _, ok := dst.(*Dense[float64]); !ok {
+		SetData(dst, dstData)
+	}
+}
+
+func scatterAddDense[T float.DType](dst, src []T, dstOffsets, srcOffsets []int) {
+	for i, o := range srcOffsets {
+		dst[dstOffsets[i]] += src[o]
+	}
+}
+
+func checkShape(shape []int) {
+	for _, d := range shape {
+		if d < 0 {
+			panic(fmt.Sprintf("mat: invalid tensor shape %v", shape))
+		}
+	}
+}
+
+// ResolveShape returns a copy of the shape, where the dimension -1, if
+// any, is inferred from the given size. It panics if the size of the
+// shape differs from the given size.
+func ResolveShape(shape []int, size int) []int {
+	shape = append([]int{}, shape...)
+	inferred := -1
+	known := 1
+	for i, d := range shape {
+		switch {
+		case d == -1 && inferred == -1:
+			inferred = i
+		case d < 0:
+			panic(fmt.Sprintf("mat: invalid tensor shape %v", shape))
+		default:
+			known *= d
+		}
+	}
+	if inferred != -1 && known != 0 && size%known == 0 {
+		shape[inferred] = size / known
+		known = size
+	}
+	if inferred != -1 && shape[inferred] == -1 || known != size {
+		panic(fmt.Sprintf("mat: cannot view %d elements as a tensor with shape %v", size, shape))
+	}
+	return shape
+}
+
+func contiguousStrides(shape []int) []int {
+	strides := make([]int, len(shape))
+	stride := 1
+	for i := len(shape) - 1; i >= 0; i-- {
+		strides[i] = stride
+		stride *= shape[i]
+	}
+	return strides
+}
+
+func canBroadcastShape(from, to []int) bool {
+	n := len(to) - len(from)
+	if n < 0 {
+		return false
+	}
+	for i, d := range from {
+		if d != 1 && d != to[n+i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ShapeSize returns the number of elements of a tensor with the given
+// shape.
+func ShapeSize(shape []int) int {
+	size := 1
+	for _, d := range shape {
+		size *= d
+	}
+	return size
+}
@@ -0,0 +1,274 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTensor(t *testing.T) {
+	t.Run("float32", testNewTensor[float32])
+	t.Run("float64", testNewTensor[float64])
+}
+
+func testNewTensor[T float.DType](t *testing.T) {
+	x := NewTensor([]int{2, 3, 2}, []T{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12})
+	assert.Equal(t, []int{2, 3, 2}, x.Shape())
+	assert.Equal(t, []int{6, 2, 1}, x.Strides())
+	assert.Equal(t, 3, x.Rank())
+	assert.Equal(t, 12, x.Size())
+	assert.Equal(t, 3, x.Dim(1))
+	assert.True(t, x.IsContiguous())
+	assert.Equal(t, T(11), float.ValueOf[T](x.At(1, 2, 0)))
+	assert.Equal(t, []T{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, Data[T](x.Matrix()))
+
+	x.SetAt(float.Interface(T(42)), 0, 1, 1)
+	assert.Equal(t, T(42), float.ValueOf[T](x.At(0, 1, 1)))
+
+	assert.Panics(t, func() { x.At(0, 3, 0) })
+	assert.Panics(t, func() { x.At(0, 1) })
+	assert.Panics(t, func() { NewTensor([]int{2, 2}, []T{1, 2, 3}) })
+	assert.Panics(t, func() { NewEmptyTensor[T](2, -1) })
+
+	s := NewTensor([]int{}, []T{7})
+	assert.Equal(t, 0, s.Rank())
+	assert.Equal(t, T(7), float.ValueOf[T](s.At()))
+}
+
+func TestTensorView(t *testing.T) {
+	t.Run("float32", testTensorView[float32])
+	t.Run("float64", testTensorView[float64])
+}
+
+func testTensorView[T float.DType](t *testing.T) {
+	m := NewDense(2, 3, []T{1, 2, 3, 4, 5, 6})
+
+	x := TensorView(m)
+	assert.Equal(t, []int{2, 3}, x.Shape())
+
+	y := TensorView(m, 3, -1)
+	assert.Equal(t, []int{3, 2}, y.Shape())
+	assert.Equal(t, T(4), float.ValueOf[T](y.At(1, 1)))
+
+	// the data is shared
+	m.SetScalar(1, 0, float.Interface(T(40)))
+	assert.Equal(t, T(40), float.ValueOf[T](y.At(1, 1)))
+
+	assert.Panics(t, func() { TensorView(m, 4, -1) })
+	assert.Panics(t, func() { TensorView(m, -1, -1) })
+	assert.Panics(t, func() { TensorView(m, 2, 2) })
+}
+
+func TestTensorMatrixDims(t *testing.T) {
+	testCases := []struct {
+		shape      []int
+		rows, cols int
+	}{
+		{[]int{}, 1, 1},
+		{[]int{5}, 5, 1},
+		{[]int{2, 3}, 2, 3},
+		{[]int{2, 3, 4}, 6, 4},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.shape), func(t *testing.T) {
+			rows, cols := TensorMatrixDims(tc.shape)
+			assert.Equal(t, tc.rows, rows)
+			assert.Equal(t, tc.cols, cols)
+		})
+	}
+}
+
+func TestShapeSize(t *testing.T) {
+	assert.Equal(t, 1, ShapeSize([]int{}))
+	assert.Equal(t, 5, ShapeSize([]int{5}))
+	assert.Equal(t, 24, ShapeSize([]int{2, 3, 4}))
+	assert.Equal(t, 0, ShapeSize([]int{2, 0}))
+}
+
+func TestResolveShape(t *testing.T) {
+	testCases := []struct {
+		shape    []int
+		size     int
+		expected []int
+	}{
+		{[]int{2, 3}, 6, []int{2, 3}},
+		{[]int{-1, 3}, 6, []int{2, 3}},
+		{[]int{2, -1, 2}, 12, []int{2, 3, 2}},
+		{[]int{-1}, 0, []int{0}},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.shape), func(t *testing.T) {
+			shape := ResolveShape(tc.shape, tc.size)
+			assert.Equal(t, tc.expected, shape)
+			assert.NotSame(t, &tc.shape[0], &shape[0])
+		})
+	}
+
+	assert.Panics(t, func() { ResolveShape([]int{2, 3}, 5) })
+	assert.Panics(t, func() { ResolveShape([]int{-1, 4}, 6) })
+	assert.Panics(t, func() { ResolveShape([]int{-1, -1}, 6) })
+	assert.Panics(t, func() { ResolveShape([]int{-2, 3}, 6) })
+}
+
+func TestBroadcastShapes(t *testing.T) {
+	testCases := []struct {
+		a, b  []int
+		shape []int
+		ok    bool
+	}{
+		{[]int{2, 3}, []int{2, 3}, []int{2, 3}, true},
+		{[]int{4, 2, 3}, []int{3}, []int{4, 2, 3}, true},
+		{[]int{1, 3}, []int{4, 1, 1}, []int{4, 1, 3}, true},
+		{[]int{}, []int{2, 2}, []int{2, 2}, true},
+		{[]int{2, 3}, []int{2}, nil, false},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v, %v", tc.a, tc.b), func(t *testing.T) {
+			shape, ok := BroadcastShapes(tc.a, tc.b)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.shape, shape)
+		})
+	}
+}
+
+func TestTensor_Reshape(t *testing.T) {
+	t.Run("float32", testTensorReshape[float32])
+	t.Run("float64", testTensorReshape[float64])
+}
+
+func testTensorReshape[T float.DType](t *testing.T) {
+	x := NewTensor([]int{2, 3}, []T{1, 2, 3, 4, 5, 6})
+
+	y := x.Reshape(3, 1, -1)
+	assert.Equal(t, []int{3, 1, 2}, y.Shape())
+	assert.Same(t, x.storage, y.storage)
+	assert.Equal(t, []T{1, 2, 3, 4, 5, 6}, Data[T](y.Matrix()))
+
+	// a non-contiguous tensor is copied
+	z := x.Transpose(0, 1).Reshape(6)
+	assert.NotSame(t, x.storage, z.storage)
+	assert.Equal(t, []T{1, 4, 2, 5, 3, 6}, Data[T](z.Matrix()))
+
+	assert.Panics(t, func() { x.Reshape(4, -1) })
+}
+
+func TestTensor_Permute(t *testing.T) {
+	t.Run("float32", testTensorPermute[float32])
+	t.Run("float64", testTensorPermute[float64])
+}
+
+func testTensorPermute[T float.DType](t *testing.T) {
+	x := NewTensor([]int{2, 3, 2}, []T{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12})
+
+	y := x.Permute(2, 0, 1)
+	assert.Equal(t, []int{2, 2, 3}, y.Shape())
+	assert.Equal(t, []int{1, 6, 2}, y.Strides())
+	assert.False(t, y.IsContiguous())
+	assert.Same(t, x.storage, y.storage)
+
+	m := y.Matrix()
+	assert.Equal(t, 4, m.Rows())
+	assert.Equal(t, 3, m.Columns())
+	assert.Equal(t, []T{1, 3, 5, 7, 9, 11, 2, 4, 6, 8, 10, 12}, Data[T](m))
+
+	c := y.Contiguous()
+	assert.True(t, c.IsContiguous())
+	assert.Equal(t, T(8), float.ValueOf[T](c.At(1, 1, 0)))
+	assert.Same(t, c, c.Contiguous())
+
+	assert.Panics(t, func() { x.Permute(0, 1) })
+	assert.Panics(t, func() { x.Permute(0, 1, 1) })
+	assert.Panics(t, func() { x.Permute(0, 1, 3) })
+}
+
+func TestTensor_Squeeze(t *testing.T) {
+	t.Run("float32", testTensorSqueeze[float32])
+	t.Run("float64", testTensorSqueeze[float64])
+}
+
+func testTensorSqueeze[T float.DType](t *testing.T) {
+	x := NewTensor([]int{2, 3}, []T{1, 2, 3, 4, 5, 6})
+
+	y := x.Unsqueeze(1)
+	assert.Equal(t, []int{2, 1, 3}, y.Shape())
+	assert.True(t, y.IsContiguous())
+	assert.Equal(t, T(5), float.ValueOf[T](y.At(1, 0, 1)))
+
+	z := y.Squeeze(1)
+	assert.Equal(t, []int{2, 3}, z.Shape())
+	assert.Equal(t, []T{1, 2, 3, 4, 5, 6}, Data[T](z.Matrix()))
+
+	assert.Panics(t, func() { x.Squeeze(0) })
+	assert.Panics(t, func() { x.Unsqueeze(3) })
+}
+
+func TestTensor_BroadcastTo(t *testing.T) {
+	t.Run("float32", testTensorBroadcastTo[float32])
+	t.Run("float64", testTensorBroadcastTo[float64])
+}
+
+func testTensorBroadcastTo[T float.DType](t *testing.T) {
+	x := NewTensor([]int{3, 1}, []T{1, 2, 3})
+
+	y := x.BroadcastTo(2, 3, 2)
+	assert.Equal(t, []int{2, 3, 2}, y.Shape())
+	assert.Equal(t, []int{0, 1, 0}, y.Strides())
+	assert.Equal(t, []T{1, 1, 2, 2, 3, 3, 1, 1, 2, 2, 3, 3}, Data[T](y.Matrix()))
+
+	s := y.SumTo(3, 1)
+	assert.Equal(t, []int{3, 1}, s.Shape())
+	assert.Equal(t, []T{4, 8, 12}, Data[T](s.Matrix()))
+
+	s = y.SumTo(1, 2)
+	assert.Equal(t, []T{12, 12}, Data[T](s.Matrix()))
+
+	assert.Panics(t, func() { x.BroadcastTo(3, 2, 2) })
+	assert.Panics(t, func() { y.SumTo(2, 1, 1, 1) })
+}
+
+func TestTensor_ElementWise(t *testing.T) {
+	t.Run("float32", testTensorElementWise[float32])
+	t.Run("float64", testTensorElementWise[float64])
+}
+
+func testTensorElementWise[T float.DType](t *testing.T) {
+	a := NewTensor([]int{2, 1, 3}, []T{1, 2, 3, 4, 5, 6})
+	b := NewTensor([]int{2, 1}, []T{10, 20})
+
+	testCases := []struct {
+		name     string
+		f        func(a, b *Tensor) *Tensor
+		expected []T
+	}{
+		{"Add", (*Tensor).Add, []T{11, 12, 13, 21, 22, 23, 14, 15, 16, 24, 25, 26}},
+		{"Sub", (*Tensor).Sub, []T{-9, -8, -7, -19, -18, -17, -6, -5, -4, -16, -15, -14}},
+		{"Prod", (*Tensor).Prod, []T{10, 20, 30, 20, 40, 60, 40, 50, 60, 80, 100, 120}},
+		{"Div", (*Tensor).Div, []T{0.1, 0.2, 0.3, 0.05, 0.1, 0.15, 0.4, 0.5, 0.6, 0.2, 0.25, 0.3}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			y := tc.f(a, b)
+			require.Equal(t, []int{2, 2, 3}, y.Shape())
+			assert.InDeltaSlice(t, tc.expected, Data[T](y.Matrix()), 1.0e-6)
+		})
+	}
+
+	// permuted operands
+	y := a.Squeeze(1).Transpose(0, 1).Add(b.Reshape(2))
+	assert.Equal(t, []int{3, 2}, y.Shape())
+	assert.Equal(t, []T{11, 24, 12, 25, 13, 26}, Data[T](y.Matrix()))
+
+	assert.Panics(t, func() { a.Add(NewEmptyTensor[T](2)) })
+}
+
+func TestTensor_String(t *testing.T) {
+	x := NewTensor([]int{2, 2}, []float64{1, 2, 3, 4})
+	assert.Equal(t, "Tensor[2 2][1 2 3 4]", x.String())
+}
@@ -6,53 +6,64 @@ package attention
 
 import (
 	"math"
-	"sync"
 
 	"github.com/nlpodyssey/spago/ag"
-	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat"
 )
 
 // ScaledDotProductAttention is a self-attention mechanism relating different positions of a single
 // sequence to compute a representation of the same sequence.
 // This method requires that the query, the key and the value vectors have already been obtained
 // from the input sequence. The scaled factor is the square root of the dimension of the key vectors.
+//
+// The keys k and the values v are matrices where each row is a key or a value vector. All the queries
+// are computed at once (see BatchedScaledDotProductAttention), and the attention vectors and the
+// attention weights of each query are returned.
 func ScaledDotProductAttention(q []ag.Node, k, v, scaleFactor ag.Node, useCausalMask bool) ([]ag.Node, []ag.Node) {
-	nodes := make([]ag.Node, len(q)*2)
-	attention := nodes[:len(q)]
-	weights := nodes[len(q):]
-
-	causalMaskEnabled := useCausalMask && len(q) > 1
-	kRows := k.Value().Rows()
-
-	var wg sync.WaitGroup
-	wg.Add(len(q))
-
-	for i, qi := range q {
-		go func(i int, qi ag.Node) {
-			scores := ag.ProdScalar(ag.Mul(k, qi), scaleFactor)
-
-			if causalMaskEnabled {
-				causalMask := k.Value().NewVec(float.SliceInterface(makeCausalMask(i, kRows))) // TODO: use external cache for causal mask?
-				scores = ag.Add(scores, ag.Var(causalMask))
-			}
+	if len(q) == 0 {
+		return nil, nil
+	}
+	attention, weights := BatchedScaledDotProductAttention(ag.Stack(q...), k, v, scaleFactor, 1, useCausalMask)
+	return ag.ColViews(attention), ag.ColViews(ag.T(weights))
+}
 
-			weights[i] = ag.Softmax(scores)
-			attention[i] = ag.MulT(v, weights[i])
-			wg.Done()
-		}(i, qi)
+// BatchedScaledDotProductAttention computes the scaled dot-product attention of one or more heads
+// with a handful of operators, using batched matrix multiplications (see ag.BatchMul).
+//
+// The queries q, the keys k and the values v are stacks of matrices, one for each head, where each
+// row is a query, key or value vector; their dimensions are (heads·n)×dk, (heads·m)×dk and
+// (heads·m)×dv respectively.
+//
+// It returns the attention, with dimensions (heads·dv)×n, where each column is the concatenation of
+// the attention vectors of all the heads for a query, and the attention weights, with dimensions
+// (heads·n)×m, where each row contains the weights of a query for a head.
+func BatchedScaledDotProductAttention(q, k, v, scaleFactor ag.Node, heads int, useCausalMask bool) (attention, weights ag.Node) {
+	scores := ag.ProdScalar(ag.BatchMul(q, k, heads, false, true), scaleFactor)
+
+	n := q.Value().Rows() / heads
+	if useCausalMask && n > 1 {
+		m := k.Value().Rows() / heads
+		causalMask := mat.NewMaskDense[float64](heads*n, m, makeCausalMask(heads, n, m)) // TODO: use external cache for causal mask?
+		scores = ag.MaskedFill(scores, causalMask, math.Inf(-1))
+		mat.ReleaseMatrix(causalMask)
 	}
 
-	wg.Wait()
+	weights = ag.RowwiseSoftmax(scores)
+	attention = ag.BatchMul(v, weights, heads, true, true)
 	return attention, weights
 }
 
-// makeCausalMask returns a slice of size seqLength filled with zeros until curIndex, and the rest with -inf.
-// FIXME: avoid specific float64 type, later passed to NewVec
-func makeCausalMask(curIndex, seqLength int) []float64 {
-	negInf := math.Inf(-1)
-	causalMask := make([]float64, seqLength)
-	for k := curIndex + 1; k < seqLength; k++ {
-		causalMask[k] = negInf
+// makeCausalMask returns the values of a (heads·n)×m mask, in row-major order, which are true
+// after the column i of the row i of each head, that is, for the keys following each query.
+func makeCausalMask(heads, n, m int) []bool {
+	causalMask := make([]bool, heads*n*m)
+	for h := 0; h < heads; h++ {
+		for i := 0; i < n; i++ {
+			row := causalMask[(h*n+i)*m : (h*n+i+1)*m]
+			for k := i + 1; k < m; k++ {
+				row[k] = true
+			}
+		}
 	}
 	return causalMask
 }
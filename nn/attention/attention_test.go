@@ -48,6 +48,43 @@ func testScaledDotProductAttention[T float.DType](t *testing.T) {
 	assert.InDeltaSlice(t, []T{2.20423303670527, 8.41210390591632, 0.152898186332002}, results[2].Value().Data(), 1.0e-5)
 }
 
+func TestScaledDotProductAttention_CausalMask(t *testing.T) {
+	t.Run("float32", testScaledDotProductAttentionCausalMask[float32])
+	t.Run("float64", testScaledDotProductAttentionCausalMask[float64])
+}
+
+func testScaledDotProductAttentionCausalMask[T float.DType](t *testing.T) {
+	queries := []ag.Node{
+		ag.Var(mat.NewVecDense([]T{1.1, 0.0, 2.3})).WithGrad(true),
+		ag.Var(mat.NewVecDense([]T{2.2, -0.5, 0.3})).WithGrad(true),
+		ag.Var(mat.NewVecDense([]T{3.2, 0.5, 0.4})).WithGrad(true),
+	}
+	keys := ag.Var(mat.NewDense(3, 3, []T{
+		0.0, 1.2, 1.3,
+		4.5, 4.3, 0.2,
+		2.7, 3.6, 2.1,
+	})).WithGrad(true)
+	values := ag.Var(mat.NewDense(3, 3, []T{
+		1.2, 2.3, 3.4,
+		2.2, 8.5, 0.0,
+		2.3, 6.5, 3.5,
+	})).WithGrad(true)
+
+	scaleFactor := nn.Const(T(1.0 / math.Sqrt(3)))
+	results, weights := ScaledDotProductAttention(queries, keys, values, scaleFactor, true)
+
+	// each query only attends to the keys up to its own position
+	assert.InDeltaSlice(t, []T{1, 0, 0}, weights[0].Value().Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{1.2, 2.3, 3.4}, results[0].Value().Data(), 1.0e-6)
+	assert.InDelta(t, 0, weights[1].Value().ScalarAt(2, 0).F64(), 1.0e-6)
+	assert.InDelta(t, 1, weights[1].Value().Sum().Scalar().F64(), 1.0e-6)
+	assert.InDeltaSlice(t, mat.Data[T](ag.Softmax(ag.ProdScalar(ag.Mul(keys, queries[2]), scaleFactor)).Value()), weights[2].Value().Data(), 1.0e-6)
+
+	ag.Backward(results[1], mat.NewVecDense([]T{1, 1, 1}))
+	assert.InDeltaSlice(t, []T{0, 0, 0}, values.Grad().ExtractRow(2).Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{0, 0, 0}, keys.Grad().ExtractRow(2).Data(), 1.0e-6)
+}
+
 //gocyclo:ignore
 func TestScaledDotProductAttention2(t *testing.T) {
 	t.Run("float32", testScaledDotProductAttention2[float32])
@@ -9,7 +9,6 @@ import (
 
 	"github.com/nlpodyssey/spago/ag"
 	"github.com/nlpodyssey/spago/nn"
-	"github.com/nlpodyssey/spago/nn/attention/selfattention"
 )
 
 var _ nn.Model = &CrossAttention{}
@@ -25,17 +24,5 @@ func init() {
 
 // Forward performs the forward step for each input node and returns the result.
 func (m *CrossAttention) Forward(cache Cache, seq1 []ag.Node, seq2 []ag.Node) ([]ag.Node, [][]ag.Node, Cache) {
-	n := len(m.Heads)
-	attentions := make([][]ag.Node, n)
-	weights := make([][]ag.Node, n)
-	nextCache := make(Cache, n)
-
-	for i, h := range m.Heads {
-		ca := selfattention.CrossAttention{Model: h}
-		attentions[i], weights[i], nextCache[i] = ca.Forward(cache.At(i), seq1, seq2)
-	}
-
-	projected := m.project(attentions, len(seq1))
-
-	return projected, weights, nextCache
+	return m.Model.Forward(cache, seq1, seq2, seq2)
 }
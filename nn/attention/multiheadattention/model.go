@@ -14,6 +14,7 @@ import (
 	"github.com/nlpodyssey/spago/mat/rand"
 	"github.com/nlpodyssey/spago/nn"
 	"github.com/nlpodyssey/spago/nn/activation"
+	"github.com/nlpodyssey/spago/nn/attention"
 	"github.com/nlpodyssey/spago/nn/attention/selfattention"
 	"github.com/nlpodyssey/spago/nn/linear"
 )
@@ -77,31 +78,50 @@ func (r Cache) At(i int) selfattention.Cache {
 }
 
 // Forward performs the forward step for each input node and returns the result.
+//
+// The attention of all the heads is computed at once, stacking the projected
+// queries, keys and values of the heads (see attention.BatchedScaledDotProductAttention).
 func (m *Model) Forward(cache Cache, q, k, v []ag.Node) ([]ag.Node, [][]ag.Node, Cache) {
 	n := len(m.Heads)
-	attentions := make([][]ag.Node, n)
-	weights := make([][]ag.Node, n)
+	queries := make([]ag.Node, 0, n*len(q))
+	keys := make([]ag.Node, n)
+	values := make([]ag.Node, n)
 	nextCache := make(Cache, n)
 
 	for i, h := range m.Heads {
-		attentions[i], weights[i], nextCache[i] = h.Forward(cache.At(i), q, k, v)
+		var pq []ag.Node
+		pq, keys[i], values[i] = h.Project(cache.At(i), q, k, v)
+		queries = append(queries, pq...)
+		nextCache[i] = selfattention.Cache{keys[i], values[i]}
 	}
 
-	projected := m.project(attentions, len(q))
+	head := m.Heads[0]
+	result, weights := attention.BatchedScaledDotProductAttention(
+		ag.Stack(queries...), stackRows(keys), stackRows(values), head.ScaleFactor, n, head.UseCausalMask)
 
-	return projected, weights, nextCache
+	// each column of the attention is the concatenation of the heads for a query
+	projected := m.OutputMerge.Forward(ag.ColViews(result)...)
+
+	weightsByHead := make([][]ag.Node, n)
+	ws := ag.ColViews(ag.T(weights))
+	for i := range weightsByHead {
+		weightsByHead[i] = ws[i*len(q) : (i+1)*len(q)]
+	}
+
+	return projected, weightsByHead, nextCache
 }
 
-func (m *Model) project(heads [][]ag.Node, seqLen int) []ag.Node {
-	n := len(heads)
-	concat := make([]ag.Node, seqLen)
-	buf := make([]ag.Node, n*seqLen)
-	for i := 0; i < seqLen; i++ {
-		buf2 := buf[i*n : i*n+n]
-		for j := 0; j < n; j++ {
-			buf2[j] = heads[j][i]
-		}
-		concat[i] = ag.Concat(buf2...)
+// stackRows returns a matrix with the rows of all the given matrices, which
+// must have the same number of columns.
+func stackRows(ms []ag.Node) ag.Node {
+	if len(ms) == 1 {
+		return ms[0]
+	}
+	rows, cols := 0, ms[0].Value().Columns()
+	flat := make([]ag.Node, len(ms))
+	for i, m := range ms {
+		rows += m.Value().Rows()
+		flat[i] = ag.Flatten(m)
 	}
-	return m.OutputMerge.Forward(concat...)
+	return ag.Reshape(ag.Concat(flat...), rows, cols)
 }
@@ -0,0 +1,70 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package multiheadattention
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/nlpodyssey/spago/nn/attention/selfattention"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfAttention_Forward(t *testing.T) {
+	t.Run("float32", testSelfAttentionForward[float32])
+	t.Run("float64", testSelfAttentionForward[float64])
+}
+
+func testSelfAttentionForward[T float.DType](t *testing.T) {
+	for _, useCausalMask := range []bool{false, true} {
+		model := New[T](4, 2, useCausalMask, false)
+		model.Init(rand.NewLockedRand(42))
+		sa := SelfAttention{Model: model}
+
+		xs := []ag.Node{
+			ag.Var(mat.NewVecDense([]T{-0.8, -0.9, -0.9, 1.0})),
+			ag.Var(mat.NewVecDense([]T{0.8, -0.3, 0.5, 0.3})),
+			ag.Var(mat.NewVecDense([]T{-0.2, 0.7, 0.2, 0.4})),
+		}
+
+		output, weights, cache := sa.Forward(nil, xs[:2])
+		assertSameAsHeads(t, model, nil, xs[:2], output, weights)
+
+		// incremental step with the cached keys and values
+		output, weights, _ = sa.Forward(cache, xs[2:])
+		assertSameAsHeads(t, model, cache, xs[2:], output, weights)
+	}
+}
+
+// assertSameAsHeads checks the results of the batched computation against
+// the attention computed separately by each head.
+func assertSameAsHeads(t *testing.T, m *Model, cache Cache, xs, output []ag.Node, weights [][]ag.Node) {
+	t.Helper()
+	require.Len(t, output, len(xs))
+	require.Len(t, weights, len(m.Heads))
+
+	heads := make([][]ag.Node, len(m.Heads))
+	for i, h := range m.Heads {
+		var w []ag.Node
+		heads[i], w, _ = selfattention.SelfAttention{Model: h}.Forward(cache.At(i), xs)
+		require.Len(t, weights[i], len(xs))
+		for j := range w {
+			assert.InDeltaSlice(t, w[j].Value().Data().F64(), weights[i][j].Value().Data().F64(), 1.0e-6)
+		}
+	}
+
+	for j := range xs {
+		concat := make([]ag.Node, len(heads))
+		for i := range heads {
+			concat[i] = heads[i][j]
+		}
+		expected := m.OutputMerge.Forward(ag.Concat(concat...))[0]
+		assert.InDeltaSlice(t, expected.Value().Data().F64(), output[j].Value().Data().F64(), 1.0e-6)
+	}
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/nlpodyssey/spago/ag"
 	"github.com/nlpodyssey/spago/nn"
-	"github.com/nlpodyssey/spago/nn/attention/selfattention"
 )
 
 var _ nn.Model = &SelfAttention{}
@@ -25,17 +24,5 @@ func init() {
 
 // Forward performs the forward step for each input node and returns the result.
 func (m *SelfAttention) Forward(cache Cache, xs []ag.Node) ([]ag.Node, [][]ag.Node, Cache) {
-	n := len(m.Heads)
-	attentions := make([][]ag.Node, n)
-	weights := make([][]ag.Node, n)
-	nextCache := make(Cache, n)
-
-	for i, h := range m.Heads {
-		sa := selfattention.SelfAttention{Model: h}
-		attentions[i], weights[i], nextCache[i] = sa.Forward(cache.At(i), xs)
-	}
-
-	projected := m.project(attentions, len(xs))
-
-	return projected, weights, nextCache
+	return m.Model.Forward(cache, xs, xs, xs)
 }
@@ -73,9 +73,16 @@ func (m *Model) Init(rng *rand.LockedRand) {
 
 // Forward performs the forward step for each input node and returns the result.
 func (m *Model) Forward(cache Cache, q, k, v []ag.Node) ([]ag.Node, []ag.Node, Cache) {
-	var pk, pv ag.Node
+	pq, pk, pv := m.Project(cache, q, k, v)
+	result, weights := attention.ScaledDotProductAttention(pq, pk, pv, m.ScaleFactor, m.UseCausalMask)
+	return result, weights, Cache{pk, pv}
+}
 
-	pq := m.Query.Forward(q...)
+// Project returns the projected queries, and the matrices of the projected keys and values,
+// where each row is a key or a value vector. The keys and the values are taken from the cache
+// in case of cross-attention, or appended to the cached ones otherwise.
+func (m *Model) Project(cache Cache, q, k, v []ag.Node) (pq []ag.Node, pk, pv ag.Node) {
+	pq = m.Query.Forward(q...)
 
 	if hasCache := cache.HasValues(); hasCache && m.IsCrossAttention {
 		pk = cache[0]
@@ -92,8 +99,5 @@ func (m *Model) Forward(cache Cache, q, k, v []ag.Node) ([]ag.Node, []ag.Node, C
 			pv = ag.Stack(fwValues...)
 		}
 	}
-
-	result, weights := attention.ScaledDotProductAttention(pq, pk, pv, m.ScaleFactor, m.UseCausalMask)
-
-	return result, weights, Cache{pk, pv}
+	return pq, pk, pv
 }
@@ -7,11 +7,16 @@ package nn
 import (
 	"sync"
 
+	"github.com/nlpodyssey/spago/ag"
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/mat/float"
 )
 
-var _ Param = &BaseParam{}
+var (
+	_ Param                  = &BaseParam{}
+	_ ag.GradNodeAccumulator = &BaseParam{}
+	_ ag.GradHookRegistry    = &BaseParam{}
+)
 
 // BaseParam is the default implementation satisfying the Param interface.
 type BaseParam struct {
@@ -19,7 +24,9 @@ type BaseParam struct {
 	pType        ParamsType // lazy initialization
 	value        mat.Matrix // store the results of a forward evaluation.
 	grad         mat.Matrix
-	payload      *Payload // additional data used for example by gradient-descend optimization methods
+	gradNode     ag.Node      // gradients expressed as a node, accumulated by ag.BackwardGraph
+	gradHooks    ag.GradHooks // called by AccGrad
+	payload      *Payload     // additional data used for example by gradient-descend optimization methods
 	requiresGrad bool
 	// Allows thread-safe locking for operations on value.
 	valueMu sync.RWMutex
@@ -102,11 +109,15 @@ func (p *BaseParam) Grad() mat.Matrix {
 	return p.grad
 }
 
-// AccGrad accumulate the gradients
+// AccGrad accumulate the gradients.
+// The gradients are first passed through the registered hooks, if any.
 func (p *BaseParam) AccGrad(grad mat.Matrix) {
 	if !p.requiresGrad {
 		return
 	}
+	if grad = p.gradHooks.Apply(grad); grad == nil {
+		return
+	}
 	p.gradMu.Lock()
 	defer p.gradMu.Unlock()
 	if p.grad == nil {
@@ -116,6 +127,45 @@ func (p *BaseParam) AccGrad(grad mat.Matrix) {
 	p.grad.AddInPlace(grad)
 }
 
+// RegisterGradHook registers a gradient hook, which is called each time some
+// gradients are accumulated into the param (e.g. to mask the gradients of
+// frozen rows of an embeddings matrix).
+func (p *BaseParam) RegisterGradHook(hook ag.GradHook) ag.RemoveGradHookFunc {
+	return p.gradHooks.Register(hook)
+}
+
+// AccGradNode accumulates the gradients, expressed as a Node, into the param.
+// The value of gx is also accumulated as with AccGrad.
+// The gradients are first passed through the registered hooks, if any (see
+// ag.GradHooks.ApplyNode).
+func (p *BaseParam) AccGradNode(gx ag.Node) {
+	if !p.requiresGrad {
+		return
+	}
+	if gx = p.gradHooks.ApplyNode(gx); gx == nil {
+		return
+	}
+	p.gradMu.Lock()
+	defer p.gradMu.Unlock()
+	if p.gradNode == nil {
+		p.gradNode = gx
+	} else {
+		p.gradNode = ag.Add(p.gradNode, gx)
+	}
+	if p.grad == nil {
+		p.grad = gx.Value().Clone()
+		return
+	}
+	p.grad.AddInPlace(gx.Value())
+}
+
+// GradNode returns the gradients accumulated with AccGradNode, or nil.
+func (p *BaseParam) GradNode() ag.Node {
+	p.gradMu.RLock()
+	defer p.gradMu.RUnlock()
+	return p.gradNode
+}
+
 // HasGrad returns true if there are accumulated gradients.
 func (p *BaseParam) HasGrad() bool {
 	p.gradMu.RLock()
@@ -142,6 +192,7 @@ func (p *BaseParam) ZeroGrad() {
 	defer p.gradMu.Unlock()
 	mat.ReleaseMatrix(p.grad)
 	p.grad = nil
+	p.gradNode = nil
 }
 
 // ApplyDelta updates the value applying the delta.
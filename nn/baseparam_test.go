@@ -0,0 +1,42 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseParam_RegisterGradHook(t *testing.T) {
+	t.Run("float32", testBaseParamRegisterGradHook[float32])
+	t.Run("float64", testBaseParamRegisterGradHook[float64])
+}
+
+func testBaseParamRegisterGradHook[T float.DType](t *testing.T) {
+	// An embeddings matrix whose first row is frozen.
+	p := NewParam(mat.NewDense(2, 2, []T{1, 2, 3, 4}))
+	mask := mat.NewDense(2, 2, []T{0, 0, 1, 1})
+	remove := p.RegisterGradHook(func(grad mat.Matrix) mat.Matrix {
+		return grad.Prod(mask)
+	})
+
+	x := ag.Var(mat.NewVecDense([]T{1, 2}))
+	ag.Backward(ag.ReduceSum(ag.Mul(p, x)))
+	assert.InDeltaSlice(t, []T{0, 0, 1, 2}, p.Grad().Data(), 1.0e-6)
+
+	p.ZeroGrad()
+	ag.BackwardGraph(ag.ReduceSum(ag.Mul(p, x)))
+	assert.InDeltaSlice(t, []T{0, 0, 1, 2}, p.GradNode().Value().Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{0, 0, 1, 2}, p.Grad().Data(), 1.0e-6)
+
+	p.ZeroGrad()
+	remove()
+	ag.Backward(ag.ReduceSum(ag.Mul(p, x)))
+	assert.InDeltaSlice(t, []T{1, 2, 1, 2}, p.Grad().Data(), 1.0e-6)
+}
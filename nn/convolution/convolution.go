@@ -6,11 +6,12 @@ package convolution
 
 import (
 	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/ag/fn"
 )
 
-// Conv1D performs a 1D convolution.
+// Conv1D performs a 1D convolution, sliding the kernel w along the columns
+// of x, with which it shares the number of rows. The result is a row vector.
 func Conv1D(w, x ag.Node, stride int) ag.Node {
-	var dim int
 	wr, wc := w.Value().Rows(), w.Value().Columns()
 	xr, xc := x.Value().Rows(), x.Value().Columns()
 	if (xc-wc)%stride != 0 {
@@ -19,39 +20,48 @@ func Conv1D(w, x ag.Node, stride int) ag.Node {
 	if xr != wr {
 		panic("Incompatible stride value for rows")
 	}
-	dim = (xc-wc)/stride + 1
-	ys := make([]ag.Node, dim)
-	for i := 0; i < dim; i++ {
-		fromCol := i * stride
-		ys[i] = ag.Dot(ag.Slice(x, 0, fromCol, wr, fromCol+wc), w)
-	}
-	return ag.Concat(ys...)
+	// the rows of x are the input channels of a single kernel
+	config := fn.ConvConfig{Stride: []int{stride}}
+	return ag.TensorConv1D(ag.NewTensor(x, xr, xc), ag.NewTensor(w, 1, wr, wc), config).Node()
 }
 
 // Conv2D performs a 2D convolution.
 func Conv2D(w, x ag.Node, xStride, yStride int) ag.Node {
-	var dimx, dimy int
 	if (x.Value().Rows()-w.Value().Rows())%xStride != 0 {
 		panic("Incompatible stride value for rows")
 	}
 	if (x.Value().Columns()-w.Value().Columns())%yStride != 0 {
 		panic("Incompatible stride value for columns")
 	}
-	dimx = (x.Value().Rows()-w.Value().Rows())/xStride + 1
-	dimy = (x.Value().Columns()-w.Value().Columns())/yStride + 1
-
+	xRows, xCols := x.Value().Dims()
 	wRows, wCols := w.Value().Dims()
+	config := fn.ConvConfig{Stride: []int{xStride, yStride}}
+	return ag.TensorConv2D(ag.NewTensor(x, 1, xRows, xCols), ag.NewTensor(w, 1, 1, wRows, wCols), config).Node()
+}
+
+// Groups returns the given number of groups of input and output channels,
+// or the default value 1 if it is zero.
+func Groups(groups int) int {
+	if groups == 0 {
+		return 1
+	}
+	return groups
+}
 
-	var outList []ag.Node
-	for i := 0; i < dimx; i++ {
-		for j := 0; j < dimy; j++ {
-			fromRow := i * xStride
-			fromCol := j * yStride
-			var view = ag.Slice(x, fromRow, fromCol, fromRow+wRows, fromCol+wCols)
-			var dotProduct = ag.Dot(view, w)
-			outList = append(outList, dotProduct)
-		}
+// Dilation returns the given dilation, or the default value 1 if it is zero.
+func Dilation(d int) int {
+	if d == 0 {
+		return 1
 	}
+	return d
+}
 
-	return ag.Reshape(ag.Concat(outList...), dimx, dimy)
+// ConcatFlat returns the values of the nodes, in row-major order,
+// concatenated into a single vector.
+func ConcatFlat(xs []ag.Node) ag.Node {
+	flat := make([]ag.Node, len(xs))
+	for i, x := range xs {
+		flat[i] = ag.Flatten(x)
+	}
+	return ag.Concat(flat...)
 }
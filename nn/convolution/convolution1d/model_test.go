@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/ag/fn"
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/mat/float"
 	"github.com/nlpodyssey/spago/nn/activation"
@@ -166,6 +167,52 @@ func testDepthwiseForward[T float.DType](t *testing.T) {
 	}, y[2].Value().Data(), 1.0e-05)
 }
 
+func TestModel_ForwardWithPaddingAndGroups(t *testing.T) {
+	t.Run("float32", testModelForwardWithPaddingAndGroups[float32])
+	t.Run("float64", testModelForwardWithPaddingAndGroups[float64])
+}
+
+func testModelForwardWithPaddingAndGroups[T float.DType](t *testing.T) {
+	model := New[T](Config{
+		KernelSizeX:    2,
+		KernelSizeY:    2,
+		YStride:        1,
+		YPadding:       1,
+		YDilation:      2,
+		PaddingMode:    fn.ReplicatePadding,
+		Groups:         2,
+		InputChannels:  2,
+		OutputChannels: 2,
+		Activation:     activation.Identity,
+	})
+	assert.Len(t, model.K, 2)
+	mat.SetData[T](model.K[0].Value(), []T{
+		0.5, -0.4,
+		0.3, 0.3,
+	})
+	mat.SetData[T](model.K[1].Value(), []T{
+		-0.5, 0.3,
+		0.2, 0.9,
+	})
+	mat.SetData[T](model.B[0].Value(), []T{0.1})
+	mat.SetData[T](model.B[1].Value(), []T{-0.2})
+
+	x1 := ag.Var(mat.NewDense(2, 4, []T{
+		0.2, 0.1, 0.5, 0.8,
+		0.4, -0.3, -0.2, -0.3,
+	})).WithGrad(true)
+
+	x2 := ag.Var(mat.NewDense(2, 4, []T{
+		-0.2, 0.1, 0.5, 0.8,
+		0.4, -0.3, -0.2, -0.9,
+	})).WithGrad(true)
+
+	y := model.Forward(x1, x2)
+
+	assert.InDeltaSlice(t, []T{0.19, 0.06, -0.35, -0.12}, y[0].Value().Data(), 1.0e-05)
+	assert.InDeltaSlice(t, []T{-0.26, -0.05, -0.88, -1.06}, y[1].Value().Data(), 1.0e-05)
+}
+
 func newTestModel[T float.DType]() *Model {
 	model := New[T](Config{
 		KernelSizeX:    2,
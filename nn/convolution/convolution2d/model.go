@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/ag/fn"
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/mat/float"
 	"github.com/nlpodyssey/spago/nn"
@@ -24,6 +25,12 @@ type Config struct {
 	KernelSizeY    int
 	XStride        int
 	YStride        int
+	XPadding       int // Number of values added at the top and at the bottom of the input
+	YPadding       int // Number of values added at the left and at the right of the input
+	XDilation      int // Step between the rows of the input covered by the kernel (default 1)
+	YDilation      int // Step between the columns of the input covered by the kernel (default 1)
+	PaddingMode    fn.PaddingMode
+	Groups         int // Number of groups of input and output channels (default 1, see fn.ConvConfig)
 	InputChannels  int
 	OutputChannels int
 	Mask           []int
@@ -48,20 +55,19 @@ func New[T float.DType](config Config) *Model {
 	if config.Mask != nil && config.InputChannels != len(config.Mask) {
 		panic(fmt.Sprintf("convolution: wrong mask size; found %d, expected %d", config.InputChannels, len(config.Mask)))
 	}
-	var paramsSize int
-	if config.DepthWise {
-		if config.OutputChannels != config.InputChannels {
-			panic("convolution: DepthWise convolution input channels must be equals to output channels")
-		}
-		paramsSize = config.OutputChannels
-	} else {
-		paramsSize = config.InputChannels * config.OutputChannels
+	if config.DepthWise && config.OutputChannels != config.InputChannels {
+		panic("convolution: DepthWise convolution input channels must be equals to output channels")
+	}
+	groups := config.groups()
+	if config.InputChannels%groups != 0 || config.OutputChannels%groups != 0 {
+		panic("convolution: input and output channels must be divisible by the groups")
 	}
+	paramsSize := config.OutputChannels * config.InputChannels / groups
 
 	kernels := make([]nn.Param, paramsSize)
 	biases := make([]nn.Param, paramsSize)
 	for i := 0; i < paramsSize; i++ {
-		requireGrad := config.Mask == nil || config.Mask[i%len(config.Mask)] == 1
+		requireGrad := !config.masked(i)
 		kernels[i] = nn.NewParam(mat.NewEmptyDense[T](config.KernelSizeX, config.KernelSizeY)).WithGrad(requireGrad)
 		biases[i] = nn.NewParam(mat.NewEmptyVecDense[T](1)).WithGrad(requireGrad)
 	}
@@ -74,26 +80,70 @@ func New[T float.DType](config Config) *Model {
 
 // Forward performs the forward step for each input node and returns the result.
 func (m *Model) Forward(xs ...ag.Node) []ag.Node {
-	ys := make([]ag.Node, m.Config.OutputChannels)
+	c := m.Config
+	rows, cols := xs[0].Value().Dims()
+	x := ag.NewTensor(convolution.ConcatFlat(xs), len(xs), rows, cols)
+	w := ag.NewTensor(m.kernels(), c.OutputChannels, c.InputChannels/c.groups(), c.KernelSizeX, c.KernelSizeY)
+	y := ag.TensorConv2D(x, w, fn.ConvConfig{
+		Stride:      []int{c.XStride, c.YStride},
+		Dilation:    []int{convolution.Dilation(c.XDilation), convolution.Dilation(c.YDilation)},
+		Padding:     []int{c.XPadding, c.YPadding},
+		PaddingMode: c.PaddingMode,
+		Groups:      c.groups(),
+	})
+
+	shape := y.Shape()
+	ys := make([]ag.Node, c.OutputChannels)
 	for i := range ys {
-		ys[i] = m.forward(xs, i)
+		out := ag.Slice(y.Node(), i*shape[1], 0, (i+1)*shape[1], shape[2])
+		ys[i] = activation.Do(c.Activation, ag.AddScalar(out, m.bias(i)))
 	}
 	return ys
 }
 
-func (m *Model) forward(xs []ag.Node, outputChannel int) ag.Node {
-	offset := outputChannel * m.Config.InputChannels
-	var out ag.Node
-	if m.Config.DepthWise {
-		out = convolution.Conv2D(m.K[outputChannel], xs[outputChannel], m.Config.XStride, m.Config.YStride)
-		out = ag.AddScalar(out, m.B[outputChannel])
-	} else {
-		for i := 0; i < len(xs); i++ {
-			if m.Config.Mask == nil || m.Config.Mask[i] == 1 {
-				out = ag.Add(out, convolution.Conv2D(m.K[i+offset], xs[i], m.Config.XStride, m.Config.YStride))
-				out = ag.AddScalar(out, m.B[i+offset])
-			}
+// kernels returns the kernels of all the channels as a single node, where
+// the kernels of the masked input channels are replaced by zeros.
+func (m *Model) kernels() ag.Node {
+	ks := make([]ag.Node, len(m.K))
+	for i, k := range m.K {
+		if m.Config.masked(i) {
+			ks[i] = ag.Var(k.Value().ZerosLike())
+			continue
+		}
+		ks[i] = k
+	}
+	return convolution.ConcatFlat(ks)
+}
+
+// bias returns the sum of the biases of the output channel, for each of the
+// input channels which are not masked.
+func (m *Model) bias(outputChannel int) ag.Node {
+	size := m.Config.InputChannels / m.Config.groups()
+	var b ag.Node
+	for i := outputChannel * size; i < (outputChannel+1)*size; i++ {
+		if !m.Config.masked(i) {
+			b = ag.Add(b, m.B[i])
 		}
 	}
-	return activation.Do(m.Config.Activation, out)
+	return b
+}
+
+// groups returns the number of groups of input and output channels.
+func (c Config) groups() int {
+	if c.DepthWise {
+		return c.InputChannels
+	}
+	return convolution.Groups(c.Groups)
+}
+
+// masked reports whether the input channel of the kernel at the given
+// index is masked.
+func (c Config) masked(kernel int) bool {
+	if c.Mask == nil {
+		return false
+	}
+	groups := c.groups()
+	size := c.InputChannels / groups
+	group := kernel / size / (c.OutputChannels / groups)
+	return c.Mask[group*size+kernel%size] == 0
 }
@@ -202,6 +202,65 @@ func testDepthwiseForward[T float.DType](t *testing.T) {
 	}, y[2].Value().Data(), 1.0e-05)
 }
 
+func TestModel_ForwardWithPaddingAndGroups(t *testing.T) {
+	t.Run("float32", testModelForwardWithPaddingAndGroups[float32])
+	t.Run("float64", testModelForwardWithPaddingAndGroups[float64])
+}
+
+func testModelForwardWithPaddingAndGroups[T float.DType](t *testing.T) {
+	model := New[T](Config{
+		KernelSizeX:    2,
+		KernelSizeY:    2,
+		XStride:        1,
+		YStride:        1,
+		XPadding:       1,
+		YPadding:       1,
+		Groups:         2,
+		InputChannels:  2,
+		OutputChannels: 2,
+		Activation:     activation.Identity,
+	})
+	assert.Len(t, model.K, 2)
+	mat.SetData[T](model.K[0].Value(), []T{
+		0.5, -0.4,
+		0.3, 0.3,
+	})
+	mat.SetData[T](model.K[1].Value(), []T{
+		-0.5, 0.3,
+		0.2, 0.9,
+	})
+	mat.SetData[T](model.B[0].Value(), []T{0.1})
+	mat.SetData[T](model.B[1].Value(), []T{-0.2})
+
+	x1 := ag.Var(mat.NewDense(3, 3, []T{
+		0.2, 0.1, 0.5,
+		0.4, -0.3, -0.2,
+		0.5, -0.6, -0.4,
+	})).WithGrad(true)
+
+	x2 := ag.Var(mat.NewDense(3, 3, []T{
+		-0.3, 0.9, 0.5,
+		0.5, -0.2, 0.8,
+		0.1, 0.3, -0.7,
+	})).WithGrad(true)
+
+	y := model.Forward(x1, x2)
+
+	assert.InDeltaSlice(t, []T{
+		0.16, 0.19, 0.28, 0.25,
+		0.14, 0.19, -0.2, 0.29,
+		0.09, 0.39, -0.27, -0.12,
+		-0.1, 0.59, -0.04, -0.1,
+	}, y[0].Value().Data(), 1.0e-05)
+
+	assert.InDeltaSlice(t, []T{
+		-0.47, 0.55, 0.43, -0.1,
+		0.16, 0.14, 0.18, -0.29,
+		0.04, -0.22, -0.43, -0.74,
+		-0.17, -0.16, -0.56, 0.15,
+	}, y[1].Value().Data(), 1.0e-05)
+}
+
 func newTestModel[T float.DType]() *Model {
 	model := New[T](Config{
 		KernelSizeX:    2,
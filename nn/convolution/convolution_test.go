@@ -139,3 +139,28 @@ func testConv2DStride2[T float.DType](t *testing.T) {
 		0.15, 0.15, 0.09, 0.09,
 	}, x.Grad().Data(), 0.005)
 }
+
+func TestGroups(t *testing.T) {
+	assert.Equal(t, 1, Groups(0))
+	assert.Equal(t, 3, Groups(3))
+}
+
+func TestDilation(t *testing.T) {
+	assert.Equal(t, 1, Dilation(0))
+	assert.Equal(t, 2, Dilation(2))
+}
+
+func TestConcatFlat(t *testing.T) {
+	t.Run("float32", testConcatFlat[float32])
+	t.Run("float64", testConcatFlat[float64])
+}
+
+func testConcatFlat[T float.DType](t *testing.T) {
+	y := ConcatFlat([]ag.Node{
+		ag.Var(mat.NewDense(2, 2, []T{1, 2, 3, 4})),
+		ag.Var(mat.NewVecDense([]T{5, 6})),
+	})
+	assert.Equal(t, 6, y.Value().Rows())
+	assert.Equal(t, 1, y.Value().Columns())
+	assert.Equal(t, []T{1, 2, 3, 4, 5, 6}, mat.Data[T](y.Value()))
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package convtranspose1d
+
+import (
+	"encoding/gob"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/nn/activation"
+	"github.com/nlpodyssey/spago/nn/convolution"
+)
+
+var _ nn.Model = &Model{}
+
+// Config provides configuration settings for a transposed convolution Model.
+//
+// The hyper-parameters are the ones of the convolution being transposed
+// (see convolution1d.Config), so that the Model maps the output of that
+// convolution back to the shape of its input: each input is a vector, and
+// each output is a matrix with KernelSizeX rows.
+type Config struct {
+	KernelSizeX    int
+	KernelSizeY    int
+	YStride        int
+	YPadding       int // Number of values removed from the left and from the right of the output
+	YOutputPadding int // Number of values added at the right of the output (see fn.ConvTransposeConfig)
+	YDilation      int // Step between the columns of the output covered by the kernel (default 1)
+	Groups         int // Number of groups of input and output channels (default 1, see fn.ConvConfig)
+	InputChannels  int
+	OutputChannels int
+	Activation     activation.Name
+}
+
+// Model contains the serializable parameters for a transposed convolutional
+// neural network model.
+type Model struct {
+	nn.Module
+	Config Config
+	// K has a kernel for each input channel and each output channel of the
+	// same group, ordered by input channel.
+	K []nn.Param `spago:"type:weights"`
+	// B has a bias for each output channel.
+	B []nn.Param `spago:"type:biases"`
+}
+
+func init() {
+	gob.Register(&Model{})
+}
+
+// New returns a new transposed convolution Model, initialized according to
+// the given configuration.
+func New[T float.DType](config Config) *Model {
+	groups := config.groups()
+	if config.InputChannels%groups != 0 || config.OutputChannels%groups != 0 {
+		panic("convtranspose1d: input and output channels must be divisible by the groups")
+	}
+	kernels := make([]nn.Param, config.InputChannels*config.OutputChannels/groups)
+	for i := range kernels {
+		kernels[i] = nn.NewParam(mat.NewEmptyDense[T](config.KernelSizeX, config.KernelSizeY))
+	}
+	biases := make([]nn.Param, config.OutputChannels)
+	for i := range biases {
+		biases[i] = nn.NewParam(mat.NewEmptyVecDense[T](1))
+	}
+	return &Model{
+		Config: config,
+		K:      kernels,
+		B:      biases,
+	}
+}
+
+// Forward performs the forward step for the input nodes, one for each input
+// channel, and returns the result, one node for each output channel.
+func (m *Model) Forward(xs ...ag.Node) []ag.Node {
+	c := m.Config
+	x := ag.NewTensor(convolution.ConcatFlat(xs), len(xs), -1)
+	// the rows of each output are the output channels of the kernels
+	w := ag.NewTensor(convolution.ConcatFlat(ag.ToNodes(m.K)), c.InputChannels, c.OutputChannels/c.groups()*c.KernelSizeX, c.KernelSizeY)
+	y := ag.TensorConvTranspose1D(x, w, fn.ConvTransposeConfig{
+		Stride:        []int{c.YStride},
+		Dilation:      []int{convolution.Dilation(c.YDilation)},
+		Padding:       []int{c.YPadding},
+		OutputPadding: []int{c.YOutputPadding},
+		Groups:        c.groups(),
+	})
+
+	length := y.Shape()[1]
+	ys := make([]ag.Node, c.OutputChannels)
+	for i := range ys {
+		out := ag.Slice(y.Node(), i*c.KernelSizeX, 0, (i+1)*c.KernelSizeX, length)
+		ys[i] = activation.Do(c.Activation, ag.AddScalar(out, m.B[i]))
+	}
+	return ys
+}
+
+// groups returns the number of groups of input and output channels.
+func (c Config) groups() int {
+	return convolution.Groups(c.Groups)
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package convtranspose1d
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn/activation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_Forward(t *testing.T) {
+	t.Run("float32", testModelForward[float32])
+	t.Run("float64", testModelForward[float64])
+}
+
+func testModelForward[T float.DType](t *testing.T) {
+	model := newTestModel[T]()
+
+	x1 := ag.Var(mat.NewVecDense([]T{0.2, 0.1, 0.5})).WithGrad(true)
+	x2 := ag.Var(mat.NewVecDense([]T{-0.3, 0.9, 0.4})).WithGrad(true)
+
+	y := model.Forward(x1, x2)
+	assert.Len(t, y, 2)
+
+	assert.Equal(t, 2, y[0].Value().Rows())
+	assert.Equal(t, 6, y[0].Value().Columns())
+	assert.InDeltaSlice(t, []T{
+		0.2, 0.02, 0.15, 0.06, 0.35, -0.1,
+		0.16, 0.16, 0.13, 0.13, 0.25, 0.25,
+	}, y[0].Value().Data(), 1.0e-06)
+
+	assert.InDeltaSlice(t, []T{
+		-0.05, -0.29, -0.65, 0.07, -0.4, -0.08,
+		-0.26, -0.47, -0.02, 0.61, -0.12, 0.16,
+	}, y[1].Value().Data(), 1.0e-06)
+
+	y[0].AccGrad(mat.NewDense(2, 6, []T{
+		0.1, -0.2, 0.3, -0.4, 0.5, -0.6,
+		0.7, -0.1, 0.2, -0.3, 0.4, -0.5,
+	}))
+	y[1].AccGrad(mat.NewDense(2, 6, []T{
+		0.6, -0.7, 0.1, -0.2, 0.3, -0.4,
+		0.5, -0.6, 0.7, -0.1, 0.2, -0.3,
+	}))
+	ag.BackwardMany(y...)
+
+	assert.InDeltaSlice(t, []T{0.31, 0.28, 0.46}, x1.Grad().Data(), 1.0e-06)
+	assert.InDeltaSlice(t, []T{-0.95, -0.06, -0.5}, x2.Grad().Data(), 1.0e-06)
+
+	assert.InDeltaSlice(t, []T{
+		0.3, -0.38,
+		0.36, -0.3,
+	}, model.K[0].Grad().Data(), 1.0e-06)
+	assert.InDeltaSlice(t, []T{
+		0.03, -0.13,
+		0.56, -0.03,
+	}, model.K[1].Grad().Data(), 1.0e-06)
+	assert.InDeltaSlice(t, []T{0.1}, model.B[0].Grad().Data(), 1.0e-06)
+	assert.InDeltaSlice(t, []T{0.1}, model.B[1].Grad().Data(), 1.0e-06)
+}
+
+func newTestModel[T float.DType]() *Model {
+	// depth-wise: each input channel is mapped to a single output channel
+	model := New[T](Config{
+		KernelSizeX:    2,
+		KernelSizeY:    2,
+		YStride:        2,
+		Groups:         2,
+		InputChannels:  2,
+		OutputChannels: 2,
+		Activation:     activation.Identity,
+	})
+	mat.SetData[T](model.K[0].Value(), []T{
+		0.5, -0.4,
+		0.3, 0.3,
+	})
+	mat.SetData[T](model.K[1].Value(), []T{
+		-0.5, 0.3,
+		0.2, 0.9,
+	})
+	mat.SetData[T](model.B[0].Value(), []T{0.1})
+	mat.SetData[T](model.B[1].Value(), []T{-0.2})
+	return model
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package convtranspose2d
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn/activation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_Forward(t *testing.T) {
+	t.Run("float32", testModelForward[float32])
+	t.Run("float64", testModelForward[float64])
+}
+
+func testModelForward[T float.DType](t *testing.T) {
+	model := newTestModel[T]()
+
+	x1 := ag.Var(mat.NewDense(2, 2, []T{
+		0.2, 0.1,
+		0.4, -0.3,
+	})).WithGrad(true)
+
+	x2 := ag.Var(mat.NewDense(2, 2, []T{
+		-0.2, 0.5,
+		0.4, -0.9,
+	})).WithGrad(true)
+
+	y := model.Forward(x1, x2)
+	assert.Len(t, y, 2)
+
+	assert.Equal(t, 4, y[0].Value().Rows())
+	assert.Equal(t, 3, y[0].Value().Columns())
+	assert.InDeltaSlice(t, []T{
+		0.12, 0.21, 0.21,
+		0.12, 0.17, 0.43,
+		0.46, -0.45, -0.05,
+		0.3, 0.19, -0.53,
+	}, y[0].Value().Data(), 1.0e-06)
+
+	assert.InDeltaSlice(t, []T{
+		-0.38, -0.15, 0.23,
+		0.02, -0.53, 0.09,
+		-0.24, 0.03, -1.01,
+		-0.48, 1.07, -0.83,
+	}, y[1].Value().Data(), 1.0e-06)
+
+	y[0].AccGrad(mat.NewDense(4, 3, []T{
+		0.1, -0.2, 0.3,
+		-0.4, 0.5, -0.6,
+		0.7, -0.1, 0.2,
+		-0.3, 0.4, -0.5,
+	}))
+	y[1].AccGrad(mat.NewDense(4, 3, []T{
+		0.6, -0.7, 0.1,
+		-0.2, 0.3, -0.4,
+		0.5, -0.6, 0.7,
+		-0.1, 0.2, -0.3,
+	}))
+	ag.BackwardMany(y...)
+
+	assert.InDeltaSlice(t, []T{-0.12, -0.17, 0.15, 0.12}, x1.Grad().Data(), 1.0e-06)
+	assert.InDeltaSlice(t, []T{0.18, -0.88, 0.32, -0.18}, x2.Grad().Data(), 1.0e-06)
+
+	expectedKernelGrads := [][]T{
+		{0.31, -0.11, -0.27, 0.35},
+		{0.43, -0.58, -0.11, 0.19},
+		{0.25, -0.03, -0.15, 0.21},
+		{0.27, -0.68, -0.03, 0.09},
+	}
+	for i, k := range model.K {
+		assert.InDeltaSlice(t, expectedKernelGrads[i], k.Grad().Data(), 1.0e-06)
+	}
+	assert.InDeltaSlice(t, []T{0.1}, model.B[0].Grad().Data(), 1.0e-06)
+	assert.InDeltaSlice(t, []T{0.1}, model.B[1].Grad().Data(), 1.0e-06)
+}
+
+func TestNew(t *testing.T) {
+	model := New[float64](Config{
+		KernelSizeX:    3,
+		KernelSizeY:    2,
+		InputChannels:  4,
+		OutputChannels: 6,
+		Groups:         2,
+	})
+	assert.Len(t, model.K, 12)
+	assert.Len(t, model.B, 6)
+	assert.Equal(t, 3, model.K[0].Value().Rows())
+	assert.Equal(t, 2, model.K[0].Value().Columns())
+
+	assert.Panics(t, func() {
+		New[float64](Config{InputChannels: 3, OutputChannels: 2, Groups: 2})
+	})
+}
+
+func newTestModel[T float.DType]() *Model {
+	model := New[T](Config{
+		KernelSizeX:    2,
+		KernelSizeY:    2,
+		XStride:        2,
+		YStride:        1,
+		InputChannels:  2,
+		OutputChannels: 2,
+		Activation:     activation.Identity,
+	})
+	kernels := [][]T{
+		{0.5, -0.4, 0.3, 0.3},
+		{-0.5, 0.3, 0.2, 0.9},
+		{0.4, 0.3, 0.2, 0.6},
+		{0.4, 0.8, -0.9, 0.4},
+	}
+	for i, k := range kernels {
+		mat.SetData[T](model.K[i].Value(), k)
+	}
+	mat.SetData[T](model.B[0].Value(), []T{0.1})
+	mat.SetData[T](model.B[1].Value(), []T{-0.2})
+	return model
+}
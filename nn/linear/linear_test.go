@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/ag/agtest"
 	"github.com/nlpodyssey/spago/losses"
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/mat/float"
@@ -67,6 +68,23 @@ func testModelForward[T float.DType](t *testing.T) {
 	}, model.B.Grad().Data(), 1.0e-05)
 }
 
+func TestModel_Gradients(t *testing.T) {
+	t.Run("float32", testModelGradients[float32])
+	t.Run("float64", testModelGradients[float64])
+}
+
+func testModelGradients[T float.DType](t *testing.T) {
+	m := &testLinearWithActivationModel{
+		M1: newTestModel[T](),
+		M2: activation.New(activation.Tanh),
+	}
+	f := func(xs ...ag.Node) ag.Node {
+		return m.forward(xs[0])
+	}
+	x := mat.NewVecDense([]T{-0.8, -0.9, -0.9, 1.0})
+	agtest.AssertModelGrad(t, m, f, x)
+}
+
 func newTestModel[T float.DType]() *Model {
 	model := New[T](4, 5)
 	mat.SetData[T](model.W.Value(), []T{
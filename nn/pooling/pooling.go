@@ -6,22 +6,69 @@ package pooling
 
 import (
 	"encoding/gob"
+	"fmt"
 
 	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/ag/fn"
 	"github.com/nlpodyssey/spago/nn"
 )
 
-var _ nn.Model = &MaxPooling{}
+var (
+	_ nn.Model = &MaxPooling{}
+	_ nn.Model = &AvgPooling{}
+	_ nn.Model = &AdaptivePooling{}
+	_ nn.Model = &GlobalPooling{}
+)
 
 // MaxPooling is a parameter-free model used to instantiate a new Processor.
+// The strides default to the size of the window, that is non-overlapping
+// windows. The windows must cover the whole input, unless Truncate is true
+// (see fn.PoolingConfig).
 type MaxPooling struct {
 	nn.Module
+	Rows         int
+	Columns      int
+	RowStride    int
+	ColumnStride int
+	Truncate     bool
+}
+
+// AvgPooling is a parameter-free model which averages the values within
+// each window. The strides default to the size of the window, that is
+// non-overlapping windows. The windows must cover the whole input, unless
+// Truncate is true (see fn.PoolingConfig).
+type AvgPooling struct {
+	nn.Module
+	Rows         int
+	Columns      int
+	RowStride    int
+	ColumnStride int
+	Truncate     bool
+}
+
+// AdaptivePooling is a parameter-free model which pools each input into a
+// matrix of a fixed size, regardless of the size of the input (see
+// fn.NewAdaptivePooling).
+type AdaptivePooling struct {
+	nn.Module
+	Mode    fn.PoolingMode
 	Rows    int
 	Columns int
 }
 
+// GlobalPooling is a parameter-free model which pools a sequence of nodes
+// into a single node, taking the element-wise maximum or average across the
+// sequence.
+type GlobalPooling struct {
+	nn.Module
+	Mode fn.PoolingMode
+}
+
 func init() {
 	gob.Register(&MaxPooling{})
+	gob.Register(&AvgPooling{})
+	gob.Register(&AdaptivePooling{})
+	gob.Register(&GlobalPooling{})
 }
 
 // NewMax returns a new model.
@@ -32,11 +79,124 @@ func NewMax(rows, columns int) *MaxPooling {
 	}
 }
 
+// WithStride sets the strides of the windows and returns the model.
+func (m *MaxPooling) WithStride(rows, columns int) *MaxPooling {
+	m.RowStride = rows
+	m.ColumnStride = columns
+	return m
+}
+
+// WithTruncation sets whether the last rows and columns of the input which
+// do not fill a window are discarded, and returns the model.
+func (m *MaxPooling) WithTruncation(value bool) *MaxPooling {
+	m.Truncate = value
+	return m
+}
+
 // Forward performs the forward step for each input node and returns the result.
 // The max pooling is applied independently to each input.
 func (m *MaxPooling) Forward(xs ...ag.Node) []ag.Node {
 	pooled := func(x ag.Node) ag.Node {
-		return ag.MaxPooling(x, m.Rows, m.Columns)
+		return ag.Pooling(x, fn.PoolingConfig{
+			Mode:         fn.MaxPool,
+			Rows:         m.Rows,
+			Columns:      m.Columns,
+			RowStride:    m.RowStride,
+			ColumnStride: m.ColumnStride,
+			Truncate:     m.Truncate,
+		})
+	}
+	return ag.Map(pooled, xs)
+}
+
+// NewAvg returns a new AvgPooling model.
+func NewAvg(rows, columns int) *AvgPooling {
+	return &AvgPooling{
+		Rows:    rows,
+		Columns: columns,
+	}
+}
+
+// WithStride sets the strides of the windows and returns the model.
+func (m *AvgPooling) WithStride(rows, columns int) *AvgPooling {
+	m.RowStride = rows
+	m.ColumnStride = columns
+	return m
+}
+
+// WithTruncation sets whether the last rows and columns of the input which
+// do not fill a window are discarded, and returns the model.
+func (m *AvgPooling) WithTruncation(value bool) *AvgPooling {
+	m.Truncate = value
+	return m
+}
+
+// Forward performs the forward step for each input node and returns the result.
+// The average pooling is applied independently to each input.
+func (m *AvgPooling) Forward(xs ...ag.Node) []ag.Node {
+	pooled := func(x ag.Node) ag.Node {
+		return ag.Pooling(x, fn.PoolingConfig{
+			Mode:         fn.AvgPool,
+			Rows:         m.Rows,
+			Columns:      m.Columns,
+			RowStride:    m.RowStride,
+			ColumnStride: m.ColumnStride,
+			Truncate:     m.Truncate,
+		})
+	}
+	return ag.Map(pooled, xs)
+}
+
+// NewAdaptiveMax returns a new AdaptivePooling model taking the maximum
+// value of each window.
+func NewAdaptiveMax(rows, columns int) *AdaptivePooling {
+	return &AdaptivePooling{
+		Mode:    fn.MaxPool,
+		Rows:    rows,
+		Columns: columns,
+	}
+}
+
+// NewAdaptiveAvg returns a new AdaptivePooling model taking the average
+// value of each window.
+func NewAdaptiveAvg(rows, columns int) *AdaptivePooling {
+	return &AdaptivePooling{
+		Mode:    fn.AvgPool,
+		Rows:    rows,
+		Columns: columns,
+	}
+}
+
+// Forward performs the forward step for each input node and returns the result.
+// The adaptive pooling is applied independently to each input.
+func (m *AdaptivePooling) Forward(xs ...ag.Node) []ag.Node {
+	pooled := func(x ag.Node) ag.Node {
+		return ag.AdaptivePooling(x, m.Mode, m.Rows, m.Columns)
 	}
 	return ag.Map(pooled, xs)
 }
+
+// NewGlobalMax returns a new GlobalPooling model taking the element-wise
+// maximum of the inputs.
+func NewGlobalMax() *GlobalPooling {
+	return &GlobalPooling{Mode: fn.MaxPool}
+}
+
+// NewGlobalAvg returns a new GlobalPooling model taking the element-wise
+// average of the inputs.
+func NewGlobalAvg() *GlobalPooling {
+	return &GlobalPooling{Mode: fn.AvgPool}
+}
+
+// Forward performs the forward step for the input nodes, which must have
+// the same shape, and returns a single node.
+func (m *GlobalPooling) Forward(xs ...ag.Node) []ag.Node {
+	switch m.Mode {
+	case fn.MaxPool:
+		return []ag.Node{ag.Maximum(xs)}
+	case fn.AvgPool:
+		return []ag.Node{ag.Mean(xs)}
+	default:
+		panic(fmt.Sprintf("pooling: invalid mode %d", m.Mode))
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pooling
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPooling_Forward(t *testing.T) {
+	t.Run("float32", testPoolingForward[float32])
+	t.Run("float64", testPoolingForward[float64])
+}
+
+func testPoolingForward[T float.DType](t *testing.T) {
+	newX := func() ag.Node {
+		return ag.Var(mat.NewDense(4, 4, []T{
+			0.4, 0.1, -0.9, -0.5,
+			-0.4, 0.3, 0.7, -0.3,
+			0.8, 0.2, -0.6, 0.7,
+			0.2, -0.1, 0.6, -0.2,
+		})).WithGrad(true)
+	}
+
+	t.Run("max", func(t *testing.T) {
+		x := newX()
+		y := NewMax(2, 2).Forward(x)
+		assert.InDeltaSlice(t, []T{
+			0.4, 0.7,
+			0.8, 0.7,
+		}, y[0].Value().Data(), 1.0e-6)
+
+		y = NewMax(2, 2).WithStride(2, 1).Forward(x)
+		assert.InDeltaSlice(t, []T{
+			0.4, 0.7, 0.7,
+			0.8, 0.6, 0.7,
+		}, y[0].Value().Data(), 1.0e-6)
+
+		ag.Backward(y[0])
+		assert.InDeltaSlice(t, []T{
+			1, 0, 0, 0,
+			0, 0, 2, 0,
+			1, 0, 0, 1,
+			0, 0, 1, 0,
+		}, x.Grad().Data(), 1.0e-6)
+	})
+
+	t.Run("truncated max", func(t *testing.T) {
+		x := newX()
+		y := NewMax(3, 3).WithTruncation(true).Forward(x)
+		assert.Equal(t, 1, y[0].Value().Rows())
+		assert.Equal(t, 1, y[0].Value().Columns())
+		assert.InDeltaSlice(t, []T{0.8}, y[0].Value().Data(), 1.0e-6)
+	})
+
+	t.Run("average", func(t *testing.T) {
+		x := newX()
+		y := NewAvg(2, 2).Forward(x)
+		assert.InDeltaSlice(t, []T{
+			0.1, -0.25,
+			0.275, 0.125,
+		}, y[0].Value().Data(), 1.0e-6)
+
+		y = NewAvg(4, 3).WithStride(1, 1).Forward(x)
+		assert.Equal(t, 1, y[0].Value().Rows())
+		assert.Equal(t, 2, y[0].Value().Columns())
+		assert.InDeltaSlice(t, []T{0.108333, 0}, y[0].Value().Data(), 1.0e-6)
+
+		y = NewAvg(3, 4).WithTruncation(true).Forward(x)
+		assert.InDeltaSlice(t, []T{0.041667}, y[0].Value().Data(), 1.0e-6)
+	})
+
+	t.Run("adaptive", func(t *testing.T) {
+		x := newX()
+		y := NewAdaptiveMax(1, 3).Forward(x)
+		assert.InDeltaSlice(t, []T{0.8, 0.7, 0.7}, y[0].Value().Data(), 1.0e-6)
+
+		y = NewAdaptiveAvg(1, 1).Forward(x)
+		assert.InDeltaSlice(t, []T{0.0625}, y[0].Value().Data(), 1.0e-6)
+
+		ag.Backward(y[0])
+		assert.InDeltaSlice(t, []T{
+			0.0625, 0.0625, 0.0625, 0.0625,
+			0.0625, 0.0625, 0.0625, 0.0625,
+			0.0625, 0.0625, 0.0625, 0.0625,
+			0.0625, 0.0625, 0.0625, 0.0625,
+		}, x.Grad().Data(), 1.0e-6)
+	})
+
+	t.Run("global", func(t *testing.T) {
+		x1 := ag.Var(mat.NewVecDense([]T{0.1, -0.2, 0.3})).WithGrad(true)
+		x2 := ag.Var(mat.NewVecDense([]T{0.4, -0.5, -0.6})).WithGrad(true)
+		x3 := ag.Var(mat.NewVecDense([]T{-0.7, 0.8, 0.9})).WithGrad(true)
+
+		y := NewGlobalMax().Forward(x1, x2, x3)
+		assert.Len(t, y, 1)
+		assert.InDeltaSlice(t, []T{0.4, 0.8, 0.9}, y[0].Value().Data(), 1.0e-6)
+
+		y = NewGlobalAvg().Forward(x1, x2, x3)
+		assert.Len(t, y, 1)
+		assert.InDeltaSlice(t, []T{-0.066667, 0.033333, 0.2}, y[0].Value().Data(), 1.0e-6)
+
+		ag.Backward(y[0])
+		assert.InDeltaSlice(t, []T{0.333333, 0.333333, 0.333333}, x1.Grad().Data(), 1.0e-6)
+	})
+}
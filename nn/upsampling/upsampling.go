@@ -0,0 +1,107 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package upsampling provides parameter-free models which increase the size
+// of the inputs, as in the decoders of convolutional neural networks.
+package upsampling
+
+import (
+	"encoding/gob"
+	"fmt"
+	"math"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/nn"
+)
+
+var _ nn.Model = &Model{}
+
+// Mode is the interpolation method used to compute the upsampled values.
+type Mode int
+
+const (
+	// Nearest repeats each value of the input.
+	Nearest Mode = iota
+	// Bilinear interpolates linearly between the two nearest values of the
+	// input, along the rows and along the columns. The values are taken at
+	// the centers of the elements (i.e. without aligning the corners), and
+	// the values at the edges are replicated.
+	Bilinear
+)
+
+// Model is a parameter-free model which upsamples each input matrix,
+// multiplying its rows by XScale and its columns by YScale.
+type Model struct {
+	nn.Module
+	XScale int
+	YScale int
+	Mode   Mode
+}
+
+func init() {
+	gob.Register(&Model{})
+}
+
+// New returns a new model.
+func New(xScale, yScale int, mode Mode) *Model {
+	if xScale <= 0 || yScale <= 0 {
+		panic(fmt.Sprintf("upsampling: invalid scale factors %d and %d", xScale, yScale))
+	}
+	return &Model{
+		XScale: xScale,
+		YScale: yScale,
+		Mode:   mode,
+	}
+}
+
+// Forward performs the forward step for each input node and returns the result.
+// The upsampling is applied independently to each input.
+func (m *Model) Forward(xs ...ag.Node) []ag.Node {
+	return ag.Map(m.upsample, xs)
+}
+
+// upsample returns the input x upsampled as r·x·cᵀ, where r and c are the
+// interpolation matrices of the rows and of the columns.
+func (m *Model) upsample(x ag.Node) ag.Node {
+	rows, cols := x.Value().Dims()
+	y := x
+	if m.YScale != 1 {
+		c := m.interpolation(x.Value(), cols, m.YScale)
+		y = ag.Mul(y, ag.T(c))
+	}
+	if m.XScale != 1 {
+		r := m.interpolation(x.Value(), rows, m.XScale)
+		y = ag.Mul(r, y)
+	}
+	return y
+}
+
+// interpolation returns a constant (size·scale)×size matrix, of the same type
+// of x, whose rows have the coefficients of the input values combined into
+// each upsampled value.
+func (m *Model) interpolation(x mat.Matrix, size, scale int) ag.Node {
+	n := size * scale
+	data := make([]float64, n*size)
+	for i := 0; i < n; i++ {
+		switch m.Mode {
+		case Nearest:
+			data[i*size+i/scale] = 1
+		case Bilinear:
+			src := math.Max((float64(i)+0.5)/float64(scale)-0.5, 0)
+			i0 := int(src)
+			i1 := i0 + 1
+			if i1 >= size {
+				i1 = size - 1
+			}
+			lambda := src - float64(i0)
+			data[i*size+i0] += 1 - lambda
+			data[i*size+i1] += lambda
+		default:
+			panic(fmt.Sprintf("upsampling: invalid mode %d", m.Mode))
+		}
+	}
+	return ag.Var(x.NewMatrix(n, size, float.SliceInterface(data)))
+}
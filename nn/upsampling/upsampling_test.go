@@ -0,0 +1,83 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package upsampling
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_Forward(t *testing.T) {
+	t.Run("float32", testModelForward[float32])
+	t.Run("float64", testModelForward[float64])
+}
+
+func testModelForward[T float.DType](t *testing.T) {
+	t.Run("nearest", func(t *testing.T) {
+		x := ag.Var(mat.NewDense(2, 3, []T{
+			1, 2, 3,
+			4, 5, 6,
+		})).WithGrad(true)
+
+		y := New(2, 1, Nearest).Forward(x)
+		assert.Equal(t, 4, y[0].Value().Rows())
+		assert.Equal(t, 3, y[0].Value().Columns())
+		assert.Equal(t, []T{
+			1, 2, 3,
+			1, 2, 3,
+			4, 5, 6,
+			4, 5, 6,
+		}, mat.Data[T](y[0].Value()))
+
+		y = New(2, 2, Nearest).Forward(x)
+		assert.Equal(t, []T{
+			1, 1, 2, 2, 3, 3,
+			1, 1, 2, 2, 3, 3,
+			4, 4, 5, 5, 6, 6,
+			4, 4, 5, 5, 6, 6,
+		}, mat.Data[T](y[0].Value()))
+
+		ag.Backward(y[0])
+		assert.Equal(t, []T{4, 4, 4, 4, 4, 4}, mat.Data[T](x.Grad()))
+	})
+
+	t.Run("bilinear", func(t *testing.T) {
+		x := ag.Var(mat.NewDense(2, 2, []T{
+			1, 2,
+			3, 4,
+		})).WithGrad(true)
+
+		y := New(2, 2, Bilinear).Forward(x)
+		assert.Equal(t, 4, y[0].Value().Rows())
+		assert.Equal(t, 4, y[0].Value().Columns())
+		assert.InDeltaSlice(t, []T{
+			1, 1.25, 1.75, 2,
+			1.5, 1.75, 2.25, 2.5,
+			2.5, 2.75, 3.25, 3.5,
+			3, 3.25, 3.75, 4,
+		}, y[0].Value().Data(), 1.0e-6)
+
+		y[0].AccGrad(mat.NewDense(4, 4, []T{
+			1, 0, 0, 0,
+			0, 0, 0, 0,
+			0, 0, 0, 0,
+			0, 0, 0, 0,
+		}))
+		ag.Backward(y[0])
+		assert.InDeltaSlice(t, []T{1, 0, 0, 0}, x.Grad().Data(), 1.0e-6)
+
+		y = New(3, 1, Bilinear).Forward(ag.Var(mat.NewVecDense([]T{0, 3})))
+		assert.InDeltaSlice(t, []T{0, 0, 1, 2, 3, 3}, y[0].Value().Data(), 1.0e-6)
+	})
+}
+
+func TestNew(t *testing.T) {
+	assert.Panics(t, func() { New(0, 2, Nearest) })
+	assert.Panics(t, func() { New(2, -1, Bilinear) })
+}